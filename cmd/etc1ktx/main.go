@@ -0,0 +1,119 @@
+// Command etc1ktx converts images to ETC1 compressed KTX files, for loading
+// with texture.LoadCompressed. ETC1 has no alpha, so transparency is lost.
+//
+//	etc1ktx [-mipmap=false] [-flip=false] [-pkm] [-o out.ktx] image.png ...
+package main
+
+import (
+	"flag"
+	"fmt"
+	"image"
+	"image/draw"
+	_ "image/jpeg"
+	_ "image/png"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/FEEDFACE-COM/piglet/texture/etc1"
+)
+
+var (
+	mipmap = flag.Bool("mipmap", true, "encode all mip levels down to 1x1")
+	flip   = flag.Bool("flip", true, "flip rows to GL orientation, with the first row at the bottom")
+	pkm    = flag.Bool("pkm", false, "write PKM instead of KTX, without mip levels")
+	output = flag.String("o", "", "output file, for a single input; default is the input with .ktx or .pkm")
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: %s [flags] image ...\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 || (*output != "" && flag.NArg() > 1) {
+		flag.Usage()
+		os.Exit(2)
+	}
+	failed := false
+	for _, name := range flag.Args() {
+		if err := convert(name); err != nil {
+			fmt.Fprintf(os.Stderr, "etc1ktx: %s\n", err)
+			failed = true
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+func convert(name string) error {
+	in, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	img, _, err := image.Decode(in)
+	in.Close()
+	if err != nil {
+		return fmt.Errorf("fail to decode %s: %s", name, err)
+	}
+	if *flip {
+		img = flipY(img)
+	}
+
+	f := etc1.EncodeFile(img, *mipmap && !*pkm)
+	ext := ".ktx"
+	if *pkm {
+		ext = ".pkm"
+	} else if *flip {
+		f.KeyValues["KTXorientation"] = "S=r,T=u"
+	} else {
+		f.KeyValues["KTXorientation"] = "S=r,T=d"
+	}
+
+	outName := *output
+	if outName == "" {
+		outName = strings.TrimSuffix(name, filepath.Ext(name)) + ext
+	}
+	out, err := os.Create(outName)
+	if err != nil {
+		return err
+	}
+	if *pkm {
+		err = etc1.WritePKM(out, f)
+	} else {
+		err = etc1.WriteKTX(out, f)
+	}
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return fmt.Errorf("fail to write %s: %s", outName, err)
+	}
+
+	size := 0
+	for _, data := range f.Levels {
+		size += len(data)
+	}
+	fmt.Printf("%s: %dx%d, %d levels, %d bytes, %d as RGBA\n", outName, f.Width, f.Height, len(f.Levels), size, f.Width*f.Height*4)
+	if opaque, ok := img.(interface{ Opaque() bool }); ok && !opaque.Opaque() {
+		fmt.Fprintf(os.Stderr, "etc1ktx: %s has transparency, which ETC1 drops\n", name)
+	}
+	return nil
+}
+
+// Return img upside down, as GL expects the first row at the bottom
+func flipY(img image.Image) image.Image {
+	b := img.Bounds()
+	ret := image.NewNRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(ret, ret.Bounds(), img, b.Min, draw.Src)
+	row := make([]uint8, ret.Stride)
+	for top, bottom := 0, b.Dy()-1; top < bottom; top, bottom = top+1, bottom-1 {
+		a := ret.Pix[top*ret.Stride : (top+1)*ret.Stride]
+		c := ret.Pix[bottom*ret.Stride : (bottom+1)*ret.Stride]
+		copy(row, a)
+		copy(a, c)
+		copy(c, row)
+	}
+	return ret
+}
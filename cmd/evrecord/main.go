@@ -0,0 +1,146 @@
+// Command evrecord lists input devices, prints their events, and records
+// them for replaying with input.OpenRecording, eg. in tests:
+//
+//	evrecord                                  list devices
+//	evrecord -print /dev/input/event0         print decoded events
+//	evrecord -o touch.ev /dev/input/event0    record to touch.ev and touch.ev.json
+//	evrecord -print touch.ev                  print a recording
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/FEEDFACE-COM/piglet/input"
+)
+
+var (
+	output      = flag.String("o", "", "record raw events to file, and the device to file.json")
+	printEvents = flag.Bool("print", false, "print decoded events")
+	duration    = flag.Duration("t", 0, "stop after duration, default at interrupt")
+	width       = flag.Int("width", 800, "display width touches are mapped to")
+	height      = flag.Int("height", 480, "display height touches are mapped to")
+	rotation    = flag.Int("rotate", 0, "rotation of the touchscreen, clockwise in degrees")
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: %s [flags] [device]\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	var err error
+	switch {
+	case flag.NArg() == 0:
+		err = list()
+	case flag.NArg() == 1 && (*output != "" || *printEvents):
+		err = run(flag.Arg(0))
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func list() error {
+	paths, _ := filepath.Glob("/dev/input/event*")
+	sort.Strings(paths)
+	if len(paths) == 0 {
+		return fmt.Errorf("fail to list input devices: none in /dev/input")
+	}
+	for _, path := range paths {
+		dev, err := input.OpenDevice(path)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			continue
+		}
+		fmt.Printf("%s\t%04x:%04x\t%s\t%s\n", path, dev.ID.Vendor, dev.ID.Product, dev.Capabilities, dev.Name)
+		dev.Close()
+	}
+	return nil
+}
+
+func run(path string) error {
+	var dev *input.Device
+	var err error
+	if _, statErr := os.Stat(path + ".json"); statErr == nil {
+		dev, err = input.OpenRecording(path)
+	} else {
+		dev, err = input.OpenDevice(path)
+	}
+	if err != nil {
+		return err
+	}
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt)
+	if *duration > 0 {
+		go func() {
+			time.Sleep(*duration)
+			stop <- os.Interrupt
+		}()
+	}
+	if *output != "" {
+		return record(dev, *output, stop)
+	}
+	in := input.New(input.Options{Display: input.Display{Width: *width, Height: *height, Rotation: input.Rotation(*rotation)}})
+	defer in.Close()
+	if err := in.Add(dev); err != nil {
+		return err
+	}
+	for {
+		select {
+		case e, ok := <-in.Events():
+			if !ok {
+				return nil
+			}
+			fmt.Printf("%s %v\n", e.Source().Time.Format("15:04:05.000000"), e)
+		case <-stop:
+			return nil
+		}
+	}
+}
+
+func record(dev *input.Device, path string, stop chan os.Signal) error {
+	desc, err := json.MarshalIndent(dev.Recording(), "", "\t")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path+".json", desc, 0644); err != nil {
+		return fmt.Errorf("fail to write recording description: %v", err)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("fail to create recording: %v", err)
+	}
+	defer f.Close()
+	go func() {
+		<-stop
+		dev.Close()
+	}()
+	fmt.Fprintf(os.Stderr, "recording %s to %s, interrupt to stop\n", dev, path)
+	events := make([]input.RawEvent, 64)
+	count := 0
+	for {
+		n, err := dev.ReadEvents(events)
+		if n > 0 {
+			if err := input.WriteEvents(f, events[:n]...); err != nil {
+				return fmt.Errorf("fail to write recording: %v", err)
+			}
+			count += n
+		}
+		if err != nil {
+			break
+		}
+	}
+	fmt.Fprintf(os.Stderr, "recorded %d events\n", count)
+	return f.Close()
+}
@@ -0,0 +1,72 @@
+// Command sdffont generates the distance field glyphs of a TrueType or
+// OpenType font ahead of time, for text.OpenSDF and text.NewSDFFont, so
+// programs do not spend time generating them at startup:
+//
+//	sdffont [-size 48] [-spread 6] [-runes chars] [-o out.png] font.ttf
+//
+// writes the glyphs to font.png, and their metrics to font.png.json.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/FEEDFACE-COM/piglet/text"
+)
+
+var (
+	size   = flag.Float64("size", 48, "pixels per em to generate glyphs at")
+	spread = flag.Float64("spread", 0, "pixels the field reaches out from edges, limiting outlines and shadows; default size/8")
+	runes  = flag.String("runes", "", "characters to generate; default printable ASCII and Latin-1")
+	output = flag.String("o", "", "output file; default is the font with .png")
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: %s [flags] font.ttf\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err := generate(flag.Arg(0)); err != nil {
+		fmt.Fprintf(os.Stderr, "sdffont: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+func generate(name string) error {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return err
+	}
+	chars := *runes
+	if chars == "" {
+		var b strings.Builder
+		for r := rune(0x20); r < 0x7f; r++ {
+			b.WriteRune(r)
+		}
+		for r := rune(0xa0); r <= 0xff; r++ {
+			b.WriteRune(r)
+		}
+		chars = b.String()
+	}
+	s, err := text.GenerateSDF(data, chars, text.SDFOptions{Size: *size, Spread: *spread})
+	if err != nil {
+		return fmt.Errorf("fail to generate glyphs of %s: %s", name, err)
+	}
+	outName := *output
+	if outName == "" {
+		outName = strings.TrimSuffix(name, filepath.Ext(name)) + ".png"
+	}
+	if err := s.Write(outName); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "%d glyphs in %dx%d to %s\n", len(s.Glyphs), s.Image.Bounds().Dx(), s.Image.Bounds().Dy(), outName)
+	return nil
+}
@@ -0,0 +1,3 @@
+package egl
+
+//go:generate go run ../internal/glowgen -api=egl
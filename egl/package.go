@@ -0,0 +1,774 @@
+// Code generated by glowgen. DO NOT EDIT.
+
+// Copyright (c) 2013-2017 The Khronos Group Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and/or associated documentation files (the
+// "Materials"), to deal in the Materials without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Materials, and to
+// permit persons to whom the Materials are furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Materials.
+
+// Package egl implements Go bindings to EGL.
+//
+// This package was generated from the Khronos XML API registry by glowgen,
+// a cut-down port of Glow (https://github.com/go-gl/glow).
+package egl
+
+// #cgo linux,arm  CFLAGS: -I/opt/vc/include
+// #cgo linux,arm LDFLAGS: -L/opt/vc/lib
+// #cgo CFLAGS: -DEGL_NO_PLATFORM_SPECIFIC_TYPES
+//
+// #ifndef EGLAPIENTRY
+// #define EGLAPIENTRY
+// #endif
+// #ifndef EGLAPIENTRYP
+// #define EGLAPIENTRYP EGLAPIENTRY *
+// #endif
+// #include <KHR/khrplatform.h>
+// #include <EGL/eglplatform.h>
+// typedef unsigned int EGLBoolean;
+// typedef unsigned int EGLenum;
+// typedef void *EGLClientBuffer;
+// typedef void *EGLConfig;
+// typedef void *EGLContext;
+// typedef void *EGLDisplay;
+// typedef void *EGLImageKHR;
+// typedef void *EGLSurface;
+// typedef void *EGLSyncKHR;
+// typedef khronos_utime_nanoseconds_t EGLTimeKHR;
+// typedef void (*__eglMustCastToProperFunctionPointerType)(void);
+// typedef EGLBoolean  (EGLAPIENTRYP GPBINDAPI)(EGLenum  api);
+// typedef EGLBoolean  (EGLAPIENTRYP GPBINDTEXIMAGE)(EGLDisplay  dpy, EGLSurface  surface, EGLint  buffer);
+// typedef EGLBoolean  (EGLAPIENTRYP GPCHOOSECONFIG)(EGLDisplay  dpy, const EGLint * attrib_list, EGLConfig * configs, EGLint  config_size, EGLint * num_config);
+// typedef EGLint  (EGLAPIENTRYP GPCLIENTWAITSYNCKHR)(EGLDisplay  dpy, EGLSyncKHR  sync, EGLint  flags, EGLTimeKHR  timeout);
+// typedef EGLBoolean  (EGLAPIENTRYP GPCOPYBUFFERS)(EGLDisplay  dpy, EGLSurface  surface, EGLNativePixmapType  target);
+// typedef EGLContext  (EGLAPIENTRYP GPCREATECONTEXT)(EGLDisplay  dpy, EGLConfig  config, EGLContext  share_context, const EGLint * attrib_list);
+// typedef EGLImageKHR  (EGLAPIENTRYP GPCREATEIMAGEKHR)(EGLDisplay  dpy, EGLContext  ctx, EGLenum  target, EGLClientBuffer  buffer, const EGLint * attrib_list);
+// typedef EGLSurface  (EGLAPIENTRYP GPCREATEPBUFFERFROMCLIENTBUFFER)(EGLDisplay  dpy, EGLenum  buftype, EGLClientBuffer  buffer, EGLConfig  config, const EGLint * attrib_list);
+// typedef EGLSurface  (EGLAPIENTRYP GPCREATEPBUFFERSURFACE)(EGLDisplay  dpy, EGLConfig  config, const EGLint * attrib_list);
+// typedef EGLSurface  (EGLAPIENTRYP GPCREATEPIXMAPSURFACE)(EGLDisplay  dpy, EGLConfig  config, EGLNativePixmapType  pixmap, const EGLint * attrib_list);
+// typedef EGLSyncKHR  (EGLAPIENTRYP GPCREATESYNCKHR)(EGLDisplay  dpy, EGLenum  type, const EGLint * attrib_list);
+// typedef EGLSurface  (EGLAPIENTRYP GPCREATEWINDOWSURFACE)(EGLDisplay  dpy, EGLConfig  config, EGLNativeWindowType  win, const EGLint * attrib_list);
+// typedef EGLBoolean  (EGLAPIENTRYP GPDESTROYCONTEXT)(EGLDisplay  dpy, EGLContext  ctx);
+// typedef EGLBoolean  (EGLAPIENTRYP GPDESTROYIMAGEKHR)(EGLDisplay  dpy, EGLImageKHR  image);
+// typedef EGLBoolean  (EGLAPIENTRYP GPDESTROYSURFACE)(EGLDisplay  dpy, EGLSurface  surface);
+// typedef EGLBoolean  (EGLAPIENTRYP GPDESTROYSYNCKHR)(EGLDisplay  dpy, EGLSyncKHR  sync);
+// typedef EGLBoolean  (EGLAPIENTRYP GPGETCONFIGATTRIB)(EGLDisplay  dpy, EGLConfig  config, EGLint  attribute, EGLint * value);
+// typedef EGLBoolean  (EGLAPIENTRYP GPGETCONFIGS)(EGLDisplay  dpy, EGLConfig * configs, EGLint  config_size, EGLint * num_config);
+// typedef EGLContext  (EGLAPIENTRYP GPGETCURRENTCONTEXT)();
+// typedef EGLDisplay  (EGLAPIENTRYP GPGETCURRENTDISPLAY)();
+// typedef EGLSurface  (EGLAPIENTRYP GPGETCURRENTSURFACE)(EGLint  readdraw);
+// typedef EGLDisplay  (EGLAPIENTRYP GPGETDISPLAY)(EGLNativeDisplayType  display_id);
+// typedef EGLint  (EGLAPIENTRYP GPGETERROR)();
+// typedef __eglMustCastToProperFunctionPointerType  (EGLAPIENTRYP GPGETPROCADDRESS)(const char * procname);
+// typedef EGLBoolean  (EGLAPIENTRYP GPGETSYNCATTRIBKHR)(EGLDisplay  dpy, EGLSyncKHR  sync, EGLint  attribute, EGLint * value);
+// typedef EGLBoolean  (EGLAPIENTRYP GPINITIALIZE)(EGLDisplay  dpy, EGLint * major, EGLint * minor);
+// typedef EGLBoolean  (EGLAPIENTRYP GPLOCKSURFACEKHR)(EGLDisplay  dpy, EGLSurface  surface, const EGLint * attrib_list);
+// typedef EGLBoolean  (EGLAPIENTRYP GPMAKECURRENT)(EGLDisplay  dpy, EGLSurface  draw, EGLSurface  read, EGLContext  ctx);
+// typedef EGLenum  (EGLAPIENTRYP GPQUERYAPI)();
+// typedef EGLBoolean  (EGLAPIENTRYP GPQUERYCONTEXT)(EGLDisplay  dpy, EGLContext  ctx, EGLint  attribute, EGLint * value);
+// typedef const char * (EGLAPIENTRYP GPQUERYSTRING)(EGLDisplay  dpy, EGLint  name);
+// typedef EGLBoolean  (EGLAPIENTRYP GPQUERYSURFACE)(EGLDisplay  dpy, EGLSurface  surface, EGLint  attribute, EGLint * value);
+// typedef EGLBoolean  (EGLAPIENTRYP GPRELEASETEXIMAGE)(EGLDisplay  dpy, EGLSurface  surface, EGLint  buffer);
+// typedef EGLBoolean  (EGLAPIENTRYP GPRELEASETHREAD)();
+// typedef EGLBoolean  (EGLAPIENTRYP GPSIGNALSYNCKHR)(EGLDisplay  dpy, EGLSyncKHR  sync, EGLenum  mode);
+// typedef EGLBoolean  (EGLAPIENTRYP GPSURFACEATTRIB)(EGLDisplay  dpy, EGLSurface  surface, EGLint  attribute, EGLint  value);
+// typedef EGLBoolean  (EGLAPIENTRYP GPSWAPBUFFERS)(EGLDisplay  dpy, EGLSurface  surface);
+// typedef EGLBoolean  (EGLAPIENTRYP GPSWAPINTERVAL)(EGLDisplay  dpy, EGLint  interval);
+// typedef EGLBoolean  (EGLAPIENTRYP GPTERMINATE)(EGLDisplay  dpy);
+// typedef EGLBoolean  (EGLAPIENTRYP GPUNLOCKSURFACEKHR)(EGLDisplay  dpy, EGLSurface  surface);
+// typedef EGLBoolean  (EGLAPIENTRYP GPWAITCLIENT)();
+// typedef EGLBoolean  (EGLAPIENTRYP GPWAITGL)();
+// typedef EGLBoolean  (EGLAPIENTRYP GPWAITNATIVE)(EGLint  engine);
+// static EGLBoolean  glowBindAPI(GPBINDAPI fnptr, EGLenum  api) {
+//   return (*fnptr)(api);
+// }
+// static EGLBoolean  glowBindTexImage(GPBINDTEXIMAGE fnptr, EGLDisplay  dpy, EGLSurface  surface, EGLint  buffer) {
+//   return (*fnptr)(dpy, surface, buffer);
+// }
+// static EGLBoolean  glowChooseConfig(GPCHOOSECONFIG fnptr, EGLDisplay  dpy, const EGLint * attrib_list, EGLConfig * configs, EGLint  config_size, EGLint * num_config) {
+//   return (*fnptr)(dpy, attrib_list, configs, config_size, num_config);
+// }
+// static EGLint  glowClientWaitSyncKHR(GPCLIENTWAITSYNCKHR fnptr, EGLDisplay  dpy, EGLSyncKHR  sync, EGLint  flags, EGLTimeKHR  timeout) {
+//   return (*fnptr)(dpy, sync, flags, timeout);
+// }
+// static EGLBoolean  glowCopyBuffers(GPCOPYBUFFERS fnptr, EGLDisplay  dpy, EGLSurface  surface, EGLNativePixmapType  target) {
+//   return (*fnptr)(dpy, surface, target);
+// }
+// static EGLContext  glowCreateContext(GPCREATECONTEXT fnptr, EGLDisplay  dpy, EGLConfig  config, EGLContext  share_context, const EGLint * attrib_list) {
+//   return (*fnptr)(dpy, config, share_context, attrib_list);
+// }
+// static EGLImageKHR  glowCreateImageKHR(GPCREATEIMAGEKHR fnptr, EGLDisplay  dpy, EGLContext  ctx, EGLenum  target, EGLClientBuffer  buffer, const EGLint * attrib_list) {
+//   return (*fnptr)(dpy, ctx, target, buffer, attrib_list);
+// }
+// static EGLSurface  glowCreatePbufferFromClientBuffer(GPCREATEPBUFFERFROMCLIENTBUFFER fnptr, EGLDisplay  dpy, EGLenum  buftype, EGLClientBuffer  buffer, EGLConfig  config, const EGLint * attrib_list) {
+//   return (*fnptr)(dpy, buftype, buffer, config, attrib_list);
+// }
+// static EGLSurface  glowCreatePbufferSurface(GPCREATEPBUFFERSURFACE fnptr, EGLDisplay  dpy, EGLConfig  config, const EGLint * attrib_list) {
+//   return (*fnptr)(dpy, config, attrib_list);
+// }
+// static EGLSurface  glowCreatePixmapSurface(GPCREATEPIXMAPSURFACE fnptr, EGLDisplay  dpy, EGLConfig  config, EGLNativePixmapType  pixmap, const EGLint * attrib_list) {
+//   return (*fnptr)(dpy, config, pixmap, attrib_list);
+// }
+// static EGLSyncKHR  glowCreateSyncKHR(GPCREATESYNCKHR fnptr, EGLDisplay  dpy, EGLenum  type, const EGLint * attrib_list) {
+//   return (*fnptr)(dpy, type, attrib_list);
+// }
+// static EGLSurface  glowCreateWindowSurface(GPCREATEWINDOWSURFACE fnptr, EGLDisplay  dpy, EGLConfig  config, EGLNativeWindowType  win, const EGLint * attrib_list) {
+//   return (*fnptr)(dpy, config, win, attrib_list);
+// }
+// static EGLBoolean  glowDestroyContext(GPDESTROYCONTEXT fnptr, EGLDisplay  dpy, EGLContext  ctx) {
+//   return (*fnptr)(dpy, ctx);
+// }
+// static EGLBoolean  glowDestroyImageKHR(GPDESTROYIMAGEKHR fnptr, EGLDisplay  dpy, EGLImageKHR  image) {
+//   return (*fnptr)(dpy, image);
+// }
+// static EGLBoolean  glowDestroySurface(GPDESTROYSURFACE fnptr, EGLDisplay  dpy, EGLSurface  surface) {
+//   return (*fnptr)(dpy, surface);
+// }
+// static EGLBoolean  glowDestroySyncKHR(GPDESTROYSYNCKHR fnptr, EGLDisplay  dpy, EGLSyncKHR  sync) {
+//   return (*fnptr)(dpy, sync);
+// }
+// static EGLBoolean  glowGetConfigAttrib(GPGETCONFIGATTRIB fnptr, EGLDisplay  dpy, EGLConfig  config, EGLint  attribute, EGLint * value) {
+//   return (*fnptr)(dpy, config, attribute, value);
+// }
+// static EGLBoolean  glowGetConfigs(GPGETCONFIGS fnptr, EGLDisplay  dpy, EGLConfig * configs, EGLint  config_size, EGLint * num_config) {
+//   return (*fnptr)(dpy, configs, config_size, num_config);
+// }
+// static EGLContext  glowGetCurrentContext(GPGETCURRENTCONTEXT fnptr) {
+//   return (*fnptr)();
+// }
+// static EGLDisplay  glowGetCurrentDisplay(GPGETCURRENTDISPLAY fnptr) {
+//   return (*fnptr)();
+// }
+// static EGLSurface  glowGetCurrentSurface(GPGETCURRENTSURFACE fnptr, EGLint  readdraw) {
+//   return (*fnptr)(readdraw);
+// }
+// static EGLDisplay  glowGetDisplay(GPGETDISPLAY fnptr, EGLNativeDisplayType  display_id) {
+//   return (*fnptr)(display_id);
+// }
+// static EGLint  glowGetError(GPGETERROR fnptr) {
+//   return (*fnptr)();
+// }
+// static __eglMustCastToProperFunctionPointerType  glowGetProcAddress(GPGETPROCADDRESS fnptr, const char * procname) {
+//   return (*fnptr)(procname);
+// }
+// static EGLBoolean  glowGetSyncAttribKHR(GPGETSYNCATTRIBKHR fnptr, EGLDisplay  dpy, EGLSyncKHR  sync, EGLint  attribute, EGLint * value) {
+//   return (*fnptr)(dpy, sync, attribute, value);
+// }
+// static EGLBoolean  glowInitialize(GPINITIALIZE fnptr, EGLDisplay  dpy, EGLint * major, EGLint * minor) {
+//   return (*fnptr)(dpy, major, minor);
+// }
+// static EGLBoolean  glowLockSurfaceKHR(GPLOCKSURFACEKHR fnptr, EGLDisplay  dpy, EGLSurface  surface, const EGLint * attrib_list) {
+//   return (*fnptr)(dpy, surface, attrib_list);
+// }
+// static EGLBoolean  glowMakeCurrent(GPMAKECURRENT fnptr, EGLDisplay  dpy, EGLSurface  draw, EGLSurface  read, EGLContext  ctx) {
+//   return (*fnptr)(dpy, draw, read, ctx);
+// }
+// static EGLenum  glowQueryAPI(GPQUERYAPI fnptr) {
+//   return (*fnptr)();
+// }
+// static EGLBoolean  glowQueryContext(GPQUERYCONTEXT fnptr, EGLDisplay  dpy, EGLContext  ctx, EGLint  attribute, EGLint * value) {
+//   return (*fnptr)(dpy, ctx, attribute, value);
+// }
+// static const char * glowQueryString(GPQUERYSTRING fnptr, EGLDisplay  dpy, EGLint  name) {
+//   return (*fnptr)(dpy, name);
+// }
+// static EGLBoolean  glowQuerySurface(GPQUERYSURFACE fnptr, EGLDisplay  dpy, EGLSurface  surface, EGLint  attribute, EGLint * value) {
+//   return (*fnptr)(dpy, surface, attribute, value);
+// }
+// static EGLBoolean  glowReleaseTexImage(GPRELEASETEXIMAGE fnptr, EGLDisplay  dpy, EGLSurface  surface, EGLint  buffer) {
+//   return (*fnptr)(dpy, surface, buffer);
+// }
+// static EGLBoolean  glowReleaseThread(GPRELEASETHREAD fnptr) {
+//   return (*fnptr)();
+// }
+// static EGLBoolean  glowSignalSyncKHR(GPSIGNALSYNCKHR fnptr, EGLDisplay  dpy, EGLSyncKHR  sync, EGLenum  mode) {
+//   return (*fnptr)(dpy, sync, mode);
+// }
+// static EGLBoolean  glowSurfaceAttrib(GPSURFACEATTRIB fnptr, EGLDisplay  dpy, EGLSurface  surface, EGLint  attribute, EGLint  value) {
+//   return (*fnptr)(dpy, surface, attribute, value);
+// }
+// static EGLBoolean  glowSwapBuffers(GPSWAPBUFFERS fnptr, EGLDisplay  dpy, EGLSurface  surface) {
+//   return (*fnptr)(dpy, surface);
+// }
+// static EGLBoolean  glowSwapInterval(GPSWAPINTERVAL fnptr, EGLDisplay  dpy, EGLint  interval) {
+//   return (*fnptr)(dpy, interval);
+// }
+// static EGLBoolean  glowTerminate(GPTERMINATE fnptr, EGLDisplay  dpy) {
+//   return (*fnptr)(dpy);
+// }
+// static EGLBoolean  glowUnlockSurfaceKHR(GPUNLOCKSURFACEKHR fnptr, EGLDisplay  dpy, EGLSurface  surface) {
+//   return (*fnptr)(dpy, surface);
+// }
+// static EGLBoolean  glowWaitClient(GPWAITCLIENT fnptr) {
+//   return (*fnptr)();
+// }
+// static EGLBoolean  glowWaitGL(GPWAITGL fnptr) {
+//   return (*fnptr)();
+// }
+// static EGLBoolean  glowWaitNative(GPWAITNATIVE fnptr, EGLint  engine) {
+//   return (*fnptr)(engine);
+// }
+import "C"
+import (
+	"errors"
+	"unsafe"
+)
+
+const (
+	ALPHA_FORMAT                       = 0x3088
+	ALPHA_FORMAT_NONPRE                = 0x308B
+	ALPHA_FORMAT_PRE                   = 0x308C
+	ALPHA_MASK_SIZE                    = 0x303E
+	ALPHA_SIZE                         = 0x3021
+	BACK_BUFFER                        = 0x3084
+	BAD_ACCESS                         = 0x3002
+	BAD_ALLOC                          = 0x3003
+	BAD_ATTRIBUTE                      = 0x3004
+	BAD_CONFIG                         = 0x3005
+	BAD_CONTEXT                        = 0x3006
+	BAD_CURRENT_SURFACE                = 0x3007
+	BAD_DISPLAY                        = 0x3008
+	BAD_MATCH                          = 0x3009
+	BAD_NATIVE_PIXMAP                  = 0x300A
+	BAD_NATIVE_WINDOW                  = 0x300B
+	BAD_PARAMETER                      = 0x300C
+	BAD_SURFACE                        = 0x300D
+	BIND_TO_TEXTURE_RGB                = 0x3039
+	BIND_TO_TEXTURE_RGBA               = 0x303A
+	BITMAP_ORIGIN_KHR                  = 0x30C8
+	BITMAP_PITCH_KHR                   = 0x30C7
+	BITMAP_PIXEL_ALPHA_OFFSET_KHR      = 0x30CC
+	BITMAP_PIXEL_BLUE_OFFSET_KHR       = 0x30CB
+	BITMAP_PIXEL_GREEN_OFFSET_KHR      = 0x30CA
+	BITMAP_PIXEL_LUMINANCE_OFFSET_KHR  = 0x30CD
+	BITMAP_PIXEL_RED_OFFSET_KHR        = 0x30C9
+	BITMAP_POINTER_KHR                 = 0x30C6
+	BLUE_SIZE                          = 0x3022
+	BUFFER_DESTROYED                   = 0x3095
+	BUFFER_PRESERVED                   = 0x3094
+	BUFFER_SIZE                        = 0x3020
+	CLIENT_APIS                        = 0x308D
+	COLORSPACE                         = 0x3087
+	COLORSPACE_LINEAR                  = 0x308A
+	COLORSPACE_sRGB                    = 0x3089
+	COLOR_BUFFER_TYPE                  = 0x303F
+	CONDITION_SATISFIED_KHR            = 0x30F6
+	CONFIG_CAVEAT                      = 0x3027
+	CONFIG_ID                          = 0x3028
+	CONFORMANT                         = 0x3042
+	CONTEXT_CLIENT_TYPE                = 0x3097
+	CONTEXT_CLIENT_VERSION             = 0x3098
+	CONTEXT_LOST                       = 0x300E
+	CORE_NATIVE_ENGINE                 = 0x305B
+	DEPTH_SIZE                         = 0x3025
+	DISPLAY_SCALING                    = 10000
+	DONT_CARE                          = -1
+	DRAW                               = 0x3059
+	EXTENSIONS                         = 0x3055
+	FALSE                              = 0
+	FOREVER_KHR                        = 0xFFFFFFFFFFFFFFFF
+	FORMAT_RGBA_8888_EXACT_KHR         = 0x30C2
+	FORMAT_RGBA_8888_KHR               = 0x30C3
+	FORMAT_RGB_565_EXACT_KHR           = 0x30C0
+	FORMAT_RGB_565_KHR                 = 0x30C1
+	GL_TEXTURE_2D_KHR                  = 0x30B1
+	GL_TEXTURE_CUBE_MAP_NEGATIVE_X_KHR = 0x30B4
+	GL_TEXTURE_CUBE_MAP_NEGATIVE_Y_KHR = 0x30B6
+	GL_TEXTURE_CUBE_MAP_NEGATIVE_Z_KHR = 0x30B8
+	GL_TEXTURE_CUBE_MAP_POSITIVE_X_KHR = 0x30B3
+	GL_TEXTURE_CUBE_MAP_POSITIVE_Y_KHR = 0x30B5
+	GL_TEXTURE_CUBE_MAP_POSITIVE_Z_KHR = 0x30B7
+	GL_TEXTURE_LEVEL_KHR               = 0x30BC
+	GREEN_SIZE                         = 0x3023
+	HEIGHT                             = 0x3056
+	HORIZONTAL_RESOLUTION              = 0x3090
+	IMAGE_PRESERVED_KHR                = 0x30D2
+	LARGEST_PBUFFER                    = 0x3058
+	LEVEL                              = 0x3029
+	LOCK_SURFACE_BIT_KHR               = 0x0080
+	LOCK_USAGE_HINT_KHR                = 0x30C5
+	LOWER_LEFT_KHR                     = 0x30CE
+	LUMINANCE_BUFFER                   = 0x308F
+	LUMINANCE_SIZE                     = 0x303D
+	MAP_PRESERVE_PIXELS_KHR            = 0x30C4
+	MATCH_FORMAT_KHR                   = 0x3043
+	MATCH_NATIVE_PIXMAP                = 0x3041
+	MAX_PBUFFER_HEIGHT                 = 0x302A
+	MAX_PBUFFER_PIXELS                 = 0x302B
+	MAX_PBUFFER_WIDTH                  = 0x302C
+	MAX_SWAP_INTERVAL                  = 0x303C
+	MIN_SWAP_INTERVAL                  = 0x303B
+	MIPMAP_LEVEL                       = 0x3083
+	MIPMAP_TEXTURE                     = 0x3082
+	MULTISAMPLE_RESOLVE                = 0x3099
+	MULTISAMPLE_RESOLVE_BOX            = 0x309B
+	MULTISAMPLE_RESOLVE_BOX_BIT        = 0x0200
+	MULTISAMPLE_RESOLVE_DEFAULT        = 0x309A
+	NATIVE_PIXMAP_KHR                  = 0x30B0
+	NATIVE_RENDERABLE                  = 0x302D
+	NATIVE_VISUAL_ID                   = 0x302E
+	NATIVE_VISUAL_TYPE                 = 0x302F
+	NONE                               = 0x3038
+	NON_CONFORMANT_CONFIG              = 0x3051
+	NOT_INITIALIZED                    = 0x3001
+	NO_TEXTURE                         = 0x305C
+	OPENGL_API                         = 0x30A2
+	OPENGL_BIT                         = 0x0008
+	OPENGL_ES2_BIT                     = 0x0004
+	OPENGL_ES_API                      = 0x30A0
+	OPENGL_ES_BIT                      = 0x0001
+	OPENVG_API                         = 0x30A1
+	OPENVG_BIT                         = 0x0002
+	OPENVG_IMAGE                       = 0x3096
+	OPTIMAL_FORMAT_BIT_KHR             = 0x0100
+	PBUFFER_BIT                        = 0x0001
+	PIXEL_ASPECT_RATIO                 = 0x3092
+	PIXMAP_BIT                         = 0x0002
+	READ                               = 0x305A
+	READ_SURFACE_BIT_KHR               = 0x0001
+	RED_SIZE                           = 0x3024
+	RENDERABLE_TYPE                    = 0x3040
+	RENDER_BUFFER                      = 0x3086
+	RGB_BUFFER                         = 0x308E
+	SAMPLES                            = 0x3031
+	SAMPLE_BUFFERS                     = 0x3032
+	SIGNALED_KHR                       = 0x30F2
+	SINGLE_BUFFER                      = 0x3085
+	SLOW_CONFIG                        = 0x3050
+	STENCIL_SIZE                       = 0x3026
+	SUCCESS                            = 0x3000
+	SURFACE_TYPE                       = 0x3033
+	SWAP_BEHAVIOR                      = 0x3093
+	SWAP_BEHAVIOR_PRESERVED_BIT        = 0x0400
+	SYNC_CONDITION_KHR                 = 0x30F8
+	SYNC_FENCE_KHR                     = 0x30F9
+	SYNC_FLUSH_COMMANDS_BIT_KHR        = 0x0001
+	SYNC_PRIOR_COMMANDS_COMPLETE_KHR   = 0x30F0
+	SYNC_REUSABLE_KHR                  = 0x30FA
+	SYNC_STATUS_KHR                    = 0x30F1
+	SYNC_TYPE_KHR                      = 0x30F7
+	TEXTURE_2D                         = 0x305F
+	TEXTURE_FORMAT                     = 0x3080
+	TEXTURE_RGB                        = 0x305D
+	TEXTURE_RGBA                       = 0x305E
+	TEXTURE_TARGET                     = 0x3081
+	TIMEOUT_EXPIRED_KHR                = 0x30F5
+	TRANSPARENT_BLUE_VALUE             = 0x3035
+	TRANSPARENT_GREEN_VALUE            = 0x3036
+	TRANSPARENT_RED_VALUE              = 0x3037
+	TRANSPARENT_RGB                    = 0x3052
+	TRANSPARENT_TYPE                   = 0x3034
+	TRUE                               = 1
+	UNKNOWN                            = -1
+	UNSIGNALED_KHR                     = 0x30F3
+	UPPER_LEFT_KHR                     = 0x30CF
+	VENDOR                             = 0x3053
+	VERTICAL_RESOLUTION                = 0x3091
+	VG_ALPHA_FORMAT                    = 0x3088
+	VG_ALPHA_FORMAT_NONPRE             = 0x308B
+	VG_ALPHA_FORMAT_PRE                = 0x308C
+	VG_ALPHA_FORMAT_PRE_BIT            = 0x0040
+	VG_COLORSPACE                      = 0x3087
+	VG_COLORSPACE_LINEAR               = 0x308A
+	VG_COLORSPACE_LINEAR_BIT           = 0x0020
+	VG_COLORSPACE_sRGB                 = 0x3089
+	VG_PARENT_IMAGE_KHR                = 0x30BA
+	WIDTH                              = 0x3057
+	WINDOW_BIT                         = 0x0004
+	WRITE_SURFACE_BIT_KHR              = 0x0002
+)
+
+var (
+	gpBindAPI                       C.GPBINDAPI
+	gpBindTexImage                  C.GPBINDTEXIMAGE
+	gpChooseConfig                  C.GPCHOOSECONFIG
+	gpClientWaitSyncKHR             C.GPCLIENTWAITSYNCKHR
+	gpCopyBuffers                   C.GPCOPYBUFFERS
+	gpCreateContext                 C.GPCREATECONTEXT
+	gpCreateImageKHR                C.GPCREATEIMAGEKHR
+	gpCreatePbufferFromClientBuffer C.GPCREATEPBUFFERFROMCLIENTBUFFER
+	gpCreatePbufferSurface          C.GPCREATEPBUFFERSURFACE
+	gpCreatePixmapSurface           C.GPCREATEPIXMAPSURFACE
+	gpCreateSyncKHR                 C.GPCREATESYNCKHR
+	gpCreateWindowSurface           C.GPCREATEWINDOWSURFACE
+	gpDestroyContext                C.GPDESTROYCONTEXT
+	gpDestroyImageKHR               C.GPDESTROYIMAGEKHR
+	gpDestroySurface                C.GPDESTROYSURFACE
+	gpDestroySyncKHR                C.GPDESTROYSYNCKHR
+	gpGetConfigAttrib               C.GPGETCONFIGATTRIB
+	gpGetConfigs                    C.GPGETCONFIGS
+	gpGetCurrentContext             C.GPGETCURRENTCONTEXT
+	gpGetCurrentDisplay             C.GPGETCURRENTDISPLAY
+	gpGetCurrentSurface             C.GPGETCURRENTSURFACE
+	gpGetDisplay                    C.GPGETDISPLAY
+	gpGetError                      C.GPGETERROR
+	gpGetProcAddress                C.GPGETPROCADDRESS
+	gpGetSyncAttribKHR              C.GPGETSYNCATTRIBKHR
+	gpInitialize                    C.GPINITIALIZE
+	gpLockSurfaceKHR                C.GPLOCKSURFACEKHR
+	gpMakeCurrent                   C.GPMAKECURRENT
+	gpQueryAPI                      C.GPQUERYAPI
+	gpQueryContext                  C.GPQUERYCONTEXT
+	gpQueryString                   C.GPQUERYSTRING
+	gpQuerySurface                  C.GPQUERYSURFACE
+	gpReleaseTexImage               C.GPRELEASETEXIMAGE
+	gpReleaseThread                 C.GPRELEASETHREAD
+	gpSignalSyncKHR                 C.GPSIGNALSYNCKHR
+	gpSurfaceAttrib                 C.GPSURFACEATTRIB
+	gpSwapBuffers                   C.GPSWAPBUFFERS
+	gpSwapInterval                  C.GPSWAPINTERVAL
+	gpTerminate                     C.GPTERMINATE
+	gpUnlockSurfaceKHR              C.GPUNLOCKSURFACEKHR
+	gpWaitClient                    C.GPWAITCLIENT
+	gpWaitGL                        C.GPWAITGL
+	gpWaitNative                    C.GPWAITNATIVE
+)
+
+// Helper functions
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func BindAPI(api uint32) bool {
+	ret := C.glowBindAPI(gpBindAPI, (C.EGLenum)(api))
+	return ret == TRUE
+}
+func BindTexImage(dpy unsafe.Pointer, surface unsafe.Pointer, buffer int32) bool {
+	ret := C.glowBindTexImage(gpBindTexImage, (C.EGLDisplay)(dpy), (C.EGLSurface)(surface), (C.EGLint)(buffer))
+	return ret == TRUE
+}
+func ChooseConfig(dpy unsafe.Pointer, attrib_list *int32, configs *unsafe.Pointer, config_size int32, num_config *int32) bool {
+	ret := C.glowChooseConfig(gpChooseConfig, (C.EGLDisplay)(dpy), (*C.EGLint)(unsafe.Pointer(attrib_list)), (*C.EGLConfig)(unsafe.Pointer(configs)), (C.EGLint)(config_size), (*C.EGLint)(unsafe.Pointer(num_config)))
+	return ret == TRUE
+}
+func ClientWaitSyncKHR(dpy unsafe.Pointer, sync unsafe.Pointer, flags int32, timeout uint64) int32 {
+	ret := C.glowClientWaitSyncKHR(gpClientWaitSyncKHR, (C.EGLDisplay)(dpy), (C.EGLSyncKHR)(sync), (C.EGLint)(flags), (C.EGLTimeKHR)(timeout))
+	return (int32)(ret)
+}
+func CopyBuffers(dpy unsafe.Pointer, surface unsafe.Pointer, target unsafe.Pointer) bool {
+	ret := C.glowCopyBuffers(gpCopyBuffers, (C.EGLDisplay)(dpy), (C.EGLSurface)(surface), (C.EGLNativePixmapType)(target))
+	return ret == TRUE
+}
+func CreateContext(dpy unsafe.Pointer, config unsafe.Pointer, share_context unsafe.Pointer, attrib_list *int32) unsafe.Pointer {
+	ret := C.glowCreateContext(gpCreateContext, (C.EGLDisplay)(dpy), (C.EGLConfig)(config), (C.EGLContext)(share_context), (*C.EGLint)(unsafe.Pointer(attrib_list)))
+	return (unsafe.Pointer)(ret)
+}
+func CreateImageKHR(dpy unsafe.Pointer, ctx unsafe.Pointer, target uint32, buffer unsafe.Pointer, attrib_list *int32) unsafe.Pointer {
+	ret := C.glowCreateImageKHR(gpCreateImageKHR, (C.EGLDisplay)(dpy), (C.EGLContext)(ctx), (C.EGLenum)(target), (C.EGLClientBuffer)(buffer), (*C.EGLint)(unsafe.Pointer(attrib_list)))
+	return (unsafe.Pointer)(ret)
+}
+func CreatePbufferFromClientBuffer(dpy unsafe.Pointer, buftype uint32, buffer unsafe.Pointer, config unsafe.Pointer, attrib_list *int32) unsafe.Pointer {
+	ret := C.glowCreatePbufferFromClientBuffer(gpCreatePbufferFromClientBuffer, (C.EGLDisplay)(dpy), (C.EGLenum)(buftype), (C.EGLClientBuffer)(buffer), (C.EGLConfig)(config), (*C.EGLint)(unsafe.Pointer(attrib_list)))
+	return (unsafe.Pointer)(ret)
+}
+func CreatePbufferSurface(dpy unsafe.Pointer, config unsafe.Pointer, attrib_list *int32) unsafe.Pointer {
+	ret := C.glowCreatePbufferSurface(gpCreatePbufferSurface, (C.EGLDisplay)(dpy), (C.EGLConfig)(config), (*C.EGLint)(unsafe.Pointer(attrib_list)))
+	return (unsafe.Pointer)(ret)
+}
+func CreatePixmapSurface(dpy unsafe.Pointer, config unsafe.Pointer, pixmap unsafe.Pointer, attrib_list *int32) unsafe.Pointer {
+	ret := C.glowCreatePixmapSurface(gpCreatePixmapSurface, (C.EGLDisplay)(dpy), (C.EGLConfig)(config), (C.EGLNativePixmapType)(pixmap), (*C.EGLint)(unsafe.Pointer(attrib_list)))
+	return (unsafe.Pointer)(ret)
+}
+func CreateSyncKHR(dpy unsafe.Pointer, xtype uint32, attrib_list *int32) unsafe.Pointer {
+	ret := C.glowCreateSyncKHR(gpCreateSyncKHR, (C.EGLDisplay)(dpy), (C.EGLenum)(xtype), (*C.EGLint)(unsafe.Pointer(attrib_list)))
+	return (unsafe.Pointer)(ret)
+}
+func CreateWindowSurface(dpy unsafe.Pointer, config unsafe.Pointer, win unsafe.Pointer, attrib_list *int32) unsafe.Pointer {
+	ret := C.glowCreateWindowSurface(gpCreateWindowSurface, (C.EGLDisplay)(dpy), (C.EGLConfig)(config), (C.EGLNativeWindowType)(win), (*C.EGLint)(unsafe.Pointer(attrib_list)))
+	return (unsafe.Pointer)(ret)
+}
+func DestroyContext(dpy unsafe.Pointer, ctx unsafe.Pointer) bool {
+	ret := C.glowDestroyContext(gpDestroyContext, (C.EGLDisplay)(dpy), (C.EGLContext)(ctx))
+	return ret == TRUE
+}
+func DestroyImageKHR(dpy unsafe.Pointer, image unsafe.Pointer) bool {
+	ret := C.glowDestroyImageKHR(gpDestroyImageKHR, (C.EGLDisplay)(dpy), (C.EGLImageKHR)(image))
+	return ret == TRUE
+}
+func DestroySurface(dpy unsafe.Pointer, surface unsafe.Pointer) bool {
+	ret := C.glowDestroySurface(gpDestroySurface, (C.EGLDisplay)(dpy), (C.EGLSurface)(surface))
+	return ret == TRUE
+}
+func DestroySyncKHR(dpy unsafe.Pointer, sync unsafe.Pointer) bool {
+	ret := C.glowDestroySyncKHR(gpDestroySyncKHR, (C.EGLDisplay)(dpy), (C.EGLSyncKHR)(sync))
+	return ret == TRUE
+}
+func GetConfigAttrib(dpy unsafe.Pointer, config unsafe.Pointer, attribute int32, value *int32) bool {
+	ret := C.glowGetConfigAttrib(gpGetConfigAttrib, (C.EGLDisplay)(dpy), (C.EGLConfig)(config), (C.EGLint)(attribute), (*C.EGLint)(unsafe.Pointer(value)))
+	return ret == TRUE
+}
+func GetConfigs(dpy unsafe.Pointer, configs *unsafe.Pointer, config_size int32, num_config *int32) bool {
+	ret := C.glowGetConfigs(gpGetConfigs, (C.EGLDisplay)(dpy), (*C.EGLConfig)(unsafe.Pointer(configs)), (C.EGLint)(config_size), (*C.EGLint)(unsafe.Pointer(num_config)))
+	return ret == TRUE
+}
+func GetCurrentContext() unsafe.Pointer {
+	ret := C.glowGetCurrentContext(gpGetCurrentContext)
+	return (unsafe.Pointer)(ret)
+}
+func GetCurrentDisplay() unsafe.Pointer {
+	ret := C.glowGetCurrentDisplay(gpGetCurrentDisplay)
+	return *(*unsafe.Pointer)(unsafe.Pointer(&ret))
+}
+func GetCurrentSurface(readdraw int32) unsafe.Pointer {
+	ret := C.glowGetCurrentSurface(gpGetCurrentSurface, (C.EGLint)(readdraw))
+	return (unsafe.Pointer)(ret)
+}
+func GetDisplay(display_id unsafe.Pointer) unsafe.Pointer {
+	ret := C.glowGetDisplay(gpGetDisplay, (C.EGLNativeDisplayType)(display_id))
+	return *(*unsafe.Pointer)(unsafe.Pointer(&ret))
+}
+func GetError() int32 {
+	ret := C.glowGetError(gpGetError)
+	return (int32)(ret)
+}
+func GetProcAddress(procname *uint8) unsafe.Pointer {
+	ret := C.glowGetProcAddress(gpGetProcAddress, (*C.char)(unsafe.Pointer(procname)))
+	return (unsafe.Pointer)(ret)
+}
+func GetSyncAttribKHR(dpy unsafe.Pointer, sync unsafe.Pointer, attribute int32, value *int32) bool {
+	ret := C.glowGetSyncAttribKHR(gpGetSyncAttribKHR, (C.EGLDisplay)(dpy), (C.EGLSyncKHR)(sync), (C.EGLint)(attribute), (*C.EGLint)(unsafe.Pointer(value)))
+	return ret == TRUE
+}
+func Initialize(dpy unsafe.Pointer, major *int32, minor *int32) bool {
+	ret := C.glowInitialize(gpInitialize, (C.EGLDisplay)(dpy), (*C.EGLint)(unsafe.Pointer(major)), (*C.EGLint)(unsafe.Pointer(minor)))
+	return ret == TRUE
+}
+func LockSurfaceKHR(dpy unsafe.Pointer, surface unsafe.Pointer, attrib_list *int32) bool {
+	ret := C.glowLockSurfaceKHR(gpLockSurfaceKHR, (C.EGLDisplay)(dpy), (C.EGLSurface)(surface), (*C.EGLint)(unsafe.Pointer(attrib_list)))
+	return ret == TRUE
+}
+func MakeCurrent(dpy unsafe.Pointer, draw unsafe.Pointer, read unsafe.Pointer, ctx unsafe.Pointer) bool {
+	ret := C.glowMakeCurrent(gpMakeCurrent, (C.EGLDisplay)(dpy), (C.EGLSurface)(draw), (C.EGLSurface)(read), (C.EGLContext)(ctx))
+	return ret == TRUE
+}
+func QueryAPI() uint32 {
+	ret := C.glowQueryAPI(gpQueryAPI)
+	return (uint32)(ret)
+}
+func QueryContext(dpy unsafe.Pointer, ctx unsafe.Pointer, attribute int32, value *int32) bool {
+	ret := C.glowQueryContext(gpQueryContext, (C.EGLDisplay)(dpy), (C.EGLContext)(ctx), (C.EGLint)(attribute), (*C.EGLint)(unsafe.Pointer(value)))
+	return ret == TRUE
+}
+func QueryString(dpy unsafe.Pointer, name int32) *uint8 {
+	ret := C.glowQueryString(gpQueryString, (C.EGLDisplay)(dpy), (C.EGLint)(name))
+	return (*uint8)(unsafe.Pointer(ret))
+}
+func QuerySurface(dpy unsafe.Pointer, surface unsafe.Pointer, attribute int32, value *int32) bool {
+	ret := C.glowQuerySurface(gpQuerySurface, (C.EGLDisplay)(dpy), (C.EGLSurface)(surface), (C.EGLint)(attribute), (*C.EGLint)(unsafe.Pointer(value)))
+	return ret == TRUE
+}
+func ReleaseTexImage(dpy unsafe.Pointer, surface unsafe.Pointer, buffer int32) bool {
+	ret := C.glowReleaseTexImage(gpReleaseTexImage, (C.EGLDisplay)(dpy), (C.EGLSurface)(surface), (C.EGLint)(buffer))
+	return ret == TRUE
+}
+func ReleaseThread() bool {
+	ret := C.glowReleaseThread(gpReleaseThread)
+	return ret == TRUE
+}
+func SignalSyncKHR(dpy unsafe.Pointer, sync unsafe.Pointer, mode uint32) bool {
+	ret := C.glowSignalSyncKHR(gpSignalSyncKHR, (C.EGLDisplay)(dpy), (C.EGLSyncKHR)(sync), (C.EGLenum)(mode))
+	return ret == TRUE
+}
+func SurfaceAttrib(dpy unsafe.Pointer, surface unsafe.Pointer, attribute int32, value int32) bool {
+	ret := C.glowSurfaceAttrib(gpSurfaceAttrib, (C.EGLDisplay)(dpy), (C.EGLSurface)(surface), (C.EGLint)(attribute), (C.EGLint)(value))
+	return ret == TRUE
+}
+func SwapBuffers(dpy unsafe.Pointer, surface unsafe.Pointer) bool {
+	ret := C.glowSwapBuffers(gpSwapBuffers, (C.EGLDisplay)(dpy), (C.EGLSurface)(surface))
+	return ret == TRUE
+}
+func SwapInterval(dpy unsafe.Pointer, interval int32) bool {
+	ret := C.glowSwapInterval(gpSwapInterval, (C.EGLDisplay)(dpy), (C.EGLint)(interval))
+	return ret == TRUE
+}
+func Terminate(dpy unsafe.Pointer) bool {
+	ret := C.glowTerminate(gpTerminate, (C.EGLDisplay)(dpy))
+	return ret == TRUE
+}
+func UnlockSurfaceKHR(dpy unsafe.Pointer, surface unsafe.Pointer) bool {
+	ret := C.glowUnlockSurfaceKHR(gpUnlockSurfaceKHR, (C.EGLDisplay)(dpy), (C.EGLSurface)(surface))
+	return ret == TRUE
+}
+func WaitClient() bool {
+	ret := C.glowWaitClient(gpWaitClient)
+	return ret == TRUE
+}
+func WaitGL() bool {
+	ret := C.glowWaitGL(gpWaitGL)
+	return ret == TRUE
+}
+func WaitNative(engine int32) bool {
+	ret := C.glowWaitNative(gpWaitNative, (C.EGLint)(engine))
+	return ret == TRUE
+}
+
+// Init initializes the EGL bindings by loading the function pointers (for
+// each EGL function) with getProcAddress, which piglet does not provide.
+//
+// Call InitWithProcAddrFunc(piglet.GetProcAddress) instead, always after
+// calling piglet.MakeCurrent() and always before calling any functions
+// exported by this package.
+func Init() error {
+	return InitWithProcAddrFunc(getProcAddress)
+}
+
+// InitWithProcAddrFunc intializes the package using the specified EGL
+// function pointer loading function. For more cases Init should be used
+// instead.
+func InitWithProcAddrFunc(getProcAddr func(name string) unsafe.Pointer) error {
+	gpBindAPI = (C.GPBINDAPI)(getProcAddr("eglBindAPI"))
+	if gpBindAPI == nil {
+		return errors.New("eglBindAPI")
+	}
+	gpBindTexImage = (C.GPBINDTEXIMAGE)(getProcAddr("eglBindTexImage"))
+	if gpBindTexImage == nil {
+		return errors.New("eglBindTexImage")
+	}
+	gpChooseConfig = (C.GPCHOOSECONFIG)(getProcAddr("eglChooseConfig"))
+	if gpChooseConfig == nil {
+		return errors.New("eglChooseConfig")
+	}
+	gpClientWaitSyncKHR = (C.GPCLIENTWAITSYNCKHR)(getProcAddr("eglClientWaitSyncKHR"))
+	gpCopyBuffers = (C.GPCOPYBUFFERS)(getProcAddr("eglCopyBuffers"))
+	if gpCopyBuffers == nil {
+		return errors.New("eglCopyBuffers")
+	}
+	gpCreateContext = (C.GPCREATECONTEXT)(getProcAddr("eglCreateContext"))
+	if gpCreateContext == nil {
+		return errors.New("eglCreateContext")
+	}
+	gpCreateImageKHR = (C.GPCREATEIMAGEKHR)(getProcAddr("eglCreateImageKHR"))
+	gpCreatePbufferFromClientBuffer = (C.GPCREATEPBUFFERFROMCLIENTBUFFER)(getProcAddr("eglCreatePbufferFromClientBuffer"))
+	if gpCreatePbufferFromClientBuffer == nil {
+		return errors.New("eglCreatePbufferFromClientBuffer")
+	}
+	gpCreatePbufferSurface = (C.GPCREATEPBUFFERSURFACE)(getProcAddr("eglCreatePbufferSurface"))
+	if gpCreatePbufferSurface == nil {
+		return errors.New("eglCreatePbufferSurface")
+	}
+	gpCreatePixmapSurface = (C.GPCREATEPIXMAPSURFACE)(getProcAddr("eglCreatePixmapSurface"))
+	if gpCreatePixmapSurface == nil {
+		return errors.New("eglCreatePixmapSurface")
+	}
+	gpCreateSyncKHR = (C.GPCREATESYNCKHR)(getProcAddr("eglCreateSyncKHR"))
+	gpCreateWindowSurface = (C.GPCREATEWINDOWSURFACE)(getProcAddr("eglCreateWindowSurface"))
+	if gpCreateWindowSurface == nil {
+		return errors.New("eglCreateWindowSurface")
+	}
+	gpDestroyContext = (C.GPDESTROYCONTEXT)(getProcAddr("eglDestroyContext"))
+	if gpDestroyContext == nil {
+		return errors.New("eglDestroyContext")
+	}
+	gpDestroyImageKHR = (C.GPDESTROYIMAGEKHR)(getProcAddr("eglDestroyImageKHR"))
+	gpDestroySurface = (C.GPDESTROYSURFACE)(getProcAddr("eglDestroySurface"))
+	if gpDestroySurface == nil {
+		return errors.New("eglDestroySurface")
+	}
+	gpDestroySyncKHR = (C.GPDESTROYSYNCKHR)(getProcAddr("eglDestroySyncKHR"))
+	gpGetConfigAttrib = (C.GPGETCONFIGATTRIB)(getProcAddr("eglGetConfigAttrib"))
+	if gpGetConfigAttrib == nil {
+		return errors.New("eglGetConfigAttrib")
+	}
+	gpGetConfigs = (C.GPGETCONFIGS)(getProcAddr("eglGetConfigs"))
+	if gpGetConfigs == nil {
+		return errors.New("eglGetConfigs")
+	}
+	gpGetCurrentContext = (C.GPGETCURRENTCONTEXT)(getProcAddr("eglGetCurrentContext"))
+	if gpGetCurrentContext == nil {
+		return errors.New("eglGetCurrentContext")
+	}
+	gpGetCurrentDisplay = (C.GPGETCURRENTDISPLAY)(getProcAddr("eglGetCurrentDisplay"))
+	if gpGetCurrentDisplay == nil {
+		return errors.New("eglGetCurrentDisplay")
+	}
+	gpGetCurrentSurface = (C.GPGETCURRENTSURFACE)(getProcAddr("eglGetCurrentSurface"))
+	if gpGetCurrentSurface == nil {
+		return errors.New("eglGetCurrentSurface")
+	}
+	gpGetDisplay = (C.GPGETDISPLAY)(getProcAddr("eglGetDisplay"))
+	if gpGetDisplay == nil {
+		return errors.New("eglGetDisplay")
+	}
+	gpGetError = (C.GPGETERROR)(getProcAddr("eglGetError"))
+	if gpGetError == nil {
+		return errors.New("eglGetError")
+	}
+	gpGetProcAddress = (C.GPGETPROCADDRESS)(getProcAddr("eglGetProcAddress"))
+	if gpGetProcAddress == nil {
+		return errors.New("eglGetProcAddress")
+	}
+	gpGetSyncAttribKHR = (C.GPGETSYNCATTRIBKHR)(getProcAddr("eglGetSyncAttribKHR"))
+	gpInitialize = (C.GPINITIALIZE)(getProcAddr("eglInitialize"))
+	if gpInitialize == nil {
+		return errors.New("eglInitialize")
+	}
+	gpLockSurfaceKHR = (C.GPLOCKSURFACEKHR)(getProcAddr("eglLockSurfaceKHR"))
+	gpMakeCurrent = (C.GPMAKECURRENT)(getProcAddr("eglMakeCurrent"))
+	if gpMakeCurrent == nil {
+		return errors.New("eglMakeCurrent")
+	}
+	gpQueryAPI = (C.GPQUERYAPI)(getProcAddr("eglQueryAPI"))
+	if gpQueryAPI == nil {
+		return errors.New("eglQueryAPI")
+	}
+	gpQueryContext = (C.GPQUERYCONTEXT)(getProcAddr("eglQueryContext"))
+	if gpQueryContext == nil {
+		return errors.New("eglQueryContext")
+	}
+	gpQueryString = (C.GPQUERYSTRING)(getProcAddr("eglQueryString"))
+	if gpQueryString == nil {
+		return errors.New("eglQueryString")
+	}
+	gpQuerySurface = (C.GPQUERYSURFACE)(getProcAddr("eglQuerySurface"))
+	if gpQuerySurface == nil {
+		return errors.New("eglQuerySurface")
+	}
+	gpReleaseTexImage = (C.GPRELEASETEXIMAGE)(getProcAddr("eglReleaseTexImage"))
+	if gpReleaseTexImage == nil {
+		return errors.New("eglReleaseTexImage")
+	}
+	gpReleaseThread = (C.GPRELEASETHREAD)(getProcAddr("eglReleaseThread"))
+	if gpReleaseThread == nil {
+		return errors.New("eglReleaseThread")
+	}
+	gpSignalSyncKHR = (C.GPSIGNALSYNCKHR)(getProcAddr("eglSignalSyncKHR"))
+	gpSurfaceAttrib = (C.GPSURFACEATTRIB)(getProcAddr("eglSurfaceAttrib"))
+	if gpSurfaceAttrib == nil {
+		return errors.New("eglSurfaceAttrib")
+	}
+	gpSwapBuffers = (C.GPSWAPBUFFERS)(getProcAddr("eglSwapBuffers"))
+	if gpSwapBuffers == nil {
+		return errors.New("eglSwapBuffers")
+	}
+	gpSwapInterval = (C.GPSWAPINTERVAL)(getProcAddr("eglSwapInterval"))
+	if gpSwapInterval == nil {
+		return errors.New("eglSwapInterval")
+	}
+	gpTerminate = (C.GPTERMINATE)(getProcAddr("eglTerminate"))
+	if gpTerminate == nil {
+		return errors.New("eglTerminate")
+	}
+	gpUnlockSurfaceKHR = (C.GPUNLOCKSURFACEKHR)(getProcAddr("eglUnlockSurfaceKHR"))
+	gpWaitClient = (C.GPWAITCLIENT)(getProcAddr("eglWaitClient"))
+	if gpWaitClient == nil {
+		return errors.New("eglWaitClient")
+	}
+	gpWaitGL = (C.GPWAITGL)(getProcAddr("eglWaitGL"))
+	if gpWaitGL == nil {
+		return errors.New("eglWaitGL")
+	}
+	gpWaitNative = (C.GPWAITNATIVE)(getProcAddr("eglWaitNative"))
+	if gpWaitNative == nil {
+		return errors.New("eglWaitNative")
+	}
+	return nil
+}
@@ -0,0 +1,12 @@
+// Code generated by glowgen. DO NOT EDIT.
+
+package egl
+
+import "unsafe"
+
+// this is just to satisfy egl.Init()! instead call:
+// egl.InitWithProcAddrFunc( piglet.GetProcAddress )
+
+func getProcAddress(string) unsafe.Pointer {
+	panic("DO NOT CALL egl.getProcAddress!! IT WILL CRASH!!")
+}
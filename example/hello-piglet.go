@@ -7,9 +7,13 @@ import (
 	"fmt"
 	"github.com/FEEDFACE-COM/piglet"
 	gl "github.com/FEEDFACE-COM/piglet/gles2"
+	"github.com/FEEDFACE-COM/piglet/glstate"
+	"github.com/FEEDFACE-COM/piglet/glx"
+	"github.com/FEEDFACE-COM/piglet/input"
+	"github.com/FEEDFACE-COM/piglet/overlay"
+	"github.com/FEEDFACE-COM/piglet/texture"
 	"github.com/go-gl/mathgl/mgl32"
 	"image"
-	"image/draw"
 	_ "image/png"
 	"math"
 	"os"
@@ -21,28 +25,28 @@ import (
 
 /* draw ***********************************************************************/
 
-func UpdateScene(shaderProgram uint32, camera mgl32.Mat4, startTime time.Time) {
+func UpdateScene(program *glx.Program, camera mgl32.Mat4, startTime time.Time) {
 
 	clock := float32(time.Now().Sub(startTime).Seconds())
 
 	angle := PI / 4. * Sin(clock)
 	cam := camera.Mul4(mgl32.HomogRotate3DY(angle))
 
-	gl.UseProgram(shaderProgram)
-	ptr := gl.GetUniformLocation(shaderProgram, gl.Str("camera\x00"))
-	gl.UniformMatrix4fv(ptr, 1, false, &cam[0])
+	program.Use()
+	if err := program.SetUniform("camera", cam); err != nil {
+		Error("%s", err)
+	}
 
 }
 
-func DrawScene(shaderProgram, vertexBuffer, textureName uint32) {
+func DrawScene(program *glx.Program, vertexBuffer *glx.VertexBuffer, tex glx.Texture) {
 
 	gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
 
-	gl.UseProgram(shaderProgram)
-	gl.BindBuffer(gl.ARRAY_BUFFER, vertexBuffer)
-	gl.BindTexture(gl.TEXTURE_2D, textureName)
+	program.Use()
+	tex.Bind()
 
-	gl.DrawArrays(gl.TRIANGLES, 0, 2*3)
+	vertexBuffer.Draw(program, glx.Triangles)
 
 	err := gl.GetError()
 	if err != gl.NO_ERROR {
@@ -53,16 +57,14 @@ func DrawScene(shaderProgram, vertexBuffer, textureName uint32) {
 
 /* init ***********************************************************************/
 
-func InitScene(width, height int32) (uint32, uint32, uint32, mgl32.Mat4, time.Time) {
+func InitScene(width, height int32) (*glx.Program, *glx.VertexBuffer, glx.Texture, mgl32.Mat4, time.Time) {
 	Notice("init scene..")
 
 	cameraMatrix := InitCameraMatrix(float32(width), float32(height))
 	vertexBuffer := InitVertexBuffer()
-	textureName := InitTextureName()
+	tex := InitTexture()
 
-	vertexShader := InitShader(VertexSource, gl.VERTEX_SHADER)
-	fragmentShader := InitShader(FragmentSource, gl.FRAGMENT_SHADER)
-	shaderProgram := InitShaderProgram(vertexShader, fragmentShader)
+	shaderProgram := InitShaderProgram(VertexSource, FragmentSource)
 
 	gl.Viewport(0, 0, width, height)
 	gl.ClearColor(0., 0., 0., 0.)
@@ -73,7 +75,7 @@ func InitScene(width, height int32) (uint32, uint32, uint32, mgl32.Mat4, time.Ti
 
 	startTime := time.Now()
 
-	return shaderProgram, vertexBuffer, textureName, cameraMatrix, startTime
+	return shaderProgram, vertexBuffer, tex, cameraMatrix, startTime
 }
 
 func InitCameraMatrix(width, height float32) mgl32.Mat4 {
@@ -93,11 +95,11 @@ func InitCameraMatrix(width, height float32) mgl32.Mat4 {
 	return cameraMatrix
 }
 
-func InitVertexBuffer() uint32 {
-	var vertexBuffer uint32
-	gl.GenBuffers(1, &vertexBuffer)
-	gl.BindBuffer(gl.ARRAY_BUFFER, vertexBuffer)
-	gl.BufferData(gl.ARRAY_BUFFER, len(VertexData)*4, gl.Ptr(VertexData), gl.STATIC_DRAW)
+func InitVertexBuffer() *glx.VertexBuffer {
+	vertexBuffer := glx.NewVertexBuffer(VertexLayout, glx.StaticDraw)
+	if err := vertexBuffer.SetVertices(VertexData); err != nil {
+		Error("%s", err)
+	}
 
 	if gl.GetError() != gl.NO_ERROR {
 		Error("fail init buffer")
@@ -106,67 +108,33 @@ func InitVertexBuffer() uint32 {
 	return vertexBuffer
 }
 
-func InitTextureName() uint32 {
-	var textureName uint32
-
+func InitTexture() glx.Texture {
 	reader := base64.NewDecoder(base64.StdEncoding, strings.NewReader(TextureData))
-	img, _, _ := image.Decode(reader)
-	textureRGBA := image.NewRGBA(img.Bounds())
-	draw.Draw(textureRGBA, textureRGBA.Bounds(), img, image.Point{0, 0}, draw.Src)
-	w, h := int32(textureRGBA.Rect.Size().X), int32(textureRGBA.Rect.Size().Y)
-	ptr := gl.Ptr(textureRGBA.Pix)
+	img, _, err := image.Decode(reader)
+	if err != nil {
+		Error("fail decode texture: %s", err)
+	}
 
 	gl.ActiveTexture(gl.TEXTURE0)
-	gl.GenTextures(1, &textureName)
-	gl.BindTexture(gl.TEXTURE_2D, textureName)
-	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MIN_FILTER, gl.LINEAR)
-	gl.TexParameteri(gl.TEXTURE_2D, gl.TEXTURE_MAG_FILTER, gl.LINEAR)
-	gl.TexImage2D(gl.TEXTURE_2D, 0, gl.RGBA, w, h, 0, gl.RGBA, gl.UNSIGNED_BYTE, ptr)
+	tex := texture.Load(img, texture.Options{Format: texture.RGBA, NoFlip: true})
 
 	if gl.GetError() != gl.NO_ERROR {
 		Error("fail init texture")
 	}
-	return textureName
+	return tex
 }
 
-func InitShader(source string, xtype uint32) uint32 {
-	var status int32
-	shader := gl.CreateShader(xtype)
-	src, free := gl.Strs(source + "\x00")
-	defer free()
-	gl.ShaderSource(shader, 1, src, nil)
-	gl.CompileShader(shader)
-	gl.GetShaderiv(shader, gl.COMPILE_STATUS, &status)
-	if status == gl.FALSE {
-		Error("fail compile shader")
+func InitShaderProgram(vertexSource, fragmentSource string) *glx.Program {
+	program, err := glx.CompileProgram(vertexSource, fragmentSource)
+	if err != nil {
+		Error("%s", err)
 	}
-	return shader
-}
 
-func InitShaderProgram(vertexShader, fragmentShader uint32) uint32 {
-	var status int32
-	shaderProgram := gl.CreateProgram()
-	gl.AttachShader(shaderProgram, vertexShader)
-	gl.AttachShader(shaderProgram, fragmentShader)
-	gl.LinkProgram(shaderProgram)
-	gl.GetProgramiv(shaderProgram, gl.LINK_STATUS, &status)
-	if status == gl.FALSE {
-		Error("fail link program")
+	if missing := VertexLayout.Missing(program); len(missing) > 0 {
+		Error("fail init shader: vertex layout lacks %s", strings.Join(missing, ", "))
 	}
 
-	ptr := gl.GetAttribLocation(shaderProgram, gl.Str("position\x00"))
-	gl.EnableVertexAttribArray(uint32(ptr))
-	gl.VertexAttribPointer(uint32(ptr), 3, gl.FLOAT, false, (3+2+4)*4, gl.PtrOffset(0*4))
-
-	ptr = gl.GetAttribLocation(shaderProgram, gl.Str("texCoord\x00"))
-	gl.EnableVertexAttribArray(uint32(ptr))
-	gl.VertexAttribPointer(uint32(ptr), 2, gl.FLOAT, false, (3+2+4)*4, gl.PtrOffset(3*4))
-
-	ptr = gl.GetAttribLocation(shaderProgram, gl.Str("color\x00"))
-	gl.EnableVertexAttribArray(uint32(ptr))
-	gl.VertexAttribPointer(uint32(ptr), 4, gl.FLOAT, false, (3+2+4)*4, gl.PtrOffset((3+2)*4))
-
-	return shaderProgram
+	return program
 }
 
 /* context ********************************************************************/
@@ -183,6 +151,7 @@ func ConfigureContext() (int32, int32) {
 
 	piglet.MakeCurrent()
 	gl.InitWithProcAddrFunc(piglet.GetProcAddress)
+	glx.UseStateCache(glstate.New()) // skip rebinding the same program, buffer and texture every frame
 	Notice("renderer: %s %s", gl.GoStr(gl.GetString((gl.VENDOR))), gl.GoStr(gl.GetString((gl.RENDERER))))
 	Notice("version: %s / %s", gl.GoStr(gl.GetString((gl.VERSION))), gl.GoStr(gl.GetString((gl.SHADING_LANGUAGE_VERSION))))
 
@@ -218,6 +187,15 @@ func main() {
 	// configure opengl context
 	width, height := ConfigureContext()
 
+	// frame statistics, shown with PIGLET_OVERLAY=1 or toggled with F3
+	stats, err := overlay.New(int(width), int(height))
+	if err != nil {
+		Error("fail to create overlay: %s", err)
+	}
+
+	// handle escape key, overlay key and mouse cursor
+	RegisterInputHandler(tickChan, width, height, stats)
+
 	// init scene
 	program, buffer, texture, camera, startTime := InitScene(width, height)
 
@@ -230,12 +208,14 @@ func main() {
 
 		UpdateScene(program, camera, startTime)
 		DrawScene(program, buffer, texture)
+		stats.Draw()
 		piglet.SwapBuffers()
 
 		ever = WaitForTick(tickChan)
 
 	}
 
+	stats.Delete()
 	TerminateContext()
 
 	os.Exit(0)
@@ -289,6 +269,41 @@ func RegisterSignalHandler(tickChan chan bool) {
 	}()
 }
 
+func RegisterInputHandler(tickChan chan bool, width, height int32, stats *overlay.Overlay) {
+	in, err := input.Open(input.Options{Display: input.Display{Width: int(width), Height: int(height)}, Hotplug: true})
+	if err != nil {
+		Notice("no input: %s", err)
+		return
+	}
+	// hidden until a mouse moves
+	if err := piglet.CreateCursor(piglet.ArrowCursor()); err != nil {
+		Notice("no cursor: %s", err)
+	}
+	piglet.ShowCursor(false)
+	cursor := false
+	go func() {
+		for e := range in.Events() {
+			if stats.HandleEvent(e) {
+				continue
+			}
+			switch e := e.(type) {
+			case *input.MouseMoveEvent:
+				piglet.MoveCursor(int(e.X), int(e.Y))
+				if !cursor {
+					piglet.ShowCursor(true)
+					cursor = true
+				}
+			case *input.KeyEvent:
+				if e.Action == input.KeyPress && (e.Key == input.KeyEsc || e.Rune == 'q') {
+					Notice("quit.")
+					tickChan <- false
+					return
+				}
+			}
+		}
+	}()
+}
+
 func Notice(format string, args ...interface{}) { fmt.Fprintf(os.Stderr, format+"\n", args...) }
 func Error(format string, args ...interface{}) {
 	fmt.Fprintf(os.Stderr, "ERROR: "+format+"\n", args...)
@@ -297,6 +312,12 @@ func Error(format string, args ...interface{}) {
 
 /* data ***********************************************************************/
 
+var VertexLayout = glx.NewLayout(
+	glx.Attribute{Name: "position", Size: 3},
+	glx.Attribute{Name: "texCoord", Size: 2},
+	glx.Attribute{Name: "color", Size: 4},
+)
+
 var VertexData = []float32{
 
 	// position       texCoord color
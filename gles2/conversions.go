@@ -1,4 +1,4 @@
-// Code generated by glow (https://github.com/go-gl/glow). DO NOT EDIT.
+// Code generated by glowgen. DO NOT EDIT.
 
 package gles2
 
@@ -17,9 +17,9 @@ import "C"
 //
 // For example:
 //
-// 	var data []uint8
-// 	...
-// 	gl.TexImage2D(gl.TEXTURE_2D, ..., gl.UNSIGNED_BYTE, gl.Ptr(&data[0]))
+//	var data []uint8
+//	...
+//	gl.TexImage2D(gl.TEXTURE_2D, ..., gl.UNSIGNED_BYTE, gl.Ptr(&data[0]))
 func Ptr(data interface{}) unsafe.Pointer {
 	if data == nil {
 		return unsafe.Pointer(nil)
@@ -52,7 +52,8 @@ func Ptr(data interface{}) unsafe.Pointer {
 // Useful for functions such as glVertexAttribPointer that take pointer
 // parameters indicating an offset rather than an absolute memory address.
 func PtrOffset(offset int) unsafe.Pointer {
-	return unsafe.Pointer(uintptr(offset))
+	ptr := uintptr(offset)
+	return *(*unsafe.Pointer)(unsafe.Pointer(&ptr))
 }
 
 // Str takes a null-terminated Go string and returns its GL-compatible address.
@@ -92,11 +93,7 @@ func Strs(strs ...string) (cstrs **uint8, free func()) {
 	data := C.malloc(C.size_t(n))
 
 	// Copy all the strings into data.
-	dataSlice := *(*[]byte)(unsafe.Pointer(&reflect.SliceHeader{
-		Data: uintptr(data),
-		Len:  n,
-		Cap:  n,
-	}))
+	dataSlice := (*[1 << 30]byte)(data)[:n:n]
 	css := make([]*uint8, len(strs)) // Populated with pointers to each string.
 	offset := 0
 	for i := range strs {
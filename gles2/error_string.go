@@ -1,14 +1,22 @@
+// Code generated by glowgen. DO NOT EDIT.
 
 package gles2
 
+// Return the name of an error code
 func ErrorString(error uint32) string {
-    switch (error) {
-        case NO_ERROR:                      return "NO_ERROR"
-        case INVALID_ENUM:                  return "INVALID_ENUM"
-        case INVALID_VALUE:                 return "INVALID_VALUE"
-        case INVALID_OPERATION:             return "INVALID_OPERATION"
-        case INVALID_FRAMEBUFFER_OPERATION: return "INVALID_FRAMEBUFFER_OPERATION"
-    }
-    return "UNKNOWN"
+	switch error {
+	case NO_ERROR:
+		return "NO_ERROR"
+	case INVALID_ENUM:
+		return "INVALID_ENUM"
+	case INVALID_VALUE:
+		return "INVALID_VALUE"
+	case INVALID_OPERATION:
+		return "INVALID_OPERATION"
+	case INVALID_FRAMEBUFFER_OPERATION:
+		return "INVALID_FRAMEBUFFER_OPERATION"
+	case OUT_OF_MEMORY:
+		return "OUT_OF_MEMORY"
+	}
+	return "UNKNOWN"
 }
-
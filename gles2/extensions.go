@@ -0,0 +1,38 @@
+package gles2
+
+import (
+	"strings"
+	"sync"
+)
+
+var extensions struct {
+	mu    sync.Mutex
+	names map[string]bool // of the current context, nil until parsed
+}
+
+// HasExtension reports whether the current context advertises the named
+// extension, eg. HasExtension("GL_OES_mapbuffer"). The extension string is
+// parsed on first use, so call this only after InitWithProcAddrFunc.
+func HasExtension(name string) bool {
+	extensions.mu.Lock()
+	defer extensions.mu.Unlock()
+	if extensions.names == nil {
+		str := GetString(EXTENSIONS)
+		if str == nil {
+			return false
+		}
+		extensions.names = make(map[string]bool)
+		for _, ext := range strings.Fields(GoStr(str)) {
+			extensions.names[ext] = true
+		}
+	}
+	return extensions.names[name]
+}
+
+// ResetExtensions forgets the parsed extension string, so HasExtension asks
+// the next context again. piglet.CreateContext and DestroyContext call it.
+func ResetExtensions() {
+	extensions.mu.Lock()
+	extensions.names = nil
+	extensions.mu.Unlock()
+}
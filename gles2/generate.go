@@ -0,0 +1,3 @@
+package gles2
+
+//go:generate go run ../internal/glowgen -api=gles2
@@ -1,4 +1,4 @@
-// Code generated by glow (https://github.com/go-gl/glow). DO NOT EDIT.
+// Code generated by glowgen. DO NOT EDIT.
 
 // Copyright (c) 2010 Khronos Group.
 // This material may be distributed subject to the terms and conditions
@@ -11,17 +11,13 @@
 
 // Package gles2 implements Go bindings to OpenGL.
 //
-// This package was automatically generated using Glow:
-//  https://github.com/go-gl/glow
-//
+// This package was generated from the Khronos XML API registry by glowgen,
+// a cut-down port of Glow (https://github.com/go-gl/glow).
 package gles2
 
 // #cgo linux,arm  CFLAGS: -I/opt/vc/include
 // #cgo linux,arm LDFLAGS: -L/opt/vc/lib
 //
-//
-//
-//
 // #if defined(_WIN32) && !defined(APIENTRY) && !defined(__CYGWIN__) && !defined(__SCITECH_SNAP__)
 // #ifndef WIN32_LEAN_AND_MEAN
 // #define WIN32_LEAN_AND_MEAN 1
@@ -49,6 +45,7 @@ package gles2
 // typedef char GLchar;
 // typedef khronos_intptr_t GLintptr;
 // typedef khronos_ssize_t GLsizeiptr;
+// typedef void *GLeglImageOES;
 // typedef void  (APIENTRYP GPACTIVETEXTURE)(GLenum  texture);
 // typedef void  (APIENTRYP GPATTACHSHADER)(GLuint  program, GLuint  shader);
 // typedef void  (APIENTRYP GPBINDATTRIBLOCATION)(GLuint  program, GLuint  index, const GLchar * name);
@@ -56,6 +53,7 @@ package gles2
 // typedef void  (APIENTRYP GPBINDFRAMEBUFFER)(GLenum  target, GLuint  framebuffer);
 // typedef void  (APIENTRYP GPBINDRENDERBUFFER)(GLenum  target, GLuint  renderbuffer);
 // typedef void  (APIENTRYP GPBINDTEXTURE)(GLenum  target, GLuint  texture);
+// typedef void  (APIENTRYP GPBINDVERTEXARRAYOES)(GLuint  array);
 // typedef void  (APIENTRYP GPBLENDCOLOR)(GLfloat  red, GLfloat  green, GLfloat  blue, GLfloat  alpha);
 // typedef void  (APIENTRYP GPBLENDEQUATION)(GLenum  mode);
 // typedef void  (APIENTRYP GPBLENDEQUATIONSEPARATE)(GLenum  modeRGB, GLenum  modeAlpha);
@@ -83,6 +81,7 @@ package gles2
 // typedef void  (APIENTRYP GPDELETERENDERBUFFERS)(GLsizei  n, const GLuint * renderbuffers);
 // typedef void  (APIENTRYP GPDELETESHADER)(GLuint  shader);
 // typedef void  (APIENTRYP GPDELETETEXTURES)(GLsizei  n, const GLuint * textures);
+// typedef void  (APIENTRYP GPDELETEVERTEXARRAYSOES)(GLsizei  n, const GLuint * arrays);
 // typedef void  (APIENTRYP GPDEPTHFUNC)(GLenum  func);
 // typedef void  (APIENTRYP GPDEPTHMASK)(GLboolean  flag);
 // typedef void  (APIENTRYP GPDEPTHRANGEF)(GLfloat  n, GLfloat  f);
@@ -92,6 +91,8 @@ package gles2
 // typedef void  (APIENTRYP GPDISCARDFRAMEBUFFEREXT)(GLenum  target, GLsizei  numAttachments, const GLenum * attachments);
 // typedef void  (APIENTRYP GPDRAWARRAYS)(GLenum  mode, GLint  first, GLsizei  count);
 // typedef void  (APIENTRYP GPDRAWELEMENTS)(GLenum  mode, GLsizei  count, GLenum  type, const void * indices);
+// typedef void  (APIENTRYP GPEGLIMAGETARGETRENDERBUFFERSTORAGEOES)(GLenum  target, GLeglImageOES  image);
+// typedef void  (APIENTRYP GPEGLIMAGETARGETTEXTURE2DOES)(GLenum  target, GLeglImageOES  image);
 // typedef void  (APIENTRYP GPENABLE)(GLenum  cap);
 // typedef void  (APIENTRYP GPENABLEVERTEXATTRIBARRAY)(GLuint  index);
 // typedef void  (APIENTRYP GPFINISH)();
@@ -103,6 +104,7 @@ package gles2
 // typedef void  (APIENTRYP GPGENFRAMEBUFFERS)(GLsizei  n, GLuint * framebuffers);
 // typedef void  (APIENTRYP GPGENRENDERBUFFERS)(GLsizei  n, GLuint * renderbuffers);
 // typedef void  (APIENTRYP GPGENTEXTURES)(GLsizei  n, GLuint * textures);
+// typedef void  (APIENTRYP GPGENVERTEXARRAYSOES)(GLsizei  n, GLuint * arrays);
 // typedef void  (APIENTRYP GPGENERATEMIPMAP)(GLenum  target);
 // typedef void  (APIENTRYP GPGETACTIVEATTRIB)(GLuint  program, GLuint  index, GLsizei  bufSize, GLsizei * length, GLint * size, GLenum * type, GLchar * name);
 // typedef void  (APIENTRYP GPGETACTIVEUNIFORM)(GLuint  program, GLuint  index, GLsizei  bufSize, GLsizei * length, GLint * size, GLenum * type, GLchar * name);
@@ -110,6 +112,7 @@ package gles2
 // typedef GLint  (APIENTRYP GPGETATTRIBLOCATION)(GLuint  program, const GLchar * name);
 // typedef void  (APIENTRYP GPGETBOOLEANV)(GLenum  pname, GLboolean * data);
 // typedef void  (APIENTRYP GPGETBUFFERPARAMETERIV)(GLenum  target, GLenum  pname, GLint * params);
+// typedef void  (APIENTRYP GPGETBUFFERPOINTERVOES)(GLenum  target, GLenum  pname, void ** params);
 // typedef GLenum  (APIENTRYP GPGETERROR)();
 // typedef void  (APIENTRYP GPGETFLOATV)(GLenum  pname, GLfloat * data);
 // typedef void  (APIENTRYP GPGETFRAMEBUFFERATTACHMENTPARAMETERIV)(GLenum  target, GLenum  attachment, GLenum  pname, GLint * params);
@@ -131,6 +134,7 @@ package gles2
 // typedef void  (APIENTRYP GPGETVERTEXATTRIBFV)(GLuint  index, GLenum  pname, GLfloat * params);
 // typedef void  (APIENTRYP GPGETVERTEXATTRIBIV)(GLuint  index, GLenum  pname, GLint * params);
 // typedef void  (APIENTRYP GPHINT)(GLenum  target, GLenum  mode);
+// typedef void  (APIENTRYP GPINSERTEVENTMARKEREXT)(GLsizei  length, const GLchar * marker);
 // typedef GLboolean  (APIENTRYP GPISBUFFER)(GLuint  buffer);
 // typedef GLboolean  (APIENTRYP GPISENABLED)(GLenum  cap);
 // typedef GLboolean  (APIENTRYP GPISFRAMEBUFFER)(GLuint  framebuffer);
@@ -138,10 +142,14 @@ package gles2
 // typedef GLboolean  (APIENTRYP GPISRENDERBUFFER)(GLuint  renderbuffer);
 // typedef GLboolean  (APIENTRYP GPISSHADER)(GLuint  shader);
 // typedef GLboolean  (APIENTRYP GPISTEXTURE)(GLuint  texture);
+// typedef GLboolean  (APIENTRYP GPISVERTEXARRAYOES)(GLuint  array);
 // typedef void  (APIENTRYP GPLINEWIDTH)(GLfloat  width);
 // typedef void  (APIENTRYP GPLINKPROGRAM)(GLuint  program);
+// typedef void * (APIENTRYP GPMAPBUFFEROES)(GLenum  target, GLenum  access);
 // typedef void  (APIENTRYP GPPIXELSTOREI)(GLenum  pname, GLint  param);
 // typedef void  (APIENTRYP GPPOLYGONOFFSET)(GLfloat  factor, GLfloat  units);
+// typedef void  (APIENTRYP GPPOPGROUPMARKEREXT)();
+// typedef void  (APIENTRYP GPPUSHGROUPMARKEREXT)(GLsizei  length, const GLchar * marker);
 // typedef void  (APIENTRYP GPREADPIXELS)(GLint  x, GLint  y, GLsizei  width, GLsizei  height, GLenum  format, GLenum  type, void * pixels);
 // typedef void  (APIENTRYP GPRELEASESHADERCOMPILER)();
 // typedef void  (APIENTRYP GPRENDERBUFFERSTORAGE)(GLenum  target, GLenum  internalformat, GLsizei  width, GLsizei  height);
@@ -180,6 +188,7 @@ package gles2
 // typedef void  (APIENTRYP GPUNIFORMMATRIX2FV)(GLint  location, GLsizei  count, GLboolean  transpose, const GLfloat * value);
 // typedef void  (APIENTRYP GPUNIFORMMATRIX3FV)(GLint  location, GLsizei  count, GLboolean  transpose, const GLfloat * value);
 // typedef void  (APIENTRYP GPUNIFORMMATRIX4FV)(GLint  location, GLsizei  count, GLboolean  transpose, const GLfloat * value);
+// typedef GLboolean  (APIENTRYP GPUNMAPBUFFEROES)(GLenum  target);
 // typedef void  (APIENTRYP GPUSEPROGRAM)(GLuint  program);
 // typedef void  (APIENTRYP GPVALIDATEPROGRAM)(GLuint  program);
 // typedef void  (APIENTRYP GPVERTEXATTRIB1F)(GLuint  index, GLfloat  x);
@@ -213,6 +222,9 @@ package gles2
 // static void  glowBindTexture(GPBINDTEXTURE fnptr, GLenum  target, GLuint  texture) {
 //   (*fnptr)(target, texture);
 // }
+// static void  glowBindVertexArrayOES(GPBINDVERTEXARRAYOES fnptr, GLuint  array) {
+//   (*fnptr)(array);
+// }
 // static void  glowBlendColor(GPBLENDCOLOR fnptr, GLfloat  red, GLfloat  green, GLfloat  blue, GLfloat  alpha) {
 //   (*fnptr)(red, green, blue, alpha);
 // }
@@ -294,6 +306,9 @@ package gles2
 // static void  glowDeleteTextures(GPDELETETEXTURES fnptr, GLsizei  n, const GLuint * textures) {
 //   (*fnptr)(n, textures);
 // }
+// static void  glowDeleteVertexArraysOES(GPDELETEVERTEXARRAYSOES fnptr, GLsizei  n, const GLuint * arrays) {
+//   (*fnptr)(n, arrays);
+// }
 // static void  glowDepthFunc(GPDEPTHFUNC fnptr, GLenum  func) {
 //   (*fnptr)(func);
 // }
@@ -321,6 +336,12 @@ package gles2
 // static void  glowDrawElements(GPDRAWELEMENTS fnptr, GLenum  mode, GLsizei  count, GLenum  type, const void * indices) {
 //   (*fnptr)(mode, count, type, indices);
 // }
+// static void  glowEGLImageTargetRenderbufferStorageOES(GPEGLIMAGETARGETRENDERBUFFERSTORAGEOES fnptr, GLenum  target, GLeglImageOES  image) {
+//   (*fnptr)(target, image);
+// }
+// static void  glowEGLImageTargetTexture2DOES(GPEGLIMAGETARGETTEXTURE2DOES fnptr, GLenum  target, GLeglImageOES  image) {
+//   (*fnptr)(target, image);
+// }
 // static void  glowEnable(GPENABLE fnptr, GLenum  cap) {
 //   (*fnptr)(cap);
 // }
@@ -354,6 +375,9 @@ package gles2
 // static void  glowGenTextures(GPGENTEXTURES fnptr, GLsizei  n, GLuint * textures) {
 //   (*fnptr)(n, textures);
 // }
+// static void  glowGenVertexArraysOES(GPGENVERTEXARRAYSOES fnptr, GLsizei  n, GLuint * arrays) {
+//   (*fnptr)(n, arrays);
+// }
 // static void  glowGenerateMipmap(GPGENERATEMIPMAP fnptr, GLenum  target) {
 //   (*fnptr)(target);
 // }
@@ -375,6 +399,9 @@ package gles2
 // static void  glowGetBufferParameteriv(GPGETBUFFERPARAMETERIV fnptr, GLenum  target, GLenum  pname, GLint * params) {
 //   (*fnptr)(target, pname, params);
 // }
+// static void  glowGetBufferPointervOES(GPGETBUFFERPOINTERVOES fnptr, GLenum  target, GLenum  pname, void ** params) {
+//   (*fnptr)(target, pname, params);
+// }
 // static GLenum  glowGetError(GPGETERROR fnptr) {
 //   return (*fnptr)();
 // }
@@ -438,6 +465,9 @@ package gles2
 // static void  glowHint(GPHINT fnptr, GLenum  target, GLenum  mode) {
 //   (*fnptr)(target, mode);
 // }
+// static void  glowInsertEventMarkerEXT(GPINSERTEVENTMARKEREXT fnptr, GLsizei  length, const GLchar * marker) {
+//   (*fnptr)(length, marker);
+// }
 // static GLboolean  glowIsBuffer(GPISBUFFER fnptr, GLuint  buffer) {
 //   return (*fnptr)(buffer);
 // }
@@ -459,18 +489,30 @@ package gles2
 // static GLboolean  glowIsTexture(GPISTEXTURE fnptr, GLuint  texture) {
 //   return (*fnptr)(texture);
 // }
+// static GLboolean  glowIsVertexArrayOES(GPISVERTEXARRAYOES fnptr, GLuint  array) {
+//   return (*fnptr)(array);
+// }
 // static void  glowLineWidth(GPLINEWIDTH fnptr, GLfloat  width) {
 //   (*fnptr)(width);
 // }
 // static void  glowLinkProgram(GPLINKPROGRAM fnptr, GLuint  program) {
 //   (*fnptr)(program);
 // }
+// static void * glowMapBufferOES(GPMAPBUFFEROES fnptr, GLenum  target, GLenum  access) {
+//   return (*fnptr)(target, access);
+// }
 // static void  glowPixelStorei(GPPIXELSTOREI fnptr, GLenum  pname, GLint  param) {
 //   (*fnptr)(pname, param);
 // }
 // static void  glowPolygonOffset(GPPOLYGONOFFSET fnptr, GLfloat  factor, GLfloat  units) {
 //   (*fnptr)(factor, units);
 // }
+// static void  glowPopGroupMarkerEXT(GPPOPGROUPMARKEREXT fnptr) {
+//   (*fnptr)();
+// }
+// static void  glowPushGroupMarkerEXT(GPPUSHGROUPMARKEREXT fnptr, GLsizei  length, const GLchar * marker) {
+//   (*fnptr)(length, marker);
+// }
 // static void  glowReadPixels(GPREADPIXELS fnptr, GLint  x, GLint  y, GLsizei  width, GLsizei  height, GLenum  format, GLenum  type, void * pixels) {
 //   (*fnptr)(x, y, width, height, format, type, pixels);
 // }
@@ -585,6 +627,9 @@ package gles2
 // static void  glowUniformMatrix4fv(GPUNIFORMMATRIX4FV fnptr, GLint  location, GLsizei  count, GLboolean  transpose, const GLfloat * value) {
 //   (*fnptr)(location, count, transpose, value);
 // }
+// static GLboolean  glowUnmapBufferOES(GPUNMAPBUFFEROES fnptr, GLenum  target) {
+//   return (*fnptr)(target);
+// }
 // static void  glowUseProgram(GPUSEPROGRAM fnptr, GLuint  program) {
 //   (*fnptr)(program);
 // }
@@ -642,6 +687,7 @@ const (
 	ARRAY_BUFFER_BINDING                         = 0x8894
 	ATTACHED_SHADERS                             = 0x8B85
 	BACK                                         = 0x0405
+	BGRA_EXT                                     = 0x80E1
 	BLEND                                        = 0x0BE2
 	BLEND_COLOR                                  = 0x8005
 	BLEND_DST_ALPHA                              = 0x80CA
@@ -656,6 +702,9 @@ const (
 	BOOL_VEC2                                    = 0x8B57
 	BOOL_VEC3                                    = 0x8B58
 	BOOL_VEC4                                    = 0x8B59
+	BUFFER_ACCESS_OES                            = 0x88BB
+	BUFFER_MAPPED_OES                            = 0x88BC
+	BUFFER_MAP_POINTER_OES                       = 0x88BD
 	BUFFER_SIZE                                  = 0x8764
 	BUFFER_USAGE                                 = 0x8765
 	BYTE                                         = 0x1400
@@ -678,15 +727,19 @@ const (
 	DECR                                         = 0x1E03
 	DECR_WRAP                                    = 0x8508
 	DELETE_STATUS                                = 0x8B80
+	DEPTH24_STENCIL8_OES                         = 0x88F0
 	DEPTH_ATTACHMENT                             = 0x8D00
 	DEPTH_BITS                                   = 0x0D56
 	DEPTH_BUFFER_BIT                             = 0x00000100
 	DEPTH_CLEAR_VALUE                            = 0x0B73
 	DEPTH_COMPONENT                              = 0x1902
 	DEPTH_COMPONENT16                            = 0x81A5
+	DEPTH_COMPONENT24_OES                        = 0x81A6
+	DEPTH_COMPONENT32_OES                        = 0x81A7
 	DEPTH_EXT                                    = 0x1801
 	DEPTH_FUNC                                   = 0x0B74
 	DEPTH_RANGE                                  = 0x0B70
+	DEPTH_STENCIL_OES                            = 0x84F9
 	DEPTH_TEST                                   = 0x0B71
 	DEPTH_WRITEMASK                              = 0x0B72
 	DITHER                                       = 0x0BD0
@@ -697,6 +750,7 @@ const (
 	ELEMENT_ARRAY_BUFFER                         = 0x8893
 	ELEMENT_ARRAY_BUFFER_BINDING                 = 0x8895
 	EQUAL                                        = 0x0202
+	ETC1_RGB8_OES                                = 0x8D64
 	EXTENSIONS                                   = 0x1F03
 	FALSE                                        = 0
 	FASTEST                                      = 0x1101
@@ -730,6 +784,7 @@ const (
 	GEQUAL                                       = 0x0206
 	GREATER                                      = 0x0204
 	GREEN_BITS                                   = 0x0D53
+	HALF_FLOAT_OES                               = 0x8D61
 	HIGH_FLOAT                                   = 0x8DF2
 	HIGH_INT                                     = 0x8DF5
 	IMPLEMENTATION_COLOR_READ_FORMAT             = 0x8B9B
@@ -794,6 +849,16 @@ const (
 	ONE_MINUS_SRC_COLOR                          = 0x0301
 	OUT_OF_MEMORY                                = 0x0505
 	PACK_ALIGNMENT                               = 0x0D05
+	PALETTE4_R5_G6_B5_OES                        = 0x8B92
+	PALETTE4_RGB5_A1_OES                         = 0x8B94
+	PALETTE4_RGB8_OES                            = 0x8B90
+	PALETTE4_RGBA4_OES                           = 0x8B93
+	PALETTE4_RGBA8_OES                           = 0x8B91
+	PALETTE8_R5_G6_B5_OES                        = 0x8B97
+	PALETTE8_RGB5_A1_OES                         = 0x8B99
+	PALETTE8_RGB8_OES                            = 0x8B95
+	PALETTE8_RGBA4_OES                           = 0x8B98
+	PALETTE8_RGBA8_OES                           = 0x8B96
 	POINTS                                       = 0x0000
 	POLYGON_OFFSET_FACTOR                        = 0x8038
 	POLYGON_OFFSET_FILL                          = 0x8037
@@ -813,13 +878,17 @@ const (
 	RENDERER                                     = 0x1F01
 	REPEAT                                       = 0x2901
 	REPLACE                                      = 0x1E01
+	REQUIRED_TEXTURE_IMAGE_UNITS_OES             = 0x8D68
 	RGB                                          = 0x1907
 	RGB565                                       = 0x8D62
 	RGB5_A1                                      = 0x8057
+	RGB8_OES                                     = 0x8051
 	RGBA                                         = 0x1908
 	RGBA4                                        = 0x8056
+	RGBA8_OES                                    = 0x8058
 	SAMPLER_2D                                   = 0x8B5E
 	SAMPLER_CUBE                                 = 0x8B60
+	SAMPLER_EXTERNAL_OES                         = 0x8D66
 	SAMPLES                                      = 0x80A9
 	SAMPLE_ALPHA_TO_COVERAGE                     = 0x809E
 	SAMPLE_BUFFERS                               = 0x80A8
@@ -897,6 +966,7 @@ const (
 	TEXTURE_2D                                   = 0x0DE1
 	TEXTURE_BINDING_2D                           = 0x8069
 	TEXTURE_BINDING_CUBE_MAP                     = 0x8514
+	TEXTURE_BINDING_EXTERNAL_OES                 = 0x8D67
 	TEXTURE_CUBE_MAP                             = 0x8513
 	TEXTURE_CUBE_MAP_NEGATIVE_X                  = 0x8516
 	TEXTURE_CUBE_MAP_NEGATIVE_Y                  = 0x8518
@@ -904,6 +974,7 @@ const (
 	TEXTURE_CUBE_MAP_POSITIVE_X                  = 0x8515
 	TEXTURE_CUBE_MAP_POSITIVE_Y                  = 0x8517
 	TEXTURE_CUBE_MAP_POSITIVE_Z                  = 0x8519
+	TEXTURE_EXTERNAL_OES                         = 0x8D65
 	TEXTURE_MAG_FILTER                           = 0x2800
 	TEXTURE_MIN_FILTER                           = 0x2801
 	TEXTURE_WRAP_S                               = 0x2802
@@ -915,6 +986,7 @@ const (
 	UNPACK_ALIGNMENT                             = 0x0CF5
 	UNSIGNED_BYTE                                = 0x1401
 	UNSIGNED_INT                                 = 0x1405
+	UNSIGNED_INT_24_8_OES                        = 0x84FA
 	UNSIGNED_SHORT                               = 0x1403
 	UNSIGNED_SHORT_4_4_4_4                       = 0x8033
 	UNSIGNED_SHORT_5_5_5_1                       = 0x8034
@@ -922,6 +994,7 @@ const (
 	VALIDATE_STATUS                              = 0x8B83
 	VENDOR                                       = 0x1F00
 	VERSION                                      = 0x1F02
+	VERTEX_ARRAY_BINDING_OES                     = 0x85B5
 	VERTEX_ATTRIB_ARRAY_BUFFER_BINDING           = 0x889F
 	VERTEX_ATTRIB_ARRAY_ENABLED                  = 0x8622
 	VERTEX_ATTRIB_ARRAY_NORMALIZED               = 0x886A
@@ -931,153 +1004,166 @@ const (
 	VERTEX_ATTRIB_ARRAY_TYPE                     = 0x8625
 	VERTEX_SHADER                                = 0x8B31
 	VIEWPORT                                     = 0x0BA2
+	WRITE_ONLY_OES                               = 0x88B9
 	ZERO                                         = 0
 )
 
 var (
-	gpActiveTexture                       C.GPACTIVETEXTURE
-	gpAttachShader                        C.GPATTACHSHADER
-	gpBindAttribLocation                  C.GPBINDATTRIBLOCATION
-	gpBindBuffer                          C.GPBINDBUFFER
-	gpBindFramebuffer                     C.GPBINDFRAMEBUFFER
-	gpBindRenderbuffer                    C.GPBINDRENDERBUFFER
-	gpBindTexture                         C.GPBINDTEXTURE
-	gpBlendColor                          C.GPBLENDCOLOR
-	gpBlendEquation                       C.GPBLENDEQUATION
-	gpBlendEquationSeparate               C.GPBLENDEQUATIONSEPARATE
-	gpBlendFunc                           C.GPBLENDFUNC
-	gpBlendFuncSeparate                   C.GPBLENDFUNCSEPARATE
-	gpBufferData                          C.GPBUFFERDATA
-	gpBufferSubData                       C.GPBUFFERSUBDATA
-	gpCheckFramebufferStatus              C.GPCHECKFRAMEBUFFERSTATUS
-	gpClear                               C.GPCLEAR
-	gpClearColor                          C.GPCLEARCOLOR
-	gpClearDepthf                         C.GPCLEARDEPTHF
-	gpClearStencil                        C.GPCLEARSTENCIL
-	gpColorMask                           C.GPCOLORMASK
-	gpCompileShader                       C.GPCOMPILESHADER
-	gpCompressedTexImage2D                C.GPCOMPRESSEDTEXIMAGE2D
-	gpCompressedTexSubImage2D             C.GPCOMPRESSEDTEXSUBIMAGE2D
-	gpCopyTexImage2D                      C.GPCOPYTEXIMAGE2D
-	gpCopyTexSubImage2D                   C.GPCOPYTEXSUBIMAGE2D
-	gpCreateProgram                       C.GPCREATEPROGRAM
-	gpCreateShader                        C.GPCREATESHADER
-	gpCullFace                            C.GPCULLFACE
-	gpDeleteBuffers                       C.GPDELETEBUFFERS
-	gpDeleteFramebuffers                  C.GPDELETEFRAMEBUFFERS
-	gpDeleteProgram                       C.GPDELETEPROGRAM
-	gpDeleteRenderbuffers                 C.GPDELETERENDERBUFFERS
-	gpDeleteShader                        C.GPDELETESHADER
-	gpDeleteTextures                      C.GPDELETETEXTURES
-	gpDepthFunc                           C.GPDEPTHFUNC
-	gpDepthMask                           C.GPDEPTHMASK
-	gpDepthRangef                         C.GPDEPTHRANGEF
-	gpDetachShader                        C.GPDETACHSHADER
-	gpDisable                             C.GPDISABLE
-	gpDisableVertexAttribArray            C.GPDISABLEVERTEXATTRIBARRAY
-	gpDiscardFramebufferEXT               C.GPDISCARDFRAMEBUFFEREXT
-	gpDrawArrays                          C.GPDRAWARRAYS
-	gpDrawElements                        C.GPDRAWELEMENTS
-	gpEnable                              C.GPENABLE
-	gpEnableVertexAttribArray             C.GPENABLEVERTEXATTRIBARRAY
-	gpFinish                              C.GPFINISH
-	gpFlush                               C.GPFLUSH
-	gpFramebufferRenderbuffer             C.GPFRAMEBUFFERRENDERBUFFER
-	gpFramebufferTexture2D                C.GPFRAMEBUFFERTEXTURE2D
-	gpFrontFace                           C.GPFRONTFACE
-	gpGenBuffers                          C.GPGENBUFFERS
-	gpGenFramebuffers                     C.GPGENFRAMEBUFFERS
-	gpGenRenderbuffers                    C.GPGENRENDERBUFFERS
-	gpGenTextures                         C.GPGENTEXTURES
-	gpGenerateMipmap                      C.GPGENERATEMIPMAP
-	gpGetActiveAttrib                     C.GPGETACTIVEATTRIB
-	gpGetActiveUniform                    C.GPGETACTIVEUNIFORM
-	gpGetAttachedShaders                  C.GPGETATTACHEDSHADERS
-	gpGetAttribLocation                   C.GPGETATTRIBLOCATION
-	gpGetBooleanv                         C.GPGETBOOLEANV
-	gpGetBufferParameteriv                C.GPGETBUFFERPARAMETERIV
-	gpGetError                            C.GPGETERROR
-	gpGetFloatv                           C.GPGETFLOATV
-	gpGetFramebufferAttachmentParameteriv C.GPGETFRAMEBUFFERATTACHMENTPARAMETERIV
-	gpGetIntegerv                         C.GPGETINTEGERV
-	gpGetProgramInfoLog                   C.GPGETPROGRAMINFOLOG
-	gpGetProgramiv                        C.GPGETPROGRAMIV
-	gpGetRenderbufferParameteriv          C.GPGETRENDERBUFFERPARAMETERIV
-	gpGetShaderInfoLog                    C.GPGETSHADERINFOLOG
-	gpGetShaderPrecisionFormat            C.GPGETSHADERPRECISIONFORMAT
-	gpGetShaderSource                     C.GPGETSHADERSOURCE
-	gpGetShaderiv                         C.GPGETSHADERIV
-	gpGetString                           C.GPGETSTRING
-	gpGetTexParameterfv                   C.GPGETTEXPARAMETERFV
-	gpGetTexParameteriv                   C.GPGETTEXPARAMETERIV
-	gpGetUniformLocation                  C.GPGETUNIFORMLOCATION
-	gpGetUniformfv                        C.GPGETUNIFORMFV
-	gpGetUniformiv                        C.GPGETUNIFORMIV
-	gpGetVertexAttribPointerv             C.GPGETVERTEXATTRIBPOINTERV
-	gpGetVertexAttribfv                   C.GPGETVERTEXATTRIBFV
-	gpGetVertexAttribiv                   C.GPGETVERTEXATTRIBIV
-	gpHint                                C.GPHINT
-	gpIsBuffer                            C.GPISBUFFER
-	gpIsEnabled                           C.GPISENABLED
-	gpIsFramebuffer                       C.GPISFRAMEBUFFER
-	gpIsProgram                           C.GPISPROGRAM
-	gpIsRenderbuffer                      C.GPISRENDERBUFFER
-	gpIsShader                            C.GPISSHADER
-	gpIsTexture                           C.GPISTEXTURE
-	gpLineWidth                           C.GPLINEWIDTH
-	gpLinkProgram                         C.GPLINKPROGRAM
-	gpPixelStorei                         C.GPPIXELSTOREI
-	gpPolygonOffset                       C.GPPOLYGONOFFSET
-	gpReadPixels                          C.GPREADPIXELS
-	gpReleaseShaderCompiler               C.GPRELEASESHADERCOMPILER
-	gpRenderbufferStorage                 C.GPRENDERBUFFERSTORAGE
-	gpSampleCoverage                      C.GPSAMPLECOVERAGE
-	gpScissor                             C.GPSCISSOR
-	gpShaderBinary                        C.GPSHADERBINARY
-	gpShaderSource                        C.GPSHADERSOURCE
-	gpStencilFunc                         C.GPSTENCILFUNC
-	gpStencilFuncSeparate                 C.GPSTENCILFUNCSEPARATE
-	gpStencilMask                         C.GPSTENCILMASK
-	gpStencilMaskSeparate                 C.GPSTENCILMASKSEPARATE
-	gpStencilOp                           C.GPSTENCILOP
-	gpStencilOpSeparate                   C.GPSTENCILOPSEPARATE
-	gpTexImage2D                          C.GPTEXIMAGE2D
-	gpTexParameterf                       C.GPTEXPARAMETERF
-	gpTexParameterfv                      C.GPTEXPARAMETERFV
-	gpTexParameteri                       C.GPTEXPARAMETERI
-	gpTexParameteriv                      C.GPTEXPARAMETERIV
-	gpTexSubImage2D                       C.GPTEXSUBIMAGE2D
-	gpUniform1f                           C.GPUNIFORM1F
-	gpUniform1fv                          C.GPUNIFORM1FV
-	gpUniform1i                           C.GPUNIFORM1I
-	gpUniform1iv                          C.GPUNIFORM1IV
-	gpUniform2f                           C.GPUNIFORM2F
-	gpUniform2fv                          C.GPUNIFORM2FV
-	gpUniform2i                           C.GPUNIFORM2I
-	gpUniform2iv                          C.GPUNIFORM2IV
-	gpUniform3f                           C.GPUNIFORM3F
-	gpUniform3fv                          C.GPUNIFORM3FV
-	gpUniform3i                           C.GPUNIFORM3I
-	gpUniform3iv                          C.GPUNIFORM3IV
-	gpUniform4f                           C.GPUNIFORM4F
-	gpUniform4fv                          C.GPUNIFORM4FV
-	gpUniform4i                           C.GPUNIFORM4I
-	gpUniform4iv                          C.GPUNIFORM4IV
-	gpUniformMatrix2fv                    C.GPUNIFORMMATRIX2FV
-	gpUniformMatrix3fv                    C.GPUNIFORMMATRIX3FV
-	gpUniformMatrix4fv                    C.GPUNIFORMMATRIX4FV
-	gpUseProgram                          C.GPUSEPROGRAM
-	gpValidateProgram                     C.GPVALIDATEPROGRAM
-	gpVertexAttrib1f                      C.GPVERTEXATTRIB1F
-	gpVertexAttrib1fv                     C.GPVERTEXATTRIB1FV
-	gpVertexAttrib2f                      C.GPVERTEXATTRIB2F
-	gpVertexAttrib2fv                     C.GPVERTEXATTRIB2FV
-	gpVertexAttrib3f                      C.GPVERTEXATTRIB3F
-	gpVertexAttrib3fv                     C.GPVERTEXATTRIB3FV
-	gpVertexAttrib4f                      C.GPVERTEXATTRIB4F
-	gpVertexAttrib4fv                     C.GPVERTEXATTRIB4FV
-	gpVertexAttribPointer                 C.GPVERTEXATTRIBPOINTER
-	gpViewport                            C.GPVIEWPORT
+	gpActiveTexture                        C.GPACTIVETEXTURE
+	gpAttachShader                         C.GPATTACHSHADER
+	gpBindAttribLocation                   C.GPBINDATTRIBLOCATION
+	gpBindBuffer                           C.GPBINDBUFFER
+	gpBindFramebuffer                      C.GPBINDFRAMEBUFFER
+	gpBindRenderbuffer                     C.GPBINDRENDERBUFFER
+	gpBindTexture                          C.GPBINDTEXTURE
+	gpBindVertexArrayOES                   C.GPBINDVERTEXARRAYOES
+	gpBlendColor                           C.GPBLENDCOLOR
+	gpBlendEquation                        C.GPBLENDEQUATION
+	gpBlendEquationSeparate                C.GPBLENDEQUATIONSEPARATE
+	gpBlendFunc                            C.GPBLENDFUNC
+	gpBlendFuncSeparate                    C.GPBLENDFUNCSEPARATE
+	gpBufferData                           C.GPBUFFERDATA
+	gpBufferSubData                        C.GPBUFFERSUBDATA
+	gpCheckFramebufferStatus               C.GPCHECKFRAMEBUFFERSTATUS
+	gpClear                                C.GPCLEAR
+	gpClearColor                           C.GPCLEARCOLOR
+	gpClearDepthf                          C.GPCLEARDEPTHF
+	gpClearStencil                         C.GPCLEARSTENCIL
+	gpColorMask                            C.GPCOLORMASK
+	gpCompileShader                        C.GPCOMPILESHADER
+	gpCompressedTexImage2D                 C.GPCOMPRESSEDTEXIMAGE2D
+	gpCompressedTexSubImage2D              C.GPCOMPRESSEDTEXSUBIMAGE2D
+	gpCopyTexImage2D                       C.GPCOPYTEXIMAGE2D
+	gpCopyTexSubImage2D                    C.GPCOPYTEXSUBIMAGE2D
+	gpCreateProgram                        C.GPCREATEPROGRAM
+	gpCreateShader                         C.GPCREATESHADER
+	gpCullFace                             C.GPCULLFACE
+	gpDeleteBuffers                        C.GPDELETEBUFFERS
+	gpDeleteFramebuffers                   C.GPDELETEFRAMEBUFFERS
+	gpDeleteProgram                        C.GPDELETEPROGRAM
+	gpDeleteRenderbuffers                  C.GPDELETERENDERBUFFERS
+	gpDeleteShader                         C.GPDELETESHADER
+	gpDeleteTextures                       C.GPDELETETEXTURES
+	gpDeleteVertexArraysOES                C.GPDELETEVERTEXARRAYSOES
+	gpDepthFunc                            C.GPDEPTHFUNC
+	gpDepthMask                            C.GPDEPTHMASK
+	gpDepthRangef                          C.GPDEPTHRANGEF
+	gpDetachShader                         C.GPDETACHSHADER
+	gpDisable                              C.GPDISABLE
+	gpDisableVertexAttribArray             C.GPDISABLEVERTEXATTRIBARRAY
+	gpDiscardFramebufferEXT                C.GPDISCARDFRAMEBUFFEREXT
+	gpDrawArrays                           C.GPDRAWARRAYS
+	gpDrawElements                         C.GPDRAWELEMENTS
+	gpEGLImageTargetRenderbufferStorageOES C.GPEGLIMAGETARGETRENDERBUFFERSTORAGEOES
+	gpEGLImageTargetTexture2DOES           C.GPEGLIMAGETARGETTEXTURE2DOES
+	gpEnable                               C.GPENABLE
+	gpEnableVertexAttribArray              C.GPENABLEVERTEXATTRIBARRAY
+	gpFinish                               C.GPFINISH
+	gpFlush                                C.GPFLUSH
+	gpFramebufferRenderbuffer              C.GPFRAMEBUFFERRENDERBUFFER
+	gpFramebufferTexture2D                 C.GPFRAMEBUFFERTEXTURE2D
+	gpFrontFace                            C.GPFRONTFACE
+	gpGenBuffers                           C.GPGENBUFFERS
+	gpGenFramebuffers                      C.GPGENFRAMEBUFFERS
+	gpGenRenderbuffers                     C.GPGENRENDERBUFFERS
+	gpGenTextures                          C.GPGENTEXTURES
+	gpGenVertexArraysOES                   C.GPGENVERTEXARRAYSOES
+	gpGenerateMipmap                       C.GPGENERATEMIPMAP
+	gpGetActiveAttrib                      C.GPGETACTIVEATTRIB
+	gpGetActiveUniform                     C.GPGETACTIVEUNIFORM
+	gpGetAttachedShaders                   C.GPGETATTACHEDSHADERS
+	gpGetAttribLocation                    C.GPGETATTRIBLOCATION
+	gpGetBooleanv                          C.GPGETBOOLEANV
+	gpGetBufferParameteriv                 C.GPGETBUFFERPARAMETERIV
+	gpGetBufferPointervOES                 C.GPGETBUFFERPOINTERVOES
+	gpGetError                             C.GPGETERROR
+	gpGetFloatv                            C.GPGETFLOATV
+	gpGetFramebufferAttachmentParameteriv  C.GPGETFRAMEBUFFERATTACHMENTPARAMETERIV
+	gpGetIntegerv                          C.GPGETINTEGERV
+	gpGetProgramInfoLog                    C.GPGETPROGRAMINFOLOG
+	gpGetProgramiv                         C.GPGETPROGRAMIV
+	gpGetRenderbufferParameteriv           C.GPGETRENDERBUFFERPARAMETERIV
+	gpGetShaderInfoLog                     C.GPGETSHADERINFOLOG
+	gpGetShaderPrecisionFormat             C.GPGETSHADERPRECISIONFORMAT
+	gpGetShaderSource                      C.GPGETSHADERSOURCE
+	gpGetShaderiv                          C.GPGETSHADERIV
+	gpGetString                            C.GPGETSTRING
+	gpGetTexParameterfv                    C.GPGETTEXPARAMETERFV
+	gpGetTexParameteriv                    C.GPGETTEXPARAMETERIV
+	gpGetUniformLocation                   C.GPGETUNIFORMLOCATION
+	gpGetUniformfv                         C.GPGETUNIFORMFV
+	gpGetUniformiv                         C.GPGETUNIFORMIV
+	gpGetVertexAttribPointerv              C.GPGETVERTEXATTRIBPOINTERV
+	gpGetVertexAttribfv                    C.GPGETVERTEXATTRIBFV
+	gpGetVertexAttribiv                    C.GPGETVERTEXATTRIBIV
+	gpHint                                 C.GPHINT
+	gpInsertEventMarkerEXT                 C.GPINSERTEVENTMARKEREXT
+	gpIsBuffer                             C.GPISBUFFER
+	gpIsEnabled                            C.GPISENABLED
+	gpIsFramebuffer                        C.GPISFRAMEBUFFER
+	gpIsProgram                            C.GPISPROGRAM
+	gpIsRenderbuffer                       C.GPISRENDERBUFFER
+	gpIsShader                             C.GPISSHADER
+	gpIsTexture                            C.GPISTEXTURE
+	gpIsVertexArrayOES                     C.GPISVERTEXARRAYOES
+	gpLineWidth                            C.GPLINEWIDTH
+	gpLinkProgram                          C.GPLINKPROGRAM
+	gpMapBufferOES                         C.GPMAPBUFFEROES
+	gpPixelStorei                          C.GPPIXELSTOREI
+	gpPolygonOffset                        C.GPPOLYGONOFFSET
+	gpPopGroupMarkerEXT                    C.GPPOPGROUPMARKEREXT
+	gpPushGroupMarkerEXT                   C.GPPUSHGROUPMARKEREXT
+	gpReadPixels                           C.GPREADPIXELS
+	gpReleaseShaderCompiler                C.GPRELEASESHADERCOMPILER
+	gpRenderbufferStorage                  C.GPRENDERBUFFERSTORAGE
+	gpSampleCoverage                       C.GPSAMPLECOVERAGE
+	gpScissor                              C.GPSCISSOR
+	gpShaderBinary                         C.GPSHADERBINARY
+	gpShaderSource                         C.GPSHADERSOURCE
+	gpStencilFunc                          C.GPSTENCILFUNC
+	gpStencilFuncSeparate                  C.GPSTENCILFUNCSEPARATE
+	gpStencilMask                          C.GPSTENCILMASK
+	gpStencilMaskSeparate                  C.GPSTENCILMASKSEPARATE
+	gpStencilOp                            C.GPSTENCILOP
+	gpStencilOpSeparate                    C.GPSTENCILOPSEPARATE
+	gpTexImage2D                           C.GPTEXIMAGE2D
+	gpTexParameterf                        C.GPTEXPARAMETERF
+	gpTexParameterfv                       C.GPTEXPARAMETERFV
+	gpTexParameteri                        C.GPTEXPARAMETERI
+	gpTexParameteriv                       C.GPTEXPARAMETERIV
+	gpTexSubImage2D                        C.GPTEXSUBIMAGE2D
+	gpUniform1f                            C.GPUNIFORM1F
+	gpUniform1fv                           C.GPUNIFORM1FV
+	gpUniform1i                            C.GPUNIFORM1I
+	gpUniform1iv                           C.GPUNIFORM1IV
+	gpUniform2f                            C.GPUNIFORM2F
+	gpUniform2fv                           C.GPUNIFORM2FV
+	gpUniform2i                            C.GPUNIFORM2I
+	gpUniform2iv                           C.GPUNIFORM2IV
+	gpUniform3f                            C.GPUNIFORM3F
+	gpUniform3fv                           C.GPUNIFORM3FV
+	gpUniform3i                            C.GPUNIFORM3I
+	gpUniform3iv                           C.GPUNIFORM3IV
+	gpUniform4f                            C.GPUNIFORM4F
+	gpUniform4fv                           C.GPUNIFORM4FV
+	gpUniform4i                            C.GPUNIFORM4I
+	gpUniform4iv                           C.GPUNIFORM4IV
+	gpUniformMatrix2fv                     C.GPUNIFORMMATRIX2FV
+	gpUniformMatrix3fv                     C.GPUNIFORMMATRIX3FV
+	gpUniformMatrix4fv                     C.GPUNIFORMMATRIX4FV
+	gpUnmapBufferOES                       C.GPUNMAPBUFFEROES
+	gpUseProgram                           C.GPUSEPROGRAM
+	gpValidateProgram                      C.GPVALIDATEPROGRAM
+	gpVertexAttrib1f                       C.GPVERTEXATTRIB1F
+	gpVertexAttrib1fv                      C.GPVERTEXATTRIB1FV
+	gpVertexAttrib2f                       C.GPVERTEXATTRIB2F
+	gpVertexAttrib2fv                      C.GPVERTEXATTRIB2FV
+	gpVertexAttrib3f                       C.GPVERTEXATTRIB3F
+	gpVertexAttrib3fv                      C.GPVERTEXATTRIB3FV
+	gpVertexAttrib4f                       C.GPVERTEXATTRIB4F
+	gpVertexAttrib4fv                      C.GPVERTEXATTRIB4FV
+	gpVertexAttribPointer                  C.GPVERTEXATTRIBPOINTER
+	gpViewport                             C.GPVIEWPORT
 )
 
 // Helper functions
@@ -1091,6 +1177,9 @@ func boolToInt(b bool) int {
 // select active texture unit
 func ActiveTexture(texture uint32) {
 	C.glowActiveTexture(gpActiveTexture, (C.GLenum)(texture))
+	if Tracking() {
+		trackActiveTexture(texture)
+	}
 }
 
 // Attaches a shader object to a program object
@@ -1106,6 +1195,9 @@ func BindAttribLocation(program uint32, index uint32, name *uint8) {
 // bind a named buffer object
 func BindBuffer(target uint32, buffer uint32) {
 	C.glowBindBuffer(gpBindBuffer, (C.GLenum)(target), (C.GLuint)(buffer))
+	if Tracking() {
+		trackBindBuffer(target, buffer)
+	}
 }
 
 // bind a framebuffer to a framebuffer target
@@ -1116,11 +1208,20 @@ func BindFramebuffer(target uint32, framebuffer uint32) {
 // bind a renderbuffer to a renderbuffer target
 func BindRenderbuffer(target uint32, renderbuffer uint32) {
 	C.glowBindRenderbuffer(gpBindRenderbuffer, (C.GLenum)(target), (C.GLuint)(renderbuffer))
+	if Tracking() {
+		trackBindRenderbuffer(target, renderbuffer)
+	}
 }
 
 // bind a named texture to a texturing target
 func BindTexture(target uint32, texture uint32) {
 	C.glowBindTexture(gpBindTexture, (C.GLenum)(target), (C.GLuint)(texture))
+	if Tracking() {
+		trackBindTexture(target, texture)
+	}
+}
+func BindVertexArrayOES(array uint32) {
+	C.glowBindVertexArrayOES(gpBindVertexArrayOES, (C.GLuint)(array))
 }
 
 // set the blend color
@@ -1151,6 +1252,9 @@ func BlendFuncSeparate(sfactorRGB uint32, dfactorRGB uint32, sfactorAlpha uint32
 // creates and initializes a buffer object's data     store
 func BufferData(target uint32, size int, data unsafe.Pointer, usage uint32) {
 	C.glowBufferData(gpBufferData, (C.GLenum)(target), (C.GLsizeiptr)(size), data, (C.GLenum)(usage))
+	if Tracking() {
+		trackBufferData(target, size, data, usage)
+	}
 }
 
 // updates a subset of a buffer object's data store
@@ -1195,6 +1299,9 @@ func CompileShader(shader uint32) {
 // specify a two-dimensional texture image in a compressed format
 func CompressedTexImage2D(target uint32, level int32, internalformat uint32, width int32, height int32, border int32, imageSize int32, data unsafe.Pointer) {
 	C.glowCompressedTexImage2D(gpCompressedTexImage2D, (C.GLenum)(target), (C.GLint)(level), (C.GLenum)(internalformat), (C.GLsizei)(width), (C.GLsizei)(height), (C.GLint)(border), (C.GLsizei)(imageSize), data)
+	if Tracking() {
+		trackCompressedTexImage2D(target, level, internalformat, width, height, border, imageSize, data)
+	}
 }
 
 // specify a two-dimensional texture subimage in a compressed format
@@ -1205,6 +1312,9 @@ func CompressedTexSubImage2D(target uint32, level int32, xoffset int32, yoffset
 // copy pixels into a 2D texture image
 func CopyTexImage2D(target uint32, level int32, internalformat uint32, x int32, y int32, width int32, height int32, border int32) {
 	C.glowCopyTexImage2D(gpCopyTexImage2D, (C.GLenum)(target), (C.GLint)(level), (C.GLenum)(internalformat), (C.GLint)(x), (C.GLint)(y), (C.GLsizei)(width), (C.GLsizei)(height), (C.GLint)(border))
+	if Tracking() {
+		trackCopyTexImage2D(target, level, internalformat, x, y, width, height, border)
+	}
 }
 
 // copy a two-dimensional texture subimage
@@ -1215,12 +1325,18 @@ func CopyTexSubImage2D(target uint32, level int32, xoffset int32, yoffset int32,
 // Creates a program object
 func CreateProgram() uint32 {
 	ret := C.glowCreateProgram(gpCreateProgram)
+	if Tracking() {
+		trackCreateProgram((uint32)(ret))
+	}
 	return (uint32)(ret)
 }
 
 // Creates a shader object
 func CreateShader(xtype uint32) uint32 {
 	ret := C.glowCreateShader(gpCreateShader, (C.GLenum)(xtype))
+	if Tracking() {
+		trackCreateShader(xtype, (uint32)(ret))
+	}
 	return (uint32)(ret)
 }
 
@@ -1232,31 +1348,52 @@ func CullFace(mode uint32) {
 // delete named buffer objects
 func DeleteBuffers(n int32, buffers *uint32) {
 	C.glowDeleteBuffers(gpDeleteBuffers, (C.GLsizei)(n), (*C.GLuint)(unsafe.Pointer(buffers)))
+	if Tracking() {
+		trackDeleteBuffers(n, buffers)
+	}
 }
 
 // delete framebuffer objects
 func DeleteFramebuffers(n int32, framebuffers *uint32) {
 	C.glowDeleteFramebuffers(gpDeleteFramebuffers, (C.GLsizei)(n), (*C.GLuint)(unsafe.Pointer(framebuffers)))
+	if Tracking() {
+		trackDeleteFramebuffers(n, framebuffers)
+	}
 }
 
 // Deletes a program object
 func DeleteProgram(program uint32) {
 	C.glowDeleteProgram(gpDeleteProgram, (C.GLuint)(program))
+	if Tracking() {
+		trackDeleteProgram(program)
+	}
 }
 
 // delete renderbuffer objects
 func DeleteRenderbuffers(n int32, renderbuffers *uint32) {
 	C.glowDeleteRenderbuffers(gpDeleteRenderbuffers, (C.GLsizei)(n), (*C.GLuint)(unsafe.Pointer(renderbuffers)))
+	if Tracking() {
+		trackDeleteRenderbuffers(n, renderbuffers)
+	}
 }
 
 // Deletes a shader object
 func DeleteShader(shader uint32) {
 	C.glowDeleteShader(gpDeleteShader, (C.GLuint)(shader))
+	if Tracking() {
+		trackDeleteShader(shader)
+	}
 }
 
 // delete named textures
 func DeleteTextures(n int32, textures *uint32) {
 	C.glowDeleteTextures(gpDeleteTextures, (C.GLsizei)(n), (*C.GLuint)(unsafe.Pointer(textures)))
+	if Tracking() {
+		trackDeleteTextures(n, textures)
+	}
+}
+func DeleteVertexArraysOES(n int32, arrays *uint32) {
+	C.glowDeleteVertexArraysOES(gpDeleteVertexArraysOES, (C.GLsizei)(n), (*C.GLuint)(unsafe.Pointer(arrays)))
 }
 
 // specify the value used for depth buffer comparisons
@@ -1293,11 +1430,19 @@ func DiscardFramebufferEXT(target uint32, numAttachments int32, attachments *uin
 // render primitives from array data
 func DrawArrays(mode uint32, first int32, count int32) {
 	C.glowDrawArrays(gpDrawArrays, (C.GLenum)(mode), (C.GLint)(first), (C.GLsizei)(count))
+	countDraw()
 }
 
 // render primitives from array data
 func DrawElements(mode uint32, count int32, xtype uint32, indices unsafe.Pointer) {
 	C.glowDrawElements(gpDrawElements, (C.GLenum)(mode), (C.GLsizei)(count), (C.GLenum)(xtype), indices)
+	countDraw()
+}
+func EGLImageTargetRenderbufferStorageOES(target uint32, image unsafe.Pointer) {
+	C.glowEGLImageTargetRenderbufferStorageOES(gpEGLImageTargetRenderbufferStorageOES, (C.GLenum)(target), (C.GLeglImageOES)(image))
+}
+func EGLImageTargetTexture2DOES(target uint32, image unsafe.Pointer) {
+	C.glowEGLImageTargetTexture2DOES(gpEGLImageTargetTexture2DOES, (C.GLenum)(target), (C.GLeglImageOES)(image))
 }
 
 // enable or disable server-side GL capabilities
@@ -1338,26 +1483,44 @@ func FrontFace(mode uint32) {
 // generate buffer object names
 func GenBuffers(n int32, buffers *uint32) {
 	C.glowGenBuffers(gpGenBuffers, (C.GLsizei)(n), (*C.GLuint)(unsafe.Pointer(buffers)))
+	if Tracking() {
+		trackGenBuffers(n, buffers)
+	}
 }
 
 // generate framebuffer object names
 func GenFramebuffers(n int32, framebuffers *uint32) {
 	C.glowGenFramebuffers(gpGenFramebuffers, (C.GLsizei)(n), (*C.GLuint)(unsafe.Pointer(framebuffers)))
+	if Tracking() {
+		trackGenFramebuffers(n, framebuffers)
+	}
 }
 
 // generate renderbuffer object names
 func GenRenderbuffers(n int32, renderbuffers *uint32) {
 	C.glowGenRenderbuffers(gpGenRenderbuffers, (C.GLsizei)(n), (*C.GLuint)(unsafe.Pointer(renderbuffers)))
+	if Tracking() {
+		trackGenRenderbuffers(n, renderbuffers)
+	}
 }
 
 // generate texture names
 func GenTextures(n int32, textures *uint32) {
 	C.glowGenTextures(gpGenTextures, (C.GLsizei)(n), (*C.GLuint)(unsafe.Pointer(textures)))
+	if Tracking() {
+		trackGenTextures(n, textures)
+	}
+}
+func GenVertexArraysOES(n int32, arrays *uint32) {
+	C.glowGenVertexArraysOES(gpGenVertexArraysOES, (C.GLsizei)(n), (*C.GLuint)(unsafe.Pointer(arrays)))
 }
 
 // generate mipmaps for a specified texture object
 func GenerateMipmap(target uint32) {
 	C.glowGenerateMipmap(gpGenerateMipmap, (C.GLenum)(target))
+	if Tracking() {
+		trackGenerateMipmap(target)
+	}
 }
 
 // Returns information about an active attribute variable for the specified program object
@@ -1388,6 +1551,9 @@ func GetBooleanv(pname uint32, data *bool) {
 func GetBufferParameteriv(target uint32, pname uint32, params *int32) {
 	C.glowGetBufferParameteriv(gpGetBufferParameteriv, (C.GLenum)(target), (C.GLenum)(pname), (*C.GLint)(unsafe.Pointer(params)))
 }
+func GetBufferPointervOES(target uint32, pname uint32, params *unsafe.Pointer) {
+	C.glowGetBufferPointervOES(gpGetBufferPointervOES, (C.GLenum)(target), (C.GLenum)(pname), params)
+}
 
 // return error information
 func GetError() uint32 {
@@ -1488,6 +1654,9 @@ func GetVertexAttribiv(index uint32, pname uint32, params *int32) {
 func Hint(target uint32, mode uint32) {
 	C.glowHint(gpHint, (C.GLenum)(target), (C.GLenum)(mode))
 }
+func InsertEventMarkerEXT(length int32, marker *uint8) {
+	C.glowInsertEventMarkerEXT(gpInsertEventMarkerEXT, (C.GLsizei)(length), (*C.GLchar)(unsafe.Pointer(marker)))
+}
 
 // determine if a name corresponds to a buffer object
 func IsBuffer(buffer uint32) bool {
@@ -1528,6 +1697,10 @@ func IsTexture(texture uint32) bool {
 	ret := C.glowIsTexture(gpIsTexture, (C.GLuint)(texture))
 	return ret == TRUE
 }
+func IsVertexArrayOES(array uint32) bool {
+	ret := C.glowIsVertexArrayOES(gpIsVertexArrayOES, (C.GLuint)(array))
+	return ret == TRUE
+}
 
 // specify the width of rasterized lines
 func LineWidth(width float32) {
@@ -1538,6 +1711,10 @@ func LineWidth(width float32) {
 func LinkProgram(program uint32) {
 	C.glowLinkProgram(gpLinkProgram, (C.GLuint)(program))
 }
+func MapBufferOES(target uint32, access uint32) unsafe.Pointer {
+	ret := C.glowMapBufferOES(gpMapBufferOES, (C.GLenum)(target), (C.GLenum)(access))
+	return (unsafe.Pointer)(ret)
+}
 
 // set pixel storage modes
 func PixelStorei(pname uint32, param int32) {
@@ -1548,6 +1725,12 @@ func PixelStorei(pname uint32, param int32) {
 func PolygonOffset(factor float32, units float32) {
 	C.glowPolygonOffset(gpPolygonOffset, (C.GLfloat)(factor), (C.GLfloat)(units))
 }
+func PopGroupMarkerEXT() {
+	C.glowPopGroupMarkerEXT(gpPopGroupMarkerEXT)
+}
+func PushGroupMarkerEXT(length int32, marker *uint8) {
+	C.glowPushGroupMarkerEXT(gpPushGroupMarkerEXT, (C.GLsizei)(length), (*C.GLchar)(unsafe.Pointer(marker)))
+}
 
 // read a block of pixels from the frame buffer
 func ReadPixels(x int32, y int32, width int32, height int32, format uint32, xtype uint32, pixels unsafe.Pointer) {
@@ -1562,6 +1745,9 @@ func ReleaseShaderCompiler() {
 // establish data storage, format and dimensions of a     renderbuffer object's image
 func RenderbufferStorage(target uint32, internalformat uint32, width int32, height int32) {
 	C.glowRenderbufferStorage(gpRenderbufferStorage, (C.GLenum)(target), (C.GLenum)(internalformat), (C.GLsizei)(width), (C.GLsizei)(height))
+	if Tracking() {
+		trackRenderbufferStorage(target, internalformat, width, height)
+	}
 }
 
 // specify multisample coverage parameters
@@ -1617,6 +1803,9 @@ func StencilOpSeparate(face uint32, sfail uint32, dpfail uint32, dppass uint32)
 // specify a two-dimensional texture image
 func TexImage2D(target uint32, level int32, internalformat int32, width int32, height int32, border int32, format uint32, xtype uint32, pixels unsafe.Pointer) {
 	C.glowTexImage2D(gpTexImage2D, (C.GLenum)(target), (C.GLint)(level), (C.GLint)(internalformat), (C.GLsizei)(width), (C.GLsizei)(height), (C.GLint)(border), (C.GLenum)(format), (C.GLenum)(xtype), pixels)
+	if Tracking() {
+		trackTexImage2D(target, level, internalformat, width, height, border, format, xtype, pixels)
+	}
 }
 func TexParameterf(target uint32, pname uint32, param float32) {
 	C.glowTexParameterf(gpTexParameterf, (C.GLenum)(target), (C.GLenum)(pname), (C.GLfloat)(param))
@@ -1730,6 +1919,10 @@ func UniformMatrix3fv(location int32, count int32, transpose bool, value *float3
 func UniformMatrix4fv(location int32, count int32, transpose bool, value *float32) {
 	C.glowUniformMatrix4fv(gpUniformMatrix4fv, (C.GLint)(location), (C.GLsizei)(count), (C.GLboolean)(boolToInt(transpose)), (*C.GLfloat)(unsafe.Pointer(value)))
 }
+func UnmapBufferOES(target uint32) bool {
+	ret := C.glowUnmapBufferOES(gpUnmapBufferOES, (C.GLenum)(target))
+	return ret == TRUE
+}
 
 // Installs a program object as part of current rendering state
 func UseProgram(program uint32) {
@@ -1776,24 +1969,11 @@ func Viewport(x int32, y int32, width int32, height int32) {
 }
 
 // Init initializes the OpenGL bindings by loading the function pointers (for
-// each OpenGL function) from the active OpenGL context.
-//
-// It must be called under the presence of an active OpenGL context, e.g.,
-// always after calling window.MakeContextCurrent() and always before calling
-// any OpenGL functions exported by this package.
-//
-// On Windows, Init loads pointers that are context-specific (and hence you
-// must re-init if switching between OpenGL contexts, although not calling Init
-// again after switching between OpenGL contexts may work if the contexts belong
-// to the same graphics driver/device).
-//
-// On macOS and the other POSIX systems, the behavior is different, but code
-// written compatible with the Windows behavior is compatible with macOS and the
-// other POSIX systems. That is, always Init under an active OpenGL context, and
-// always re-init after switching graphics contexts.
+// each OpenGL function) with getProcAddress, which piglet does not provide.
 //
-// For information about caveats of Init, you should read the "Platform Specific
-// Function Retrieval" section of https://www.opengl.org/wiki/Load_OpenGL_Functions.
+// Call InitWithProcAddrFunc(piglet.GetProcAddress) instead, always after
+// calling piglet.MakeCurrent() and always before calling any functions
+// exported by this package.
 func Init() error {
 	return InitWithProcAddrFunc(getProcAddress)
 }
@@ -1830,6 +2010,7 @@ func InitWithProcAddrFunc(getProcAddr func(name string) unsafe.Pointer) error {
 	if gpBindTexture == nil {
 		return errors.New("glBindTexture")
 	}
+	gpBindVertexArrayOES = (C.GPBINDVERTEXARRAYOES)(getProcAddr("glBindVertexArrayOES"))
 	gpBlendColor = (C.GPBLENDCOLOR)(getProcAddr("glBlendColor"))
 	if gpBlendColor == nil {
 		return errors.New("glBlendColor")
@@ -1938,6 +2119,7 @@ func InitWithProcAddrFunc(getProcAddr func(name string) unsafe.Pointer) error {
 	if gpDeleteTextures == nil {
 		return errors.New("glDeleteTextures")
 	}
+	gpDeleteVertexArraysOES = (C.GPDELETEVERTEXARRAYSOES)(getProcAddr("glDeleteVertexArraysOES"))
 	gpDepthFunc = (C.GPDEPTHFUNC)(getProcAddr("glDepthFunc"))
 	if gpDepthFunc == nil {
 		return errors.New("glDepthFunc")
@@ -1971,6 +2153,8 @@ func InitWithProcAddrFunc(getProcAddr func(name string) unsafe.Pointer) error {
 	if gpDrawElements == nil {
 		return errors.New("glDrawElements")
 	}
+	gpEGLImageTargetRenderbufferStorageOES = (C.GPEGLIMAGETARGETRENDERBUFFERSTORAGEOES)(getProcAddr("glEGLImageTargetRenderbufferStorageOES"))
+	gpEGLImageTargetTexture2DOES = (C.GPEGLIMAGETARGETTEXTURE2DOES)(getProcAddr("glEGLImageTargetTexture2DOES"))
 	gpEnable = (C.GPENABLE)(getProcAddr("glEnable"))
 	if gpEnable == nil {
 		return errors.New("glEnable")
@@ -2015,6 +2199,7 @@ func InitWithProcAddrFunc(getProcAddr func(name string) unsafe.Pointer) error {
 	if gpGenTextures == nil {
 		return errors.New("glGenTextures")
 	}
+	gpGenVertexArraysOES = (C.GPGENVERTEXARRAYSOES)(getProcAddr("glGenVertexArraysOES"))
 	gpGenerateMipmap = (C.GPGENERATEMIPMAP)(getProcAddr("glGenerateMipmap"))
 	if gpGenerateMipmap == nil {
 		return errors.New("glGenerateMipmap")
@@ -2043,6 +2228,7 @@ func InitWithProcAddrFunc(getProcAddr func(name string) unsafe.Pointer) error {
 	if gpGetBufferParameteriv == nil {
 		return errors.New("glGetBufferParameteriv")
 	}
+	gpGetBufferPointervOES = (C.GPGETBUFFERPOINTERVOES)(getProcAddr("glGetBufferPointervOES"))
 	gpGetError = (C.GPGETERROR)(getProcAddr("glGetError"))
 	if gpGetError == nil {
 		return errors.New("glGetError")
@@ -2127,6 +2313,7 @@ func InitWithProcAddrFunc(getProcAddr func(name string) unsafe.Pointer) error {
 	if gpHint == nil {
 		return errors.New("glHint")
 	}
+	gpInsertEventMarkerEXT = (C.GPINSERTEVENTMARKEREXT)(getProcAddr("glInsertEventMarkerEXT"))
 	gpIsBuffer = (C.GPISBUFFER)(getProcAddr("glIsBuffer"))
 	if gpIsBuffer == nil {
 		return errors.New("glIsBuffer")
@@ -2155,6 +2342,7 @@ func InitWithProcAddrFunc(getProcAddr func(name string) unsafe.Pointer) error {
 	if gpIsTexture == nil {
 		return errors.New("glIsTexture")
 	}
+	gpIsVertexArrayOES = (C.GPISVERTEXARRAYOES)(getProcAddr("glIsVertexArrayOES"))
 	gpLineWidth = (C.GPLINEWIDTH)(getProcAddr("glLineWidth"))
 	if gpLineWidth == nil {
 		return errors.New("glLineWidth")
@@ -2163,6 +2351,7 @@ func InitWithProcAddrFunc(getProcAddr func(name string) unsafe.Pointer) error {
 	if gpLinkProgram == nil {
 		return errors.New("glLinkProgram")
 	}
+	gpMapBufferOES = (C.GPMAPBUFFEROES)(getProcAddr("glMapBufferOES"))
 	gpPixelStorei = (C.GPPIXELSTOREI)(getProcAddr("glPixelStorei"))
 	if gpPixelStorei == nil {
 		return errors.New("glPixelStorei")
@@ -2171,6 +2360,8 @@ func InitWithProcAddrFunc(getProcAddr func(name string) unsafe.Pointer) error {
 	if gpPolygonOffset == nil {
 		return errors.New("glPolygonOffset")
 	}
+	gpPopGroupMarkerEXT = (C.GPPOPGROUPMARKEREXT)(getProcAddr("glPopGroupMarkerEXT"))
+	gpPushGroupMarkerEXT = (C.GPPUSHGROUPMARKEREXT)(getProcAddr("glPushGroupMarkerEXT"))
 	gpReadPixels = (C.GPREADPIXELS)(getProcAddr("glReadPixels"))
 	if gpReadPixels == nil {
 		return errors.New("glReadPixels")
@@ -2323,6 +2514,7 @@ func InitWithProcAddrFunc(getProcAddr func(name string) unsafe.Pointer) error {
 	if gpUniformMatrix4fv == nil {
 		return errors.New("glUniformMatrix4fv")
 	}
+	gpUnmapBufferOES = (C.GPUNMAPBUFFEROES)(getProcAddr("glUnmapBufferOES"))
 	gpUseProgram = (C.GPUSEPROGRAM)(getProcAddr("glUseProgram"))
 	if gpUseProgram == nil {
 		return errors.New("glUseProgram")
@@ -1,3 +1,4 @@
+// Code generated by glowgen. DO NOT EDIT.
 
 package gles2
 
@@ -6,8 +7,6 @@ import "unsafe"
 // this is just to satisfy gles2.Init()! instead call:
 // gles2.InitWithProcAddrFunc( piglet.GetProcAddress )
 
-func getProcAddress(string) unsafe.Pointer { 
-    panic("DO NOT CALL gles2.getProcAddress!! IT WILL CRASH!!")
-    return unsafe.Pointer(nil) 
+func getProcAddress(string) unsafe.Pointer {
+	panic("DO NOT CALL gles2.getProcAddress!! IT WILL CRASH!!")
 }
-
@@ -0,0 +1,374 @@
+package gles2
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unsafe"
+)
+
+// Tracking records the objects created and deleted through this package,
+// to find the ones never deleted. It is off by default, as it costs a
+// lookup and a stack trace per tracked call. The track functions are
+// called by the generated bindings, see glTracked in
+// internal/glowgen/config.go.
+
+var tracking int32 // atomic, 1 while on
+
+// Kinds of tracked objects
+const (
+	KindBuffer       = "buffer"
+	KindTexture      = "texture"
+	KindFramebuffer  = "framebuffer"
+	KindRenderbuffer = "renderbuffer"
+	KindShader       = "shader"
+	KindProgram      = "program"
+)
+
+// A TrackedObject is a live GL object, as recorded by the tracker
+type TrackedObject struct {
+	Kind    string
+	Name    uint32
+	Bytes   int64     // estimated GPU memory
+	Created time.Time // of the Gen or Create call
+	stack   []uintptr
+}
+
+// Return the Go stack that created the object, one "function file:line"
+// per line
+func (o TrackedObject) Stack() string {
+	var b strings.Builder
+	frames := runtime.CallersFrames(o.stack)
+	for {
+		frame, more := frames.Next()
+		fmt.Fprintf(&b, "%s\n\t%s:%d\n", frame.Function, frame.File, frame.Line)
+		if !more {
+			break
+		}
+	}
+	return b.String()
+}
+
+type trackKey struct {
+	kind string
+	name uint32
+}
+
+type tracked struct {
+	TrackedObject
+	levels map[[2]int32]int64 // bytes by image target and level, for textures
+}
+
+func (t *tracked) sum() {
+	t.Bytes = 0
+	for _, size := range t.levels {
+		t.Bytes += size
+	}
+}
+
+var tracker struct {
+	mu           sync.Mutex
+	objects      map[trackKey]*tracked
+	buffers      map[uint32]uint32    // bound buffer by target
+	textures     map[[2]uint32]uint32 // bound texture by unit and target
+	unit         uint32
+	renderbuffer uint32
+}
+
+// Start or stop tracking. Starting forgets everything recorded before, so
+// start right after creating the context. Objects created while tracking
+// is off are not reported.
+func SetTracking(on bool) {
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+	if on && !Tracking() {
+		tracker.objects = map[trackKey]*tracked{}
+		tracker.buffers = map[uint32]uint32{}
+		tracker.textures = map[[2]uint32]uint32{}
+		tracker.unit, tracker.renderbuffer = 0, 0
+	}
+	v := int32(0)
+	if on {
+		v = 1
+	}
+	atomic.StoreInt32(&tracking, v)
+}
+
+// Report whether tracking is on
+func Tracking() bool { return atomic.LoadInt32(&tracking) != 0 }
+
+// Return the live tracked objects, by kind and name
+func TrackedObjects() []TrackedObject {
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+	ret := make([]TrackedObject, 0, len(tracker.objects))
+	for _, t := range tracker.objects {
+		ret = append(ret, t.TrackedObject)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		if ret[i].Kind != ret[j].Kind {
+			return ret[i].Kind < ret[j].Kind
+		}
+		return ret[i].Name < ret[j].Name
+	})
+	return ret
+}
+
+// Return the estimated GPU memory of the live tracked objects
+func TrackedBytes() int64 {
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+	var ret int64
+	for _, t := range tracker.objects {
+		ret += t.Bytes
+	}
+	return ret
+}
+
+// Write a summary of the live tracked objects by kind, and with verbose
+// each object with the stack that created it
+func WriteTrackingReport(w io.Writer, verbose bool) {
+	objects := TrackedObjects()
+	counts, bytes := map[string]int{}, map[string]int64{}
+	var total int64
+	for _, o := range objects {
+		counts[o.Kind]++
+		bytes[o.Kind] += o.Bytes
+		total += o.Bytes
+	}
+	fmt.Fprintf(w, "gles2: %d live objects, %s estimated\n", len(objects), formatBytes(total))
+	for _, kind := range []string{KindBuffer, KindTexture, KindRenderbuffer, KindFramebuffer, KindProgram, KindShader} {
+		if counts[kind] > 0 {
+			fmt.Fprintf(w, "  %-12s %5d  %s\n", kind, counts[kind], formatBytes(bytes[kind]))
+		}
+	}
+	if !verbose {
+		return
+	}
+	for _, o := range objects {
+		fmt.Fprintf(w, "\n%s %d, %s, created %s:\n%s", o.Kind, o.Name, formatBytes(o.Bytes), o.Created.Format(time.RFC3339), o.Stack())
+	}
+}
+
+func formatBytes(n int64) string {
+	switch {
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1f MiB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1f KiB", float64(n)/(1<<10))
+	}
+	return fmt.Sprintf("%d B", n)
+}
+
+// Record objects created by the caller of the tracked function
+func trackCreate(kind string, names ...uint32) {
+	var pcs [32]uintptr
+	n := runtime.Callers(4, pcs[:]) // skip Callers, trackCreate, trackGen*, Gen*
+	stack := append([]uintptr(nil), pcs[:n]...)
+	now := time.Now()
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+	for _, name := range names {
+		if name != 0 {
+			tracker.objects[trackKey{kind, name}] = &tracked{TrackedObject: TrackedObject{Kind: kind, Name: name, Created: now, stack: stack}}
+		}
+	}
+}
+
+func trackDelete(kind string, names ...uint32) {
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+	for _, name := range names {
+		delete(tracker.objects, trackKey{kind, name})
+	}
+}
+
+// Return n names at p
+func trackNames(n int32, p *uint32) []uint32 {
+	if n <= 0 || p == nil {
+		return nil
+	}
+	return (*[1 << 24]uint32)(unsafe.Pointer(p))[:n:n]
+}
+
+func trackGenBuffers(n int32, buffers *uint32) {
+	trackCreate(KindBuffer, trackNames(n, buffers)...)
+}
+
+func trackDeleteBuffers(n int32, buffers *uint32) {
+	trackDelete(KindBuffer, trackNames(n, buffers)...)
+}
+
+func trackBindBuffer(target uint32, buffer uint32) {
+	tracker.mu.Lock()
+	tracker.buffers[target] = buffer
+	tracker.mu.Unlock()
+}
+
+func trackBufferData(target uint32, size int, data unsafe.Pointer, usage uint32) {
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+	if t, ok := tracker.objects[trackKey{KindBuffer, tracker.buffers[target]}]; ok {
+		t.Bytes = int64(size)
+	}
+}
+
+func trackGenTextures(n int32, textures *uint32) {
+	trackCreate(KindTexture, trackNames(n, textures)...)
+}
+
+func trackDeleteTextures(n int32, textures *uint32) {
+	trackDelete(KindTexture, trackNames(n, textures)...)
+}
+
+func trackActiveTexture(texture uint32) {
+	tracker.mu.Lock()
+	tracker.unit = texture - TEXTURE0
+	tracker.mu.Unlock()
+}
+
+func trackBindTexture(target uint32, texture uint32) {
+	tracker.mu.Lock()
+	tracker.textures[[2]uint32{tracker.unit, target}] = texture
+	tracker.mu.Unlock()
+}
+
+// Record the size of an image of the texture bound for target, which may
+// be a cube map face
+func trackImage(target uint32, level int32, size int64) {
+	binding := target
+	if target >= TEXTURE_CUBE_MAP_POSITIVE_X && target <= TEXTURE_CUBE_MAP_NEGATIVE_Z {
+		binding = TEXTURE_CUBE_MAP
+	}
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+	t, ok := tracker.objects[trackKey{KindTexture, tracker.textures[[2]uint32{tracker.unit, binding}]}]
+	if !ok {
+		return
+	}
+	if t.levels == nil {
+		t.levels = map[[2]int32]int64{}
+	}
+	t.levels[[2]int32{int32(target), level}] = size
+	delete(t.levels, [2]int32{int32(target), -1}) // mipmaps need generating again
+	t.sum()
+}
+
+// bytes per pixel of uncompressed texture formats
+func pixelSize(format, xtype uint32) int64 {
+	switch xtype {
+	case UNSIGNED_SHORT_5_6_5, UNSIGNED_SHORT_4_4_4_4, UNSIGNED_SHORT_5_5_5_1:
+		return 2
+	case HALF_FLOAT_OES:
+		return 2 * pixelSize(format, UNSIGNED_BYTE)
+	}
+	switch format {
+	case ALPHA, LUMINANCE:
+		return 1
+	case LUMINANCE_ALPHA:
+		return 2
+	case RGB:
+		return 3
+	}
+	return 4
+}
+
+func trackTexImage2D(target uint32, level int32, internalformat int32, width int32, height int32, border int32, format uint32, xtype uint32, pixels unsafe.Pointer) {
+	trackImage(target, level, int64(width)*int64(height)*pixelSize(format, xtype))
+}
+
+func trackCompressedTexImage2D(target uint32, level int32, internalformat uint32, width int32, height int32, border int32, imageSize int32, data unsafe.Pointer) {
+	trackImage(target, level, int64(imageSize))
+}
+
+func trackCopyTexImage2D(target uint32, level int32, internalformat uint32, x int32, y int32, width int32, height int32, border int32) {
+	trackImage(target, level, int64(width)*int64(height)*pixelSize(internalformat, UNSIGNED_BYTE))
+}
+
+// Generated mipmaps add a third of the base levels, recorded as level -1
+func trackGenerateMipmap(target uint32) {
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+	t, ok := tracker.objects[trackKey{KindTexture, tracker.textures[[2]uint32{tracker.unit, target}]}]
+	if !ok || t.levels == nil {
+		return
+	}
+	var base int64
+	for key, size := range t.levels {
+		if key[1] == 0 {
+			base += size
+		}
+	}
+	t.levels[[2]int32{int32(target), -1}] = base / 3
+	t.sum()
+}
+
+func trackGenFramebuffers(n int32, framebuffers *uint32) {
+	trackCreate(KindFramebuffer, trackNames(n, framebuffers)...)
+}
+
+func trackDeleteFramebuffers(n int32, framebuffers *uint32) {
+	trackDelete(KindFramebuffer, trackNames(n, framebuffers)...)
+}
+
+func trackGenRenderbuffers(n int32, renderbuffers *uint32) {
+	trackCreate(KindRenderbuffer, trackNames(n, renderbuffers)...)
+}
+
+func trackDeleteRenderbuffers(n int32, renderbuffers *uint32) {
+	trackDelete(KindRenderbuffer, trackNames(n, renderbuffers)...)
+}
+
+func trackBindRenderbuffer(target uint32, renderbuffer uint32) {
+	tracker.mu.Lock()
+	tracker.renderbuffer = renderbuffer
+	tracker.mu.Unlock()
+}
+
+func trackRenderbufferStorage(target uint32, internalformat uint32, width int32, height int32) {
+	size := int64(4)
+	switch internalformat {
+	case STENCIL_INDEX8:
+		size = 1
+	case RGBA4, RGB5_A1, RGB565, DEPTH_COMPONENT16:
+		size = 2
+	}
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+	if t, ok := tracker.objects[trackKey{KindRenderbuffer, tracker.renderbuffer}]; ok {
+		t.Bytes = int64(width) * int64(height) * size
+	}
+}
+
+func trackCreateShader(xtype uint32, shader uint32) {
+	trackCreate(KindShader, shader)
+}
+
+func trackDeleteShader(shader uint32) {
+	trackDelete(KindShader, shader)
+}
+
+func trackCreateProgram(program uint32) {
+	trackCreate(KindProgram, program)
+}
+
+func trackDeleteProgram(program uint32) {
+	trackDelete(KindProgram, program)
+}
+
+// Draw calls are counted whether tracking is on or not, as that costs
+// only an atomic add. The generated DrawArrays and DrawElements call
+// countDraw, see glCounted in internal/glowgen/config.go.
+
+var drawCalls uint64 // atomic
+
+func countDraw() { atomic.AddUint64(&drawCalls, 1) }
+
+// Return the number of DrawArrays and DrawElements calls so far, eg. to
+// count them per frame
+func DrawCalls() uint64 { return atomic.LoadUint64(&drawCalls) }
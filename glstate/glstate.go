@@ -0,0 +1,517 @@
+// Package glstate tracks GL state to skip calls that would not change
+// anything, as every cgo call into the driver is costly on a Pi. A Cache
+// only knows about calls made through it, so call Invalidate after any
+// code that touches GL directly.
+//
+// All methods work on a nil *Cache, by calling GL directly, so code can
+// take an optional cache without checking for one.
+package glstate
+
+import (
+	gl "github.com/FEEDFACE-COM/piglet/gles2"
+)
+
+// marks names and enums that are not known
+const unknown = ^uint32(0)
+
+// Texture units tracked. Bindings on higher units are not cached.
+const MaxUnits = 8
+
+type textureUnit struct {
+	texture2D, cubeMap, external uint32
+}
+
+func (u *textureUnit) binding(target uint32) *uint32 {
+	switch target {
+	case gl.TEXTURE_2D:
+		return &u.texture2D
+	case gl.TEXTURE_CUBE_MAP:
+		return &u.cubeMap
+	case gl.TEXTURE_EXTERNAL_OES:
+		return &u.external
+	}
+	return nil
+}
+
+// Statistics of a Cache
+type Stats struct {
+	Calls   int // passed on to GL
+	Skipped int // that would not have changed anything
+}
+
+// A Cache remembers the state set through it
+type Cache struct {
+	program       uint32
+	arrayBuffer   uint32
+	elementBuffer uint32
+	framebuffer   uint32
+	renderbuffer  uint32
+	activeUnit    uint32
+	units         [MaxUnits]textureUnit
+	caps          map[uint32]int8 // 1 enabled, 0 disabled, absent unknown
+	blend         [4]uint32       // src rgb, dst rgb, src alpha, dst alpha
+	blendEquation [2]uint32
+	depthFunc     uint32
+	depthMask     int8
+	colorMask     int8 // bits rgba, -1 unknown
+	cullFace      uint32
+	frontFace     uint32
+	viewport      [4]int32
+	viewportValid bool
+	scissor       [4]int32
+	scissorValid  bool
+	clearColor    [4]float32
+	clearValid    bool
+	stats         Stats
+}
+
+// Return a cache that knows nothing about the current state yet
+func New() *Cache {
+	c := &Cache{}
+	c.Invalidate()
+	return c
+}
+
+// Forget all state, eg. after third party code made GL calls directly
+func (c *Cache) Invalidate() {
+	if c == nil {
+		return
+	}
+	c.program, c.arrayBuffer, c.elementBuffer = unknown, unknown, unknown
+	c.framebuffer, c.renderbuffer, c.activeUnit = unknown, unknown, unknown
+	for i := range c.units {
+		c.units[i] = textureUnit{unknown, unknown, unknown}
+	}
+	c.caps = map[uint32]int8{}
+	c.blend = [4]uint32{unknown, unknown, unknown, unknown}
+	c.blendEquation = [2]uint32{unknown, unknown}
+	c.depthFunc, c.depthMask, c.colorMask = unknown, -1, -1
+	c.cullFace, c.frontFace = unknown, unknown
+	c.viewportValid, c.scissorValid, c.clearValid = false, false, false
+}
+
+// Return the number of calls made and skipped so far
+func (c *Cache) Stats() Stats {
+	if c == nil {
+		return Stats{}
+	}
+	return c.stats
+}
+
+// Reset the statistics, eg. once per frame
+func (c *Cache) ResetStats() {
+	if c != nil {
+		c.stats = Stats{}
+	}
+}
+
+// Report whether a call setting *cached to value can be skipped, and
+// record value otherwise
+func (c *Cache) skip(cached *uint32, value uint32) bool {
+	if *cached == value {
+		c.stats.Skipped++
+		return true
+	}
+	*cached = value
+	c.stats.Calls++
+	return false
+}
+
+// Use a program, as gl.UseProgram
+func (c *Cache) UseProgram(name uint32) {
+	if c == nil || !c.skip(&c.program, name) {
+		gl.UseProgram(name)
+	}
+}
+
+// Return the program in use, asking GL only if it is not known
+func (c *Cache) Program() uint32 {
+	if c != nil && c.program != unknown {
+		return c.program
+	}
+	var name int32
+	gl.GetIntegerv(gl.CURRENT_PROGRAM, &name)
+	if c != nil {
+		c.program = uint32(name)
+	}
+	return uint32(name)
+}
+
+// Bind a buffer to gl.ARRAY_BUFFER or gl.ELEMENT_ARRAY_BUFFER
+func (c *Cache) BindBuffer(target, name uint32) {
+	if c != nil {
+		cached := &c.arrayBuffer
+		if target == gl.ELEMENT_ARRAY_BUFFER {
+			cached = &c.elementBuffer
+		}
+		if c.skip(cached, name) {
+			return
+		}
+	}
+	gl.BindBuffer(target, name)
+}
+
+// Bind a framebuffer, 0 for the display
+func (c *Cache) BindFramebuffer(name uint32) {
+	if c == nil || !c.skip(&c.framebuffer, name) {
+		gl.BindFramebuffer(gl.FRAMEBUFFER, name)
+	}
+}
+
+// Bind a renderbuffer
+func (c *Cache) BindRenderbuffer(name uint32) {
+	if c == nil || !c.skip(&c.renderbuffer, name) {
+		gl.BindRenderbuffer(gl.RENDERBUFFER, name)
+	}
+}
+
+// Return the bound framebuffer, asking GL only if it is not known
+func (c *Cache) Framebuffer() uint32 {
+	if c != nil && c.framebuffer != unknown {
+		return c.framebuffer
+	}
+	var binding int32
+	gl.GetIntegerv(gl.FRAMEBUFFER_BINDING, &binding)
+	if c != nil {
+		c.framebuffer = uint32(binding)
+	}
+	return uint32(binding)
+}
+
+// Select the texture unit, as in gl.ActiveTexture(gl.TEXTURE0 + unit)
+func (c *Cache) ActiveTexture(unit int) {
+	if c == nil || !c.skip(&c.activeUnit, uint32(unit)) {
+		gl.ActiveTexture(gl.TEXTURE0 + uint32(unit))
+	}
+}
+
+// Bind a texture to target on the active unit
+func (c *Cache) BindTexture(target, name uint32) {
+	if c != nil && c.activeUnit < MaxUnits {
+		if cached := c.units[c.activeUnit].binding(target); cached != nil && c.skip(cached, name) {
+			return
+		}
+	} else if c != nil {
+		c.stats.Calls++
+	}
+	gl.BindTexture(target, name)
+}
+
+// Bind a texture to target on unit, and leave the unit active
+func (c *Cache) BindTextureUnit(unit int, target, name uint32) {
+	c.ActiveTexture(unit)
+	c.BindTexture(target, name)
+}
+
+// Return the texture bound to target on unit, asking GL only if it is not
+// known, which leaves the unit active
+func (c *Cache) Texture(unit int, target uint32) uint32 {
+	var cached *uint32
+	if c != nil && unit < MaxUnits {
+		cached = c.units[unit].binding(target)
+	}
+	if cached != nil && *cached != unknown {
+		return *cached
+	}
+	pname := uint32(gl.TEXTURE_BINDING_2D)
+	switch target {
+	case gl.TEXTURE_CUBE_MAP:
+		pname = gl.TEXTURE_BINDING_CUBE_MAP
+	case gl.TEXTURE_EXTERNAL_OES:
+		pname = gl.TEXTURE_BINDING_EXTERNAL_OES
+	}
+	c.ActiveTexture(unit)
+	var name int32
+	gl.GetIntegerv(pname, &name)
+	if cached != nil {
+		*cached = uint32(name)
+	}
+	return uint32(name)
+}
+
+// Enable a capability, eg. gl.DEPTH_TEST
+func (c *Cache) Enable(capability uint32) { c.SetEnabled(capability, true) }
+
+// Disable a capability
+func (c *Cache) Disable(capability uint32) { c.SetEnabled(capability, false) }
+
+// Enable or disable a capability, eg. gl.BLEND
+func (c *Cache) SetEnabled(capability uint32, enabled bool) {
+	if c != nil {
+		value := int8(0)
+		if enabled {
+			value = 1
+		}
+		if cached, ok := c.caps[capability]; ok && cached == value {
+			c.stats.Skipped++
+			return
+		}
+		c.caps[capability] = value
+		c.stats.Calls++
+	}
+	if enabled {
+		gl.Enable(capability)
+	} else {
+		gl.Disable(capability)
+	}
+}
+
+// Report whether a capability is enabled, asking GL only if it is not known
+func (c *Cache) IsEnabled(capability uint32) bool {
+	if c != nil {
+		if cached, ok := c.caps[capability]; ok {
+			return cached == 1
+		}
+	}
+	enabled := gl.IsEnabled(capability)
+	if c != nil {
+		value := int8(0)
+		if enabled {
+			value = 1
+		}
+		c.caps[capability] = value
+	}
+	return enabled
+}
+
+// Capabilities that Begin2D changes
+var caps2D = [...]uint32{gl.DEPTH_TEST, gl.CULL_FACE, gl.BLEND}
+
+// Enabled states of depth test, face culling and blending, as Begin2D
+// found them
+type Caps2D [len(caps2D)]bool
+
+// Disable depth test and face culling and enable blending, to draw 2D
+// over the scene, and return the states to restore with End2D
+func (c *Cache) Begin2D() Caps2D {
+	var ret Caps2D
+	for i, capability := range caps2D {
+		ret[i] = c.IsEnabled(capability)
+	}
+	c.Disable(gl.DEPTH_TEST)
+	c.Disable(gl.CULL_FACE)
+	c.Enable(gl.BLEND)
+	return ret
+}
+
+// Restore the states that Begin2D changed
+func (c *Cache) End2D(saved Caps2D) {
+	for i, capability := range caps2D {
+		c.SetEnabled(capability, saved[i])
+	}
+}
+
+// Set the blend function of color and alpha
+func (c *Cache) BlendFunc(src, dst uint32) {
+	c.BlendFuncSeparate(src, dst, src, dst)
+}
+
+// Set separate blend functions of color and alpha
+func (c *Cache) BlendFuncSeparate(srcRGB, dstRGB, srcAlpha, dstAlpha uint32) {
+	if c != nil {
+		value := [4]uint32{srcRGB, dstRGB, srcAlpha, dstAlpha}
+		if c.blend == value {
+			c.stats.Skipped++
+			return
+		}
+		c.blend = value
+		c.stats.Calls++
+	}
+	if srcRGB == srcAlpha && dstRGB == dstAlpha {
+		gl.BlendFunc(srcRGB, dstRGB)
+	} else {
+		gl.BlendFuncSeparate(srcRGB, dstRGB, srcAlpha, dstAlpha)
+	}
+}
+
+// Return the blend functions as src rgb, dst rgb, src alpha, dst alpha,
+// asking GL only if they are not known
+func (c *Cache) GetBlendFunc() [4]uint32 {
+	if c != nil && c.blend[0] != unknown {
+		return c.blend
+	}
+	var ret [4]uint32
+	for i, pname := range [4]uint32{gl.BLEND_SRC_RGB, gl.BLEND_DST_RGB, gl.BLEND_SRC_ALPHA, gl.BLEND_DST_ALPHA} {
+		var value int32
+		gl.GetIntegerv(pname, &value)
+		ret[i] = uint32(value)
+	}
+	if c != nil {
+		c.blend = ret
+	}
+	return ret
+}
+
+// Set the blend equation of color and alpha
+func (c *Cache) BlendEquation(mode uint32) {
+	if c != nil {
+		if c.blendEquation == [2]uint32{mode, mode} {
+			c.stats.Skipped++
+			return
+		}
+		c.blendEquation = [2]uint32{mode, mode}
+		c.stats.Calls++
+	}
+	gl.BlendEquation(mode)
+}
+
+// Set the depth comparison
+func (c *Cache) DepthFunc(function uint32) {
+	if c == nil || !c.skip(&c.depthFunc, function) {
+		gl.DepthFunc(function)
+	}
+}
+
+// Enable or disable writing depth
+func (c *Cache) DepthMask(flag bool) {
+	if c != nil {
+		value := int8(0)
+		if flag {
+			value = 1
+		}
+		if c.depthMask == value {
+			c.stats.Skipped++
+			return
+		}
+		c.depthMask = value
+		c.stats.Calls++
+	}
+	gl.DepthMask(flag)
+}
+
+// Enable or disable writing color channels
+func (c *Cache) ColorMask(r, g, b, a bool) {
+	if c != nil {
+		value := int8(0)
+		for i, flag := range []bool{r, g, b, a} {
+			if flag {
+				value |= 1 << uint(i)
+			}
+		}
+		if c.colorMask == value {
+			c.stats.Skipped++
+			return
+		}
+		c.colorMask = value
+		c.stats.Calls++
+	}
+	gl.ColorMask(r, g, b, a)
+}
+
+// Set the faces to cull
+func (c *Cache) CullFace(mode uint32) {
+	if c == nil || !c.skip(&c.cullFace, mode) {
+		gl.CullFace(mode)
+	}
+}
+
+// Set the winding of front faces
+func (c *Cache) FrontFace(mode uint32) {
+	if c == nil || !c.skip(&c.frontFace, mode) {
+		gl.FrontFace(mode)
+	}
+}
+
+// Set the viewport
+func (c *Cache) Viewport(x, y, w, h int32) {
+	if c != nil {
+		value := [4]int32{x, y, w, h}
+		if c.viewportValid && c.viewport == value {
+			c.stats.Skipped++
+			return
+		}
+		c.viewport, c.viewportValid = value, true
+		c.stats.Calls++
+	}
+	gl.Viewport(x, y, w, h)
+}
+
+// Return the viewport, asking GL only if it is not known
+func (c *Cache) GetViewport() [4]int32 {
+	if c != nil && c.viewportValid {
+		return c.viewport
+	}
+	var ret [4]int32
+	gl.GetIntegerv(gl.VIEWPORT, &ret[0])
+	if c != nil {
+		c.viewport, c.viewportValid = ret, true
+	}
+	return ret
+}
+
+// Set the scissor box
+func (c *Cache) Scissor(x, y, w, h int32) {
+	if c != nil {
+		value := [4]int32{x, y, w, h}
+		if c.scissorValid && c.scissor == value {
+			c.stats.Skipped++
+			return
+		}
+		c.scissor, c.scissorValid = value, true
+		c.stats.Calls++
+	}
+	gl.Scissor(x, y, w, h)
+}
+
+// Set the color Clear fills with
+func (c *Cache) ClearColor(r, g, b, a float32) {
+	if c != nil {
+		value := [4]float32{r, g, b, a}
+		if c.clearValid && c.clearColor == value {
+			c.stats.Skipped++
+			return
+		}
+		c.clearColor, c.clearValid = value, true
+		c.stats.Calls++
+	}
+	gl.ClearColor(r, g, b, a)
+}
+
+// Forget a deleted program, as GL may reuse its name
+func (c *Cache) ForgetProgram(name uint32) {
+	if c != nil && c.program == name {
+		c.program = unknown
+	}
+}
+
+// Forget a deleted buffer. GL unbinds it, and may reuse its name.
+func (c *Cache) ForgetBuffer(name uint32) {
+	if c == nil {
+		return
+	}
+	if c.arrayBuffer == name {
+		c.arrayBuffer = 0
+	}
+	if c.elementBuffer == name {
+		c.elementBuffer = 0
+	}
+}
+
+// Forget a deleted texture. GL unbinds it from all units, and may reuse its name.
+func (c *Cache) ForgetTexture(name uint32) {
+	if c == nil {
+		return
+	}
+	for i := range c.units {
+		u := &c.units[i]
+		for _, cached := range []*uint32{&u.texture2D, &u.cubeMap, &u.external} {
+			if *cached == name {
+				*cached = 0
+			}
+		}
+	}
+}
+
+// Forget a deleted framebuffer. GL binds the default one instead.
+func (c *Cache) ForgetFramebuffer(name uint32) {
+	if c != nil && c.framebuffer == name {
+		c.framebuffer = 0
+	}
+}
+
+// Forget a deleted renderbuffer
+func (c *Cache) ForgetRenderbuffer(name uint32) {
+	if c != nil && c.renderbuffer == name {
+		c.renderbuffer = 0
+	}
+}
@@ -0,0 +1,67 @@
+// Package gltrack serves the GL objects recorded by gles2.SetTracking over
+// HTTP, to watch a running program for leaks:
+//
+//	gles2.SetTracking(true)
+//	http.Handle("/debug/gl/objects", gltrack.Handler())
+//	go http.ListenAndServe("localhost:6060", nil)
+//
+// The handler writes a summary by kind, with ?stacks=1 every live object
+// with the stack that created it, and with ?format=json the objects as JSON.
+package gltrack
+
+import (
+	"encoding/json"
+	"net/http"
+
+	gl "github.com/FEEDFACE-COM/piglet/gles2"
+)
+
+// An Object is a live GL object as served in JSON
+type Object struct {
+	Kind    string `json:"kind"`
+	Name    uint32 `json:"name"`
+	Bytes   int64  `json:"bytes"`
+	Created string `json:"created"`
+	Stack   string `json:"stack,omitempty"`
+}
+
+// A Report is the JSON served by Handler
+type Report struct {
+	Tracking bool     `json:"tracking"`
+	Bytes    int64    `json:"bytes"`
+	Objects  []Object `json:"objects"`
+}
+
+// Return a handler reporting the live tracked objects
+func Handler() http.Handler {
+	return http.HandlerFunc(serve)
+}
+
+func serve(w http.ResponseWriter, r *http.Request) {
+	stacks := r.FormValue("stacks") != "" && r.FormValue("stacks") != "0"
+	if r.FormValue("format") == "json" {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(NewReport(stacks))
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	if !gl.Tracking() {
+		w.Write([]byte("gles2: tracking is off, call gles2.SetTracking(true) after creating the context\n"))
+		return
+	}
+	gl.WriteTrackingReport(w, stacks)
+}
+
+// Return a report of the live tracked objects, with their stacks if stacks is set
+func NewReport(stacks bool) Report {
+	ret := Report{Tracking: gl.Tracking(), Objects: []Object{}}
+	for _, o := range gl.TrackedObjects() {
+		obj := Object{Kind: o.Kind, Name: o.Name, Bytes: o.Bytes, Created: o.Created.Format("2006-01-02T15:04:05.000Z07:00")}
+		if stacks {
+			obj.Stack = o.Stack()
+		}
+		ret.Bytes += o.Bytes
+		ret.Objects = append(ret.Objects, obj)
+	}
+	return ret
+}
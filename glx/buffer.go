@@ -0,0 +1,67 @@
+package glx
+
+import (
+	gl "github.com/FEEDFACE-COM/piglet/gles2"
+)
+
+// A Buffer holds vertex or index data in GL memory
+type Buffer struct {
+	name   uint32
+	target BufferTarget
+}
+
+// Create a new buffer, for binding to target
+func NewBuffer(target BufferTarget) Buffer {
+	b := Buffer{target: target}
+	gl.GenBuffers(1, &b.name)
+	return b
+}
+
+// Return the GL name of the buffer
+func (b Buffer) Name() uint32 { return b.name }
+
+// Return the target the buffer binds to
+func (b Buffer) Target() BufferTarget { return b.target }
+
+// Bind the buffer to its target
+func (b Buffer) Bind() {
+	state.BindBuffer(uint32(b.target), b.name)
+}
+
+// Unbind whatever buffer is bound to target
+func UnbindBuffer(target BufferTarget) {
+	state.BindBuffer(uint32(target), 0)
+}
+
+// Bind the buffer and replace its data store with a copy of data, which must
+// be a slice or a pointer to a fixed size value, eg. []float32 or *[16]float32
+func (b Buffer) SetData(data interface{}, usage Usage) {
+	ptr, size := dataPtr(data)
+	b.Bind()
+	gl.BufferData(uint32(b.target), size, ptr, uint32(usage))
+}
+
+// Bind the buffer and allocate size bytes of uninitialized storage
+func (b Buffer) Alloc(size int, usage Usage) {
+	b.Bind()
+	gl.BufferData(uint32(b.target), size, nil, uint32(usage))
+}
+
+// Bind the buffer and overwrite its data store from offset bytes on
+func (b Buffer) SetSubData(offset int, data interface{}) {
+	ptr, size := dataPtr(data)
+	if size == 0 {
+		return
+	}
+	b.Bind()
+	gl.BufferSubData(uint32(b.target), offset, size, ptr)
+}
+
+// Delete the buffer
+func (b *Buffer) Delete() {
+	if b.name != 0 {
+		gl.DeleteBuffers(1, &b.name)
+		state.ForgetBuffer(b.name)
+		b.name = 0
+	}
+}
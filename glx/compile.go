@@ -0,0 +1,202 @@
+package glx
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	gl "github.com/FEEDFACE-COM/piglet/gles2"
+)
+
+// lines of source shown around an error
+const snippetContext = 1
+
+// A LogMessage is one line of a shader info log. Line is the line in the
+// shader source it refers to, or 0 if the driver did not say.
+type LogMessage struct {
+	Line int
+	Text string
+}
+
+// A LineMap maps lines of a generated shader source back to the file and
+// line they came from, eg. after resolving includes
+type LineMap interface {
+	Origin(line int) (file string, n int, ok bool)
+}
+
+// A CompileError carries the info log of a shader that failed to compile
+type CompileError struct {
+	Type     ShaderType
+	Source   string
+	Log      string
+	Messages []LogMessage
+	Map      LineMap // optional, for reporting original file and line
+}
+
+// matches "ERROR: 0:12: msg" as logged by VideoCore, and "0:12(5): error: msg" as by mesa
+var logLineRegexp = regexp.MustCompile(`^(?:(?:ERROR|WARNING):\s*)?\d+:(\d+)(?:\(\d+\))?:\s*(.*)$`)
+
+// Split an info log into messages, with line numbers where the driver gives them
+func parseInfoLog(log string) []LogMessage {
+	var ret []LogMessage
+	for _, line := range strings.Split(log, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if m := logLineRegexp.FindStringSubmatch(line); m != nil {
+			n, _ := strconv.Atoi(m[1])
+			ret = append(ret, LogMessage{Line: n, Text: m[2]})
+		} else {
+			ret = append(ret, LogMessage{Text: line})
+		}
+	}
+	return ret
+}
+
+// Return the lines of source around line, the offending one marked
+func snippet(source string, line int) string {
+	lines := strings.Split(source, "\n")
+	if line < 1 || line > len(lines) {
+		return ""
+	}
+	var ret strings.Builder
+	for i := line - snippetContext; i <= line+snippetContext; i++ {
+		if i < 1 || i > len(lines) {
+			continue
+		}
+		mark := " "
+		if i == line {
+			mark = ">"
+		}
+		fmt.Fprintf(&ret, "  %s%4d | %s\n", mark, i, lines[i-1])
+	}
+	return ret.String()
+}
+
+func (t ShaderType) String() string {
+	switch t {
+	case VertexShader:
+		return "vertex"
+	case FragmentShader:
+		return "fragment"
+	}
+	return fmt.Sprintf("0x%04x", uint32(t))
+}
+
+func (e *CompileError) Error() string {
+	var ret strings.Builder
+	fmt.Fprintf(&ret, "fail to compile %s shader", e.Type)
+	if len(e.Messages) == 0 {
+		return ret.String()
+	}
+	ret.WriteString(":\n")
+	for _, msg := range e.Messages {
+		if msg.Line <= 0 {
+			fmt.Fprintf(&ret, "%s\n", msg.Text)
+			continue
+		}
+		if e.Map != nil {
+			if file, n, ok := e.Map.Origin(msg.Line); ok {
+				fmt.Fprintf(&ret, "%s:%d: %s\n", file, n, msg.Text)
+				ret.WriteString(snippet(e.Source, msg.Line))
+				continue
+			}
+		}
+		fmt.Fprintf(&ret, "line %d: %s\n", msg.Line, msg.Text)
+		ret.WriteString(snippet(e.Source, msg.Line))
+	}
+	return strings.TrimSuffix(ret.String(), "\n")
+}
+
+// A LinkError carries the info log of a program that failed to link or validate
+type LinkError struct {
+	Op  string // "link" or "validate"
+	Log string
+}
+
+func (e *LinkError) Error() string {
+	if e.Log == "" {
+		return "fail to " + e.Op + " program"
+	}
+	return "fail to " + e.Op + " program:\n" + strings.TrimSpace(e.Log)
+}
+
+// Return the info log of the shader, as left by the last compile
+func (s Shader) InfoLog() string {
+	var length int32
+	gl.GetShaderiv(s.name, gl.INFO_LOG_LENGTH, &length)
+	if length <= 1 {
+		return ""
+	}
+	log := make([]uint8, length)
+	gl.GetShaderInfoLog(s.name, length, nil, &log[0])
+	return strings.TrimRight(string(log), "\x00")
+}
+
+// Return the info log of the program, as left by the last link or validate
+func (p *Program) InfoLog() string {
+	var length int32
+	gl.GetProgramiv(p.name, gl.INFO_LOG_LENGTH, &length)
+	if length <= 1 {
+		return ""
+	}
+	log := make([]uint8, length)
+	gl.GetProgramInfoLog(p.name, length, nil, &log[0])
+	return strings.TrimRight(string(log), "\x00")
+}
+
+// Check whether the program can execute in the current GL state, eg. with
+// its samplers and textures set up, right before drawing while debugging
+func (p *Program) Validate() error {
+	var status int32
+	gl.ValidateProgram(p.name)
+	gl.GetProgramiv(p.name, gl.VALIDATE_STATUS, &status)
+	if status == gl.FALSE {
+		return &LinkError{Op: "validate", Log: p.InfoLog()}
+	}
+	return nil
+}
+
+// Compile source into a new shader of type xtype. On failure the shader
+// is deleted and the error is a *CompileError.
+func CompileShader(xtype ShaderType, source string) (Shader, error) {
+	shader := NewShader(xtype)
+	shader.SetSource(source)
+	if err := shader.Compile(); err != nil {
+		shader.Delete()
+		return Shader{}, err
+	}
+	return shader, nil
+}
+
+// Compile vertex and fragment sources, and link them into a new program.
+// The shader objects are deleted either way, as the program keeps what it
+// needs. Compile errors are *CompileError, link errors *LinkError. The
+// program is not validated, as that depends on the state at draw time, see
+// Validate.
+func CompileProgram(vertexSource, fragmentSource string) (*Program, error) {
+	vertex, err := CompileShader(VertexShader, vertexSource)
+	if err != nil {
+		return nil, err
+	}
+	defer vertex.Delete()
+	fragment, err := CompileShader(FragmentShader, fragmentSource)
+	if err != nil {
+		return nil, err
+	}
+	defer fragment.Delete()
+
+	program := NewProgram()
+	program.Attach(vertex)
+	program.Attach(fragment)
+	err = program.Link()
+	program.Detach(vertex)
+	program.Detach(fragment)
+	if err != nil {
+		program.Delete()
+		return nil, err
+	}
+	return program, nil
+}
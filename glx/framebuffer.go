@@ -0,0 +1,135 @@
+package glx
+
+import (
+	"fmt"
+
+	gl "github.com/FEEDFACE-COM/piglet/gles2"
+)
+
+// A Framebuffer collects attachments to render into, instead of the display
+type Framebuffer struct {
+	name uint32
+}
+
+// Create a new framebuffer, without attachments
+func NewFramebuffer() Framebuffer {
+	var f Framebuffer
+	gl.GenFramebuffers(1, &f.name)
+	return f
+}
+
+// Return the GL name of the framebuffer
+func (f Framebuffer) Name() uint32 { return f.name }
+
+// Bind the framebuffer for rendering
+func (f Framebuffer) Bind() {
+	state.BindFramebuffer(f.name)
+}
+
+// Bind the default framebuffer, ie. render to the display again
+func UnbindFramebuffer() {
+	state.BindFramebuffer(0)
+}
+
+// Bind the framebuffer and attach mipmap level level of a 2D texture
+func (f Framebuffer) AttachTexture(attachment Attachment, texture Texture, level int) {
+	f.Bind()
+	gl.FramebufferTexture2D(gl.FRAMEBUFFER, uint32(attachment), uint32(texture.target), texture.name, int32(level))
+}
+
+// Bind the framebuffer and attach a renderbuffer
+func (f Framebuffer) AttachRenderbuffer(attachment Attachment, renderbuffer Renderbuffer) {
+	f.Bind()
+	gl.FramebufferRenderbuffer(gl.FRAMEBUFFER, uint32(attachment), gl.RENDERBUFFER, renderbuffer.name)
+}
+
+// Completeness statuses of framebuffers
+type FramebufferStatus uint32
+
+const (
+	FramebufferComplete                    FramebufferStatus = gl.FRAMEBUFFER_COMPLETE
+	FramebufferIncompleteAttachment        FramebufferStatus = gl.FRAMEBUFFER_INCOMPLETE_ATTACHMENT
+	FramebufferIncompleteDimensions        FramebufferStatus = gl.FRAMEBUFFER_INCOMPLETE_DIMENSIONS
+	FramebufferIncompleteMissingAttachment FramebufferStatus = gl.FRAMEBUFFER_INCOMPLETE_MISSING_ATTACHMENT
+	FramebufferUnsupported                 FramebufferStatus = gl.FRAMEBUFFER_UNSUPPORTED
+)
+
+var framebufferStatusNames = map[FramebufferStatus]string{
+	FramebufferComplete:                    "FRAMEBUFFER_COMPLETE",
+	FramebufferIncompleteAttachment:        "FRAMEBUFFER_INCOMPLETE_ATTACHMENT",
+	FramebufferIncompleteDimensions:        "FRAMEBUFFER_INCOMPLETE_DIMENSIONS",
+	FramebufferIncompleteMissingAttachment: "FRAMEBUFFER_INCOMPLETE_MISSING_ATTACHMENT",
+	FramebufferUnsupported:                 "FRAMEBUFFER_UNSUPPORTED",
+}
+
+func (s FramebufferStatus) String() string {
+	if name, ok := framebufferStatusNames[s]; ok {
+		return name
+	}
+	return fmt.Sprintf("framebuffer status 0x%04X", uint32(s))
+}
+
+// Explain what usually causes s on VideoCore IV
+func (s FramebufferStatus) Hint() string {
+	switch s {
+	case FramebufferIncompleteAttachment:
+		return "an attachment has a format that can not be rendered to, or no storage"
+	case FramebufferIncompleteDimensions:
+		return "the attachments differ in size"
+	case FramebufferIncompleteMissingAttachment:
+		return "there is no attachment"
+	case FramebufferUnsupported:
+		return "the combination of formats is not supported, eg. separate depth and stencil buffers"
+	}
+	return ""
+}
+
+// Bind the framebuffer and return its completeness status
+func (f Framebuffer) Status() FramebufferStatus {
+	f.Bind()
+	return FramebufferStatus(gl.CheckFramebufferStatus(gl.FRAMEBUFFER))
+}
+
+// Delete the framebuffer
+func (f *Framebuffer) Delete() {
+	if f.name != 0 {
+		gl.DeleteFramebuffers(1, &f.name)
+		state.ForgetFramebuffer(f.name)
+		f.name = 0
+	}
+}
+
+// A Renderbuffer is an image to render into that can not be sampled
+type Renderbuffer struct {
+	name uint32
+}
+
+// Create a new renderbuffer, without storage
+func NewRenderbuffer() Renderbuffer {
+	var r Renderbuffer
+	gl.GenRenderbuffers(1, &r.name)
+	return r
+}
+
+// Return the GL name of the renderbuffer
+func (r Renderbuffer) Name() uint32 { return r.name }
+
+// Bind the renderbuffer
+func (r Renderbuffer) Bind() {
+	state.BindRenderbuffer(r.name)
+}
+
+// Bind the renderbuffer and allocate w*h pixels of storage in format
+func (r Renderbuffer) Storage(format RenderbufferFormat, w, h int) {
+	r.Bind()
+	gl.RenderbufferStorage(gl.RENDERBUFFER, uint32(format), int32(w), int32(h))
+}
+
+// Delete the renderbuffer
+func (r *Renderbuffer) Delete() {
+	if r.name != 0 {
+		gl.DeleteRenderbuffers(1, &r.name)
+		state.ForgetRenderbuffer(r.name)
+		r.name = 0
+	}
+}
@@ -0,0 +1,164 @@
+// Package glx wraps the generated gles2 functions in distinct types for
+// each kind of GL object, so that passing a texture to BindBuffer or a
+// shader to UseProgram fails to compile instead of failing at runtime.
+//
+// All functions must be called from the thread that holds the current
+// piglet context, after gles2.InitWithProcAddrFunc.
+package glx
+
+import (
+	"reflect"
+	"unsafe"
+
+	gl "github.com/FEEDFACE-COM/piglet/gles2"
+)
+
+// Buffer binding targets
+type BufferTarget uint32
+
+const (
+	ArrayBuffer        BufferTarget = gl.ARRAY_BUFFER
+	ElementArrayBuffer BufferTarget = gl.ELEMENT_ARRAY_BUFFER
+)
+
+// Buffer data usage hints
+type Usage uint32
+
+const (
+	StaticDraw  Usage = gl.STATIC_DRAW
+	DynamicDraw Usage = gl.DYNAMIC_DRAW
+	StreamDraw  Usage = gl.STREAM_DRAW
+)
+
+// Texture binding targets
+type TextureTarget uint32
+
+const (
+	Texture2D          TextureTarget = gl.TEXTURE_2D
+	TextureCubeMap     TextureTarget = gl.TEXTURE_CUBE_MAP
+	TextureExternalOES TextureTarget = gl.TEXTURE_EXTERNAL_OES
+)
+
+// Faces of a cube map, for uploading images
+type CubeFace uint32
+
+const (
+	CubePositiveX CubeFace = gl.TEXTURE_CUBE_MAP_POSITIVE_X
+	CubeNegativeX CubeFace = gl.TEXTURE_CUBE_MAP_NEGATIVE_X
+	CubePositiveY CubeFace = gl.TEXTURE_CUBE_MAP_POSITIVE_Y
+	CubeNegativeY CubeFace = gl.TEXTURE_CUBE_MAP_NEGATIVE_Y
+	CubePositiveZ CubeFace = gl.TEXTURE_CUBE_MAP_POSITIVE_Z
+	CubeNegativeZ CubeFace = gl.TEXTURE_CUBE_MAP_NEGATIVE_Z
+)
+
+// Pixel formats of texture images
+type Format uint32
+
+const (
+	Alpha          Format = gl.ALPHA
+	Luminance      Format = gl.LUMINANCE
+	LuminanceAlpha Format = gl.LUMINANCE_ALPHA
+	RGB            Format = gl.RGB
+	RGBA           Format = gl.RGBA
+	BGRA           Format = gl.BGRA_EXT
+)
+
+// Component types of texture images
+type PixelType uint32
+
+const (
+	UnsignedByte      PixelType = gl.UNSIGNED_BYTE
+	UnsignedShort565  PixelType = gl.UNSIGNED_SHORT_5_6_5
+	UnsignedShort4444 PixelType = gl.UNSIGNED_SHORT_4_4_4_4
+	UnsignedShort5551 PixelType = gl.UNSIGNED_SHORT_5_5_5_1
+)
+
+// Texture minification and magnification filters
+type Filter int32
+
+const (
+	Nearest              Filter = gl.NEAREST
+	Linear               Filter = gl.LINEAR
+	NearestMipmapNearest Filter = gl.NEAREST_MIPMAP_NEAREST
+	LinearMipmapNearest  Filter = gl.LINEAR_MIPMAP_NEAREST
+	NearestMipmapLinear  Filter = gl.NEAREST_MIPMAP_LINEAR
+	LinearMipmapLinear   Filter = gl.LINEAR_MIPMAP_LINEAR
+)
+
+// Texture coordinate wrap modes
+type Wrap int32
+
+const (
+	ClampToEdge    Wrap = gl.CLAMP_TO_EDGE
+	Repeat         Wrap = gl.REPEAT
+	MirroredRepeat Wrap = gl.MIRRORED_REPEAT
+)
+
+// Shader stages
+type ShaderType uint32
+
+const (
+	VertexShader   ShaderType = gl.VERTEX_SHADER
+	FragmentShader ShaderType = gl.FRAGMENT_SHADER
+)
+
+// Framebuffer attachment points
+type Attachment uint32
+
+const (
+	ColorAttachment0  Attachment = gl.COLOR_ATTACHMENT0
+	DepthAttachment   Attachment = gl.DEPTH_ATTACHMENT
+	StencilAttachment Attachment = gl.STENCIL_ATTACHMENT
+)
+
+// Renderbuffer storage formats
+type RenderbufferFormat uint32
+
+const (
+	RGBA4            RenderbufferFormat = gl.RGBA4
+	RGB5A1           RenderbufferFormat = gl.RGB5_A1
+	RGB565           RenderbufferFormat = gl.RGB565
+	DepthComponent16 RenderbufferFormat = gl.DEPTH_COMPONENT16
+	DepthComponent24 RenderbufferFormat = gl.DEPTH_COMPONENT24_OES
+	StencilIndex8    RenderbufferFormat = gl.STENCIL_INDEX8
+	Depth24Stencil8  RenderbufferFormat = gl.DEPTH24_STENCIL8_OES
+	RGB8             RenderbufferFormat = gl.RGB8_OES
+	RGBA8            RenderbufferFormat = gl.RGBA8_OES
+)
+
+// Primitives to draw
+type Mode uint32
+
+const (
+	Points        Mode = gl.POINTS
+	Lines         Mode = gl.LINES
+	LineLoop      Mode = gl.LINE_LOOP
+	LineStrip     Mode = gl.LINE_STRIP
+	Triangles     Mode = gl.TRIANGLES
+	TriangleStrip Mode = gl.TRIANGLE_STRIP
+	TriangleFan   Mode = gl.TRIANGLE_FAN
+)
+
+// Draw count vertices, starting at first, from the bound array buffers
+func DrawArrays(mode Mode, first, count int) {
+	gl.DrawArrays(uint32(mode), int32(first), int32(count))
+}
+
+// Return the address and size in bytes of a slice or of a pointer to a
+// fixed size value, for passing to GL
+func dataPtr(data interface{}) (unsafe.Pointer, int) {
+	if data == nil {
+		return nil, 0
+	}
+	v := reflect.ValueOf(data)
+	switch v.Kind() {
+	case reflect.Slice:
+		if v.Len() == 0 {
+			return nil, 0
+		}
+		return unsafe.Pointer(v.Pointer()), v.Len() * int(v.Type().Elem().Size())
+	case reflect.Ptr:
+		return unsafe.Pointer(v.Pointer()), int(v.Type().Elem().Size())
+	}
+	panic("glx: data must be a slice or a pointer, not " + v.Type().String())
+}
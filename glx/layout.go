@@ -0,0 +1,185 @@
+package glx
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	gl "github.com/FEEDFACE-COM/piglet/gles2"
+)
+
+// Component types of vertex attributes
+type VertexType uint32
+
+const (
+	VertexFloat         VertexType = gl.FLOAT
+	VertexByte          VertexType = gl.BYTE
+	VertexUnsignedByte  VertexType = gl.UNSIGNED_BYTE
+	VertexShort         VertexType = gl.SHORT
+	VertexUnsignedShort VertexType = gl.UNSIGNED_SHORT
+	VertexFixed         VertexType = gl.FIXED
+)
+
+// Return the size in bytes of one component
+func (t VertexType) Size() int {
+	switch t {
+	case VertexByte, VertexUnsignedByte:
+		return 1
+	case VertexShort, VertexUnsignedShort:
+		return 2
+	}
+	return 4
+}
+
+// An Attribute describes one vertex attribute within interleaved vertices
+type Attribute struct {
+	Name       string     // in the vertex shader
+	Size       int        // components, 1 to 4
+	Type       VertexType // VertexFloat if zero
+	Normalized bool       // map integers to [0,1] or [-1,1]
+	Offset     int        // in bytes from the start of a vertex, computed by NewLayout
+}
+
+// A Layout describes interleaved vertices, so their attributes can be bound
+// to programs by name without computing strides and offsets by hand
+type Layout struct {
+	Attributes []Attribute
+	Stride     int // bytes per vertex
+}
+
+// Return a layout of attrs packed in order. Offsets are aligned to 4 bytes,
+// as VideoCore IV is slow with unaligned attributes.
+func NewLayout(attrs ...Attribute) *Layout {
+	l := &Layout{}
+	for _, a := range attrs {
+		if a.Type == 0 {
+			a.Type = VertexFloat
+		}
+		a.Offset = align4(l.Stride)
+		l.Stride = a.Offset + a.Size*a.Type.Size()
+		l.Attributes = append(l.Attributes, a)
+	}
+	l.Stride = align4(l.Stride)
+	return l
+}
+
+func align4(n int) int { return (n + 3) &^ 3 }
+
+// Return the layout of the fields of a struct, eg.
+//
+//	type Vertex struct {
+//		Position mgl32.Vec3
+//		TexCoord [2]float32
+//		Color    [4]uint8 `attrib:"color,normalized"`
+//	}
+//	layout, err := glx.LayoutOf(Vertex{})
+//
+// Fields are float32, int8, uint8, int16 or uint16, or arrays of 1 to 4 of
+// them. Attributes are named after the tag, or the field name with its first
+// letter lower cased. Fields tagged "-" are skipped.
+func LayoutOf(vertex interface{}) (*Layout, error) {
+	t := reflect.TypeOf(vertex)
+	if t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("fail to get layout of %T: not a struct", vertex)
+	}
+	l := &Layout{Stride: int(t.Size())}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("attrib")
+		if tag == "-" {
+			continue
+		}
+		a := Attribute{Name: lowerFirst(field.Name), Offset: int(field.Offset), Size: 1}
+		if tag != "" {
+			parts := strings.Split(tag, ",")
+			if parts[0] != "" {
+				a.Name = parts[0]
+			}
+			for _, option := range parts[1:] {
+				if option != "normalized" {
+					return nil, fmt.Errorf("fail to get layout of %s: field %s has unknown option %s", t, field.Name, option)
+				}
+				a.Normalized = true
+			}
+		}
+		ft := field.Type
+		if ft.Kind() == reflect.Array {
+			a.Size = ft.Len()
+			ft = ft.Elem()
+		}
+		switch ft.Kind() {
+		case reflect.Float32:
+			a.Type = VertexFloat
+		case reflect.Int8:
+			a.Type = VertexByte
+		case reflect.Uint8:
+			a.Type = VertexUnsignedByte
+		case reflect.Int16:
+			a.Type = VertexShort
+		case reflect.Uint16:
+			a.Type = VertexUnsignedShort
+		default:
+			return nil, fmt.Errorf("fail to get layout of %s: field %s is %s", t, field.Name, field.Type)
+		}
+		if a.Size < 1 || a.Size > 4 {
+			return nil, fmt.Errorf("fail to get layout of %s: field %s has %d components", t, field.Name, a.Size)
+		}
+		l.Attributes = append(l.Attributes, a)
+	}
+	return l, nil
+}
+
+func lowerFirst(s string) string {
+	r, n := utf8.DecodeRuneInString(s)
+	return string(unicode.ToLower(r)) + s[n:]
+}
+
+// Return the attribute called name, or nil
+func (l *Layout) Attribute(name string) *Attribute {
+	for i := range l.Attributes {
+		if l.Attributes[i].Name == name {
+			return &l.Attributes[i]
+		}
+	}
+	return nil
+}
+
+// Point the attributes of p at the vertices in the bound array buffer,
+// starting at vertex first, and enable them. Attributes p does not use
+// are skipped, as the compiler drops unused ones.
+func (l *Layout) Enable(p *Program, first int) {
+	for _, a := range l.Attributes {
+		loc := p.Attrib(a.Name)
+		if loc < 0 {
+			continue
+		}
+		gl.EnableVertexAttribArray(uint32(loc))
+		gl.VertexAttribPointer(uint32(loc), int32(a.Size), uint32(a.Type), a.Normalized, int32(l.Stride), gl.PtrOffset(first*l.Stride+a.Offset))
+	}
+}
+
+// Disable the attributes of p enabled by Enable
+func (l *Layout) Disable(p *Program) {
+	for _, a := range l.Attributes {
+		if loc := p.Attrib(a.Name); loc >= 0 {
+			gl.DisableVertexAttribArray(uint32(loc))
+		}
+	}
+}
+
+// Return the attributes that p uses but l lacks, to check a layout against
+// a program once after linking
+func (l *Layout) Missing(p *Program) []string {
+	var ret []string
+	for _, v := range p.Attribs() {
+		if l.Attribute(v.Name) == nil {
+			ret = append(ret, v.Name)
+		}
+	}
+	return ret
+}
@@ -0,0 +1,242 @@
+package glx
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	gl "github.com/FEEDFACE-COM/piglet/gles2"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// GLSL types of attributes and uniforms
+type DataType uint32
+
+const (
+	Float              DataType = gl.FLOAT
+	FloatVec2          DataType = gl.FLOAT_VEC2
+	FloatVec3          DataType = gl.FLOAT_VEC3
+	FloatVec4          DataType = gl.FLOAT_VEC4
+	Int                DataType = gl.INT
+	IntVec2            DataType = gl.INT_VEC2
+	IntVec3            DataType = gl.INT_VEC3
+	IntVec4            DataType = gl.INT_VEC4
+	Bool               DataType = gl.BOOL
+	BoolVec2           DataType = gl.BOOL_VEC2
+	BoolVec3           DataType = gl.BOOL_VEC3
+	BoolVec4           DataType = gl.BOOL_VEC4
+	FloatMat2          DataType = gl.FLOAT_MAT2
+	FloatMat3          DataType = gl.FLOAT_MAT3
+	FloatMat4          DataType = gl.FLOAT_MAT4
+	Sampler2D          DataType = gl.SAMPLER_2D
+	SamplerCube        DataType = gl.SAMPLER_CUBE
+	SamplerExternalOES DataType = gl.SAMPLER_EXTERNAL_OES
+)
+
+var dataTypeNames = map[DataType]string{
+	Float: "float", FloatVec2: "vec2", FloatVec3: "vec3", FloatVec4: "vec4",
+	Int: "int", IntVec2: "ivec2", IntVec3: "ivec3", IntVec4: "ivec4",
+	Bool: "bool", BoolVec2: "bvec2", BoolVec3: "bvec3", BoolVec4: "bvec4",
+	FloatMat2: "mat2", FloatMat3: "mat3", FloatMat4: "mat4",
+	Sampler2D: "sampler2D", SamplerCube: "samplerCube", SamplerExternalOES: "samplerExternalOES",
+}
+
+// Return the GLSL name of the type, eg. "mat4"
+func (t DataType) String() string {
+	if name, ok := dataTypeNames[t]; ok {
+		return name
+	}
+	return fmt.Sprintf("0x%04x", uint32(t))
+}
+
+// A Variable is an active attribute or uniform of a linked program
+type Variable struct {
+	Name     string   // without any [0] suffix
+	Location int32    // of the first element, for arrays
+	Type     DataType // of the elements, for arrays
+	Size     int32    // number of elements, 1 unless an array
+}
+
+// Query the active attributes and uniforms of the linked program, and
+// cache them by name
+func (p *Program) reflect() {
+	p.attribs = p.active(gl.ACTIVE_ATTRIBUTES, gl.ACTIVE_ATTRIBUTE_MAX_LENGTH, gl.GetActiveAttrib, gl.GetAttribLocation)
+	p.uniforms = p.active(gl.ACTIVE_UNIFORMS, gl.ACTIVE_UNIFORM_MAX_LENGTH, gl.GetActiveUniform, gl.GetUniformLocation)
+	p.elements = map[string]Variable{}
+}
+
+func (p *Program) active(
+	countName, lengthName uint32,
+	getActive func(uint32, uint32, int32, *int32, *int32, *uint32, *uint8),
+	getLocation func(uint32, *uint8) int32,
+) map[string]Variable {
+	var count, maxLength int32
+	gl.GetProgramiv(p.name, countName, &count)
+	gl.GetProgramiv(p.name, lengthName, &maxLength)
+	ret := make(map[string]Variable, count)
+	buf := make([]uint8, maxLength+1)
+	for i := int32(0); i < count; i++ {
+		var length, size int32
+		var xtype uint32
+		getActive(p.name, uint32(i), int32(len(buf)), &length, &size, &xtype, &buf[0])
+		name := string(buf[:length])
+		v := Variable{
+			Name:     strings.TrimSuffix(name, "[0]"),
+			Location: getLocation(p.name, gl.Str(name+"\x00")),
+			Type:     DataType(xtype),
+			Size:     size,
+		}
+		ret[v.Name] = v
+	}
+	return ret
+}
+
+func sortedVariables(vars map[string]Variable) []Variable {
+	ret := make([]Variable, 0, len(vars))
+	for _, v := range vars {
+		ret = append(ret, v)
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].Name < ret[j].Name })
+	return ret
+}
+
+// Return the active attributes of the program, sorted by name
+func (p *Program) Attribs() []Variable { return sortedVariables(p.attribs) }
+
+// Return the active uniforms of the program, sorted by name
+func (p *Program) Uniforms() []Variable { return sortedVariables(p.uniforms) }
+
+// Return the active attribute called name
+func (p *Program) AttribInfo(name string) (Variable, bool) {
+	v, ok := p.attribs[name]
+	return v, ok
+}
+
+// Return the active uniform called name. For an element name[k] of an
+// array, return its own location, and the Size of the rest of the array.
+func (p *Program) UniformInfo(name string) (Variable, bool) {
+	if v, ok := p.uniforms[name]; ok {
+		return v, true
+	}
+	if v, ok := p.elements[name]; ok {
+		return v, true
+	}
+	i := strings.LastIndexByte(name, '[')
+	if i < 0 || !strings.HasSuffix(name, "]") {
+		return Variable{}, false
+	}
+	k, err := strconv.Atoi(name[i+1 : len(name)-1])
+	v, ok := p.uniforms[name[:i]]
+	if err != nil || !ok || k < 0 || k >= int(v.Size) {
+		return Variable{}, false
+	}
+	v.Name = name
+	v.Location = gl.GetUniformLocation(p.name, gl.Str(name+"\x00"))
+	v.Size -= int32(k)
+	if p.elements != nil {
+		p.elements[name] = v
+	}
+	return v, true
+}
+
+// Set the uniform called name of the program in use to value, checking
+// that the program has such a uniform and that value matches its type.
+// Name may also be an element of an array, eg. lights[2]. Value types:
+//
+//	float32, []float32       float
+//	int32, int, bool         int, bool and samplers
+//	mgl32.Vec2, Vec3, Vec4   vec2, vec3, vec4
+//	mgl32.Mat2, Mat3, Mat4   mat2, mat3, mat4
+//	[]mgl32.Vec4             vec4 arrays
+//
+// The program must be in use, see Use.
+func (p *Program) SetUniform(name string, value interface{}) error {
+	v, ok := p.UniformInfo(name)
+	if !ok {
+		return fmt.Errorf("fail to set uniform %s: no such active uniform", name)
+	}
+	loc := v.Location
+	mismatch := func() error {
+		return fmt.Errorf("fail to set uniform %s: is %s, not %T", name, v.Type, value)
+	}
+	tooLong := func(n int) error {
+		return fmt.Errorf("fail to set uniform %s: is %s[%d], not [%d]", name, v.Type, v.Size, n)
+	}
+	switch x := value.(type) {
+	case float32:
+		if v.Type != Float {
+			return mismatch()
+		}
+		gl.Uniform1f(loc, x)
+	case []float32:
+		if v.Type != Float {
+			return mismatch()
+		} else if len(x) > int(v.Size) {
+			return tooLong(len(x))
+		}
+		if len(x) > 0 {
+			gl.Uniform1fv(loc, int32(len(x)), &x[0])
+		}
+	case int32, int, bool:
+		switch v.Type {
+		case Int, Bool, Sampler2D, SamplerCube, SamplerExternalOES:
+		default:
+			return mismatch()
+		}
+		var i int32
+		switch x := x.(type) {
+		case int32:
+			i = x
+		case int:
+			i = int32(x)
+		case bool:
+			if x {
+				i = 1
+			}
+		}
+		gl.Uniform1i(loc, i)
+	case mgl32.Vec2:
+		if v.Type != FloatVec2 {
+			return mismatch()
+		}
+		gl.Uniform2f(loc, x[0], x[1])
+	case mgl32.Vec3:
+		if v.Type != FloatVec3 {
+			return mismatch()
+		}
+		gl.Uniform3f(loc, x[0], x[1], x[2])
+	case mgl32.Vec4:
+		if v.Type != FloatVec4 {
+			return mismatch()
+		}
+		gl.Uniform4f(loc, x[0], x[1], x[2], x[3])
+	case []mgl32.Vec4:
+		if v.Type != FloatVec4 {
+			return mismatch()
+		} else if len(x) > int(v.Size) {
+			return tooLong(len(x))
+		}
+		if len(x) > 0 {
+			gl.Uniform4fv(loc, int32(len(x)), &x[0][0])
+		}
+	case mgl32.Mat2:
+		if v.Type != FloatMat2 {
+			return mismatch()
+		}
+		gl.UniformMatrix2fv(loc, 1, false, &x[0])
+	case mgl32.Mat3:
+		if v.Type != FloatMat3 {
+			return mismatch()
+		}
+		gl.UniformMatrix3fv(loc, 1, false, &x[0])
+	case mgl32.Mat4:
+		if v.Type != FloatMat4 {
+			return mismatch()
+		}
+		gl.UniformMatrix4fv(loc, 1, false, &x[0])
+	default:
+		return fmt.Errorf("fail to set uniform %s: unsupported type %T", name, value)
+	}
+	return nil
+}
@@ -0,0 +1,169 @@
+package glx
+
+import (
+	"errors"
+	"fmt"
+
+	gl "github.com/FEEDFACE-COM/piglet/gles2"
+)
+
+// Options of a RenderTarget
+type RenderTargetOptions struct {
+	Format  Format    // of the color texture, RGBA if zero. VideoCore renders to RGBA and RGB.
+	Type    PixelType // UnsignedByte if zero, or UnsignedShort565 with RGB, UnsignedShort4444 or UnsignedShort5551 with RGBA
+	Filter  Filter    // of the color texture, Linear if zero
+	Depth   bool      // add a depth buffer, 24 bit with GL_OES_depth24
+	Stencil bool      // add a stencil buffer, packed with depth if both are set
+}
+
+// A RenderTarget renders into a texture, with optional depth and stencil
+type RenderTarget struct {
+	Framebuffer Framebuffer
+	Color       Texture
+	Depth       Renderbuffer // also holds stencil if both are packed
+	Stencil     Renderbuffer
+	Width       int
+	Height      int
+
+	opts     RenderTargetOptions
+	discard  []uint32
+	viewport [4]int32
+	previous uint32
+	bound    bool
+}
+
+// An error of a framebuffer that is not complete
+type FramebufferError struct {
+	Status FramebufferStatus
+}
+
+func (e *FramebufferError) Error() string {
+	ret := "fail to complete framebuffer: " + e.Status.String()
+	if hint := e.Status.Hint(); hint != "" {
+		ret += ", " + hint
+	}
+	return ret
+}
+
+// Create a render target of w*h pixels. Fails with a *FramebufferError if
+// the driver does not support the combination of formats.
+func NewRenderTarget(w, h int, opts RenderTargetOptions) (*RenderTarget, error) {
+	if opts.Format == 0 {
+		opts.Format = RGBA
+	}
+	if opts.Type == 0 {
+		opts.Type = UnsignedByte
+	}
+	if opts.Filter == 0 {
+		opts.Filter = Linear
+	}
+	switch {
+	case opts.Format == RGBA && (opts.Type == UnsignedByte || opts.Type == UnsignedShort4444 || opts.Type == UnsignedShort5551):
+	case opts.Format == RGB && (opts.Type == UnsignedByte || opts.Type == UnsignedShort565):
+	default:
+		return nil, fmt.Errorf("fail to create render target: can not render to format 0x%04X type 0x%04X", uint32(opts.Format), uint32(opts.Type))
+	}
+	if opts.Depth && opts.Stencil && !gl.HasExtension("GL_OES_packed_depth_stencil") {
+		return nil, errors.New("fail to create render target: depth with stencil needs GL_OES_packed_depth_stencil")
+	}
+	rt := &RenderTarget{Framebuffer: NewFramebuffer(), Color: NewTexture(Texture2D), opts: opts}
+	if err := rt.allocate(w, h); err != nil {
+		rt.Delete()
+		return nil, err
+	}
+	return rt, nil
+}
+
+// Allocate attachments of w*h pixels and check completeness
+func (rt *RenderTarget) allocate(w, h int) error {
+	defer state.BindFramebuffer(state.Framebuffer())
+
+	rt.Width, rt.Height = w, h
+	rt.Color.SetImage(0, rt.opts.Format, w, h, rt.opts.Type, nil)
+	rt.Color.SetFilter(rt.opts.Filter, rt.opts.Filter)
+	rt.Color.SetWrap(ClampToEdge, ClampToEdge)
+	rt.Framebuffer.AttachTexture(ColorAttachment0, rt.Color, 0)
+
+	rt.discard = rt.discard[:0]
+	switch {
+	case rt.opts.Depth && rt.opts.Stencil:
+		rt.storage(&rt.Depth, Depth24Stencil8, w, h)
+		rt.Framebuffer.AttachRenderbuffer(DepthAttachment, rt.Depth)
+		rt.Framebuffer.AttachRenderbuffer(StencilAttachment, rt.Depth)
+		rt.discard = append(rt.discard, gl.DEPTH_ATTACHMENT, gl.STENCIL_ATTACHMENT)
+	case rt.opts.Depth:
+		format := DepthComponent16
+		if gl.HasExtension("GL_OES_depth24") {
+			format = DepthComponent24
+		}
+		rt.storage(&rt.Depth, format, w, h)
+		rt.Framebuffer.AttachRenderbuffer(DepthAttachment, rt.Depth)
+		rt.discard = append(rt.discard, gl.DEPTH_ATTACHMENT)
+	case rt.opts.Stencil:
+		rt.storage(&rt.Stencil, StencilIndex8, w, h)
+		rt.Framebuffer.AttachRenderbuffer(StencilAttachment, rt.Stencil)
+		rt.discard = append(rt.discard, gl.STENCIL_ATTACHMENT)
+	}
+	if status := rt.Framebuffer.Status(); status != FramebufferComplete {
+		return &FramebufferError{Status: status}
+	}
+	return nil
+}
+
+func (rt *RenderTarget) storage(r *Renderbuffer, format RenderbufferFormat, w, h int) {
+	if r.name == 0 {
+		*r = NewRenderbuffer()
+	}
+	r.Storage(format, w, h)
+}
+
+// Reallocate the attachments at a new size, eg. after the display size
+// changed. The content is lost.
+func (rt *RenderTarget) Resize(w, h int) error {
+	if w == rt.Width && h == rt.Height {
+		return nil
+	}
+	return rt.allocate(w, h)
+}
+
+// Start rendering into the target: save the bound framebuffer and the
+// viewport, then bind the target and set the viewport to cover it
+func (rt *RenderTarget) Begin() {
+	rt.previous = state.Framebuffer()
+	rt.viewport = state.GetViewport()
+	rt.Framebuffer.Bind()
+	state.Viewport(0, 0, int32(rt.Width), int32(rt.Height))
+	rt.bound = true
+}
+
+// Finish rendering into the target: discard depth and stencil, which are
+// not needed after the pass, and restore the framebuffer and viewport
+// saved by Begin. Discarding spares VideoCore writing them back from tile
+// memory, and needs GL_EXT_discard_framebuffer.
+func (rt *RenderTarget) End() {
+	if !rt.bound {
+		return
+	}
+	rt.Discard(rt.discard...)
+	state.BindFramebuffer(rt.previous)
+	state.Viewport(rt.viewport[0], rt.viewport[1], rt.viewport[2], rt.viewport[3])
+	rt.bound = false
+}
+
+// Discard attachments of the bound target, eg. gl.COLOR_ATTACHMENT0 right
+// after Begin when every pixel is about to be overwritten. A no-op without
+// GL_EXT_discard_framebuffer.
+func (rt *RenderTarget) Discard(attachments ...uint32) {
+	if len(attachments) == 0 || !gl.HasExtension("GL_EXT_discard_framebuffer") {
+		return
+	}
+	gl.DiscardFramebufferEXT(gl.FRAMEBUFFER, int32(len(attachments)), &attachments[0])
+}
+
+// Delete the framebuffer and its attachments
+func (rt *RenderTarget) Delete() {
+	rt.Framebuffer.Delete()
+	rt.Color.Delete()
+	rt.Depth.Delete()
+	rt.Stencil.Delete()
+}
@@ -0,0 +1,167 @@
+package glx
+
+import (
+	"strings"
+
+	gl "github.com/FEEDFACE-COM/piglet/gles2"
+)
+
+// A Shader is a single compiled shader stage
+type Shader struct {
+	name  uint32
+	xtype ShaderType
+}
+
+// Create a new shader of type xtype
+func NewShader(xtype ShaderType) Shader {
+	return Shader{name: gl.CreateShader(uint32(xtype)), xtype: xtype}
+}
+
+// Return the GL name of the shader
+func (s Shader) Name() uint32 { return s.name }
+
+// Return the stage of the shader
+func (s Shader) Type() ShaderType { return s.xtype }
+
+// Replace the source code of the shader
+func (s Shader) SetSource(source string) {
+	src, free := gl.Strs(source + "\x00")
+	defer free()
+	gl.ShaderSource(s.name, 1, src, nil)
+}
+
+// Return the source code of the shader
+func (s Shader) Source() string {
+	var length int32
+	gl.GetShaderiv(s.name, gl.SHADER_SOURCE_LENGTH, &length)
+	if length <= 1 {
+		return ""
+	}
+	source := make([]uint8, length)
+	gl.GetShaderSource(s.name, length, nil, &source[0])
+	return strings.TrimRight(string(source), "\x00")
+}
+
+// Compile the shader source. On failure the error is a *CompileError with
+// the info log of the driver.
+func (s Shader) Compile() error {
+	var status int32
+	gl.CompileShader(s.name)
+	gl.GetShaderiv(s.name, gl.COMPILE_STATUS, &status)
+	if status == gl.FALSE {
+		log := s.InfoLog()
+		return &CompileError{Type: s.xtype, Source: s.Source(), Log: log, Messages: parseInfoLog(log)}
+	}
+	return nil
+}
+
+// Delete the shader
+func (s *Shader) Delete() {
+	if s.name != 0 {
+		gl.DeleteShader(s.name)
+		s.name = 0
+	}
+}
+
+// A Program links a vertex and a fragment shader. Linking caches the
+// active attributes and uniforms, see Attribs and Uniforms.
+type Program struct {
+	name     uint32
+	attribs  map[string]Variable
+	uniforms map[string]Variable
+	elements map[string]Variable // uniform array elements looked up so far
+}
+
+// Create a new, empty program
+func NewProgram() *Program {
+	return &Program{name: gl.CreateProgram()}
+}
+
+// Return the GL name of the program
+func (p *Program) Name() uint32 { return p.name }
+
+// Attach shader to the program, for linking
+func (p *Program) Attach(shader Shader) {
+	gl.AttachShader(p.name, shader.name)
+}
+
+// Detach shader from the program
+func (p *Program) Detach(shader Shader) {
+	gl.DetachShader(p.name, shader.name)
+}
+
+// Bind the attribute called name to location index, before linking
+func (p *Program) BindAttrib(index uint32, name string) {
+	gl.BindAttribLocation(p.name, index, gl.Str(name+"\x00"))
+}
+
+// Link the attached shaders. On failure the error is a *LinkError with the
+// info log of the driver.
+func (p *Program) Link() error {
+	var status int32
+	gl.LinkProgram(p.name)
+	gl.GetProgramiv(p.name, gl.LINK_STATUS, &status)
+	if status == gl.FALSE {
+		return &LinkError{Op: "link", Log: p.InfoLog()}
+	}
+	p.reflect()
+	return nil
+}
+
+// Install the program for rendering
+func (p *Program) Use() {
+	state.UseProgram(p.name)
+}
+
+// Return the location of the attribute called name, or -1 if the program
+// has no such active attribute
+func (p *Program) Attrib(name string) int32 {
+	if v, ok := p.attribs[name]; ok {
+		return v.Location
+	}
+	return gl.GetAttribLocation(p.name, gl.Str(name+"\x00"))
+}
+
+// Return the uniform called name, eg. "camera" or "lights[2]". Setting an
+// inactive uniform is a no-op.
+func (p *Program) Uniform(name string) Uniform {
+	if v, ok := p.uniforms[name]; ok {
+		return Uniform(v.Location)
+	}
+	return Uniform(gl.GetUniformLocation(p.name, gl.Str(name+"\x00")))
+}
+
+// Delete the program
+func (p *Program) Delete() {
+	if p.name != 0 {
+		gl.DeleteProgram(p.name)
+		state.ForgetProgram(p.name)
+		p.name = 0
+	}
+}
+
+// A Uniform is the location of a uniform variable. The setters apply to the
+// program in use, so call Use on the program first.
+type Uniform int32
+
+func (u Uniform) Int(v int32)             { gl.Uniform1i(int32(u), v) }
+func (u Uniform) Float(v float32)         { gl.Uniform1f(int32(u), v) }
+func (u Uniform) Vec2(x, y float32)       { gl.Uniform2f(int32(u), x, y) }
+func (u Uniform) Vec3(x, y, z float32)    { gl.Uniform3f(int32(u), x, y, z) }
+func (u Uniform) Vec4(x, y, z, w float32) { gl.Uniform4f(int32(u), x, y, z, w) }
+func (u Uniform) Mat2(m [4]float32)       { gl.UniformMatrix2fv(int32(u), 1, false, &m[0]) }
+func (u Uniform) Mat3(m [9]float32)       { gl.UniformMatrix3fv(int32(u), 1, false, &m[0]) }
+func (u Uniform) Mat4(m [16]float32)      { gl.UniformMatrix4fv(int32(u), 1, false, &m[0]) }
+
+// Set a float array uniform
+func (u Uniform) Floats(v []float32) {
+	if len(v) > 0 {
+		gl.Uniform1fv(int32(u), int32(len(v)), &v[0])
+	}
+}
+
+// Bind texture to texture unit unit, and point a sampler uniform at that unit
+func (u Uniform) Sampler(texture Texture, unit int) {
+	texture.BindUnit(unit)
+	gl.Uniform1i(int32(u), int32(unit))
+}
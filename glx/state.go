@@ -0,0 +1,16 @@
+package glx
+
+import (
+	"github.com/FEEDFACE-COM/piglet/glstate"
+)
+
+// the cache binds go through, nil to call GL directly
+var state *glstate.Cache
+
+// Route the binds of glx objects, and of packages built on glx, through a
+// state cache that skips redundant calls, or call GL directly again with
+// nil. Invalidate the cache after code that makes GL calls itself.
+func UseStateCache(cache *glstate.Cache) { state = cache }
+
+// Return the cache set with UseStateCache, or nil
+func StateCache() *glstate.Cache { return state }
@@ -0,0 +1,96 @@
+package glx
+
+import (
+	gl "github.com/FEEDFACE-COM/piglet/gles2"
+)
+
+// A Texture holds images for sampling in shaders
+type Texture struct {
+	name   uint32
+	target TextureTarget
+}
+
+// Create a new texture, for binding to target
+func NewTexture(target TextureTarget) Texture {
+	t := Texture{target: target}
+	gl.GenTextures(1, &t.name)
+	return t
+}
+
+// Return the GL name of the texture
+func (t Texture) Name() uint32 { return t.name }
+
+// Return the target the texture binds to
+func (t Texture) Target() TextureTarget { return t.target }
+
+// Bind the texture to its target on the active texture unit
+func (t Texture) Bind() {
+	state.BindTexture(uint32(t.target), t.name)
+}
+
+// Bind the texture to its target on texture unit unit, and leave that unit active
+func (t Texture) BindUnit(unit int) {
+	state.BindTextureUnit(unit, uint32(t.target), t.name)
+}
+
+// Bind the texture and upload the image of mipmap level level, with pixels
+// being a slice of w*h pixels of the given format and type, or nil to
+// allocate storage only
+func (t Texture) SetImage(level int, format Format, w, h int, xtype PixelType, pixels interface{}) {
+	t.setImage(uint32(t.target), level, format, w, h, xtype, pixels)
+}
+
+// Like SetImage, but upload a face of a cube map texture
+func (t Texture) SetFace(face CubeFace, level int, format Format, w, h int, xtype PixelType, pixels interface{}) {
+	t.setImage(uint32(face), level, format, w, h, xtype, pixels)
+}
+
+func (t Texture) setImage(target uint32, level int, format Format, w, h int, xtype PixelType, pixels interface{}) {
+	ptr, _ := dataPtr(pixels)
+	t.Bind()
+	gl.TexImage2D(target, int32(level), int32(format), int32(w), int32(h), 0, uint32(format), uint32(xtype), ptr)
+}
+
+// Bind the texture and upload compressed data of mipmap level level, with
+// format a compressed internal format such as gl.ETC1_RGB8_OES
+func (t Texture) SetCompressedImage(level int, format uint32, w, h int, data []byte) {
+	ptr, size := dataPtr(data)
+	t.Bind()
+	gl.CompressedTexImage2D(uint32(t.target), int32(level), format, int32(w), int32(h), 0, int32(size), ptr)
+}
+
+// Bind the texture and overwrite a w*h region at x,y of mipmap level level
+func (t Texture) SetSubImage(level int, x, y, w, h int, format Format, xtype PixelType, pixels interface{}) {
+	ptr, _ := dataPtr(pixels)
+	t.Bind()
+	gl.TexSubImage2D(uint32(t.target), int32(level), int32(x), int32(y), int32(w), int32(h), uint32(format), uint32(xtype), ptr)
+}
+
+// Bind the texture and set its minification and magnification filters
+func (t Texture) SetFilter(min, mag Filter) {
+	t.Bind()
+	gl.TexParameteri(uint32(t.target), gl.TEXTURE_MIN_FILTER, int32(min))
+	gl.TexParameteri(uint32(t.target), gl.TEXTURE_MAG_FILTER, int32(mag))
+}
+
+// Bind the texture and set its wrap modes along s and t
+func (t Texture) SetWrap(s, tt Wrap) {
+	t.Bind()
+	gl.TexParameteri(uint32(t.target), gl.TEXTURE_WRAP_S, int32(s))
+	gl.TexParameteri(uint32(t.target), gl.TEXTURE_WRAP_T, int32(tt))
+}
+
+// Bind the texture and generate all mipmap levels from level 0
+func (t Texture) GenerateMipmap() {
+	t.Bind()
+	gl.GenerateMipmap(uint32(t.target))
+}
+
+// Delete the texture
+func (t *Texture) Delete() {
+	if t.name != 0 {
+		gl.DeleteTextures(1, &t.name)
+		state.ForgetTexture(t.name)
+		t.name = 0
+	}
+}
@@ -0,0 +1,156 @@
+package glx
+
+import (
+	"fmt"
+	"reflect"
+
+	gl "github.com/FEEDFACE-COM/piglet/gles2"
+)
+
+// A VertexBuffer is an array buffer of vertices in a Layout. It tracks its
+// vertex count and capacity, so dynamic buffers are updated with
+// BufferSubData and only reallocated when they grow.
+type VertexBuffer struct {
+	Buffer
+	Layout   *Layout
+	Usage    Usage
+	Count    int // vertices
+	capacity int // bytes
+}
+
+// Create a vertex buffer of layout, with usage StaticDraw for data set once
+// or DynamicDraw and StreamDraw for data updated every few frames
+func NewVertexBuffer(layout *Layout, usage Usage) *VertexBuffer {
+	return &VertexBuffer{Buffer: NewBuffer(ArrayBuffer), Layout: layout, Usage: usage}
+}
+
+// Check that vertices is a slice of whole vertices, and return their count
+func (vb *VertexBuffer) count(vertices interface{}) (int, error) {
+	_, size := dataPtr(vertices)
+	v := reflect.ValueOf(vertices)
+	if v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Struct && int(v.Type().Elem().Size()) != vb.Layout.Stride {
+		return 0, fmt.Errorf("fail to upload vertices: %s is %d bytes, layout stride is %d", v.Type().Elem(), v.Type().Elem().Size(), vb.Layout.Stride)
+	}
+	if size%vb.Layout.Stride != 0 {
+		return 0, fmt.Errorf("fail to upload vertices: %d bytes are not a multiple of stride %d", size, vb.Layout.Stride)
+	}
+	return size / vb.Layout.Stride, nil
+}
+
+// Replace the vertices with a slice of structs in the layout, or a flat
+// slice like []float32. The data store is reused while it is large enough.
+func (vb *VertexBuffer) SetVertices(vertices interface{}) error {
+	n, err := vb.count(vertices)
+	if err != nil {
+		return err
+	}
+	size := n * vb.Layout.Stride
+	if size > vb.capacity || vb.Usage == StaticDraw {
+		vb.SetData(vertices, vb.Usage)
+		vb.capacity = size
+	} else {
+		vb.SetSubData(0, vertices)
+	}
+	vb.Count = n
+	return nil
+}
+
+// Allocate room for n vertices without uploading any
+func (vb *VertexBuffer) Reserve(n int) {
+	if size := n * vb.Layout.Stride; size > vb.capacity {
+		vb.Alloc(size, vb.Usage)
+		vb.capacity = size
+	}
+}
+
+// Overwrite vertices from vertex first on, within the current capacity
+func (vb *VertexBuffer) UpdateVertices(first int, vertices interface{}) error {
+	n, err := vb.count(vertices)
+	if err != nil {
+		return err
+	}
+	if (first+n)*vb.Layout.Stride > vb.capacity {
+		return fmt.Errorf("fail to update vertices %d to %d: capacity is %d", first, first+n, vb.capacity/vb.Layout.Stride)
+	}
+	vb.SetSubData(first*vb.Layout.Stride, vertices)
+	if first+n > vb.Count {
+		vb.Count = first + n
+	}
+	return nil
+}
+
+// Bind the buffer and point the attributes of p at its vertices
+func (vb *VertexBuffer) Enable(p *Program) {
+	vb.Bind()
+	vb.Layout.Enable(p, 0)
+}
+
+// Draw all vertices with the attributes of p, which must be in use
+func (vb *VertexBuffer) Draw(p *Program, mode Mode) {
+	vb.Enable(p)
+	DrawArrays(mode, 0, vb.Count)
+}
+
+// Index types of element array buffers
+type IndexType uint32
+
+const (
+	IndexUnsignedByte  IndexType = gl.UNSIGNED_BYTE
+	IndexUnsignedShort IndexType = gl.UNSIGNED_SHORT
+	IndexUnsignedInt   IndexType = gl.UNSIGNED_INT // needs GL_OES_element_index_uint
+)
+
+// An IndexBuffer is an element array buffer of vertex indices
+type IndexBuffer struct {
+	Buffer
+	Type     IndexType
+	Usage    Usage
+	Count    int // indices
+	capacity int // bytes
+}
+
+// Create an index buffer
+func NewIndexBuffer(usage Usage) *IndexBuffer {
+	return &IndexBuffer{Buffer: NewBuffer(ElementArrayBuffer), Usage: usage}
+}
+
+// Replace the indices with a []uint8, []uint16 or []uint32
+func (ib *IndexBuffer) SetIndices(indices interface{}) error {
+	switch indices.(type) {
+	case []uint8:
+		ib.Type = IndexUnsignedByte
+	case []uint16:
+		ib.Type = IndexUnsignedShort
+	case []uint32:
+		ib.Type = IndexUnsignedInt
+	default:
+		return fmt.Errorf("fail to upload indices: %T is not []uint8, []uint16 or []uint32", indices)
+	}
+	_, size := dataPtr(indices)
+	if size > ib.capacity || ib.Usage == StaticDraw {
+		ib.SetData(indices, ib.Usage)
+		ib.capacity = size
+	} else {
+		ib.SetSubData(0, indices)
+	}
+	ib.Count = reflect.ValueOf(indices).Len()
+	return nil
+}
+
+// Draw count indices from first on, from the bound array buffer
+func (ib *IndexBuffer) DrawRange(mode Mode, first, count int) {
+	ib.Bind()
+	size := 1
+	switch ib.Type {
+	case IndexUnsignedShort:
+		size = 2
+	case IndexUnsignedInt:
+		size = 4
+	}
+	gl.DrawElements(uint32(mode), int32(count), uint32(ib.Type), gl.PtrOffset(first*size))
+}
+
+// Draw all indices from the bound array buffer
+func (ib *IndexBuffer) Draw(mode Mode) {
+	ib.DrawRange(mode, 0, ib.Count)
+}
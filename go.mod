@@ -1,5 +1,8 @@
 module github.com/FEEDFACE-COM/piglet
 
-go 1.13
+go 1.16
 
-require github.com/go-gl/mathgl v0.0.0-20190713194549-592312d8590a // indirect
+require (
+	github.com/go-gl/mathgl v0.0.0-20190713194549-592312d8590a
+	golang.org/x/image v0.0.0-20190321063152-3fc05d484e9f
+)
@@ -0,0 +1,320 @@
+package input
+
+import (
+	"math"
+	"sync"
+)
+
+// Codes of linux/input-event-codes.h used by gamepads
+const (
+	BTN_MISC       = 0x100
+	BTN_SOUTH      = 0x130
+	BTN_EAST       = 0x131
+	BTN_NORTH      = 0x133
+	BTN_WEST       = 0x134
+	BTN_TL         = 0x136
+	BTN_TR         = 0x137
+	BTN_TL2        = 0x138
+	BTN_TR2        = 0x139
+	BTN_SELECT     = 0x13a
+	BTN_START      = 0x13b
+	BTN_MODE       = 0x13c
+	BTN_THUMBL     = 0x13d
+	BTN_THUMBR     = 0x13e
+	BTN_DPAD_UP    = 0x220
+	BTN_DPAD_DOWN  = 0x221
+	BTN_DPAD_LEFT  = 0x222
+	BTN_DPAD_RIGHT = 0x223
+
+	ABS_Z     = 0x02
+	ABS_RX    = 0x03
+	ABS_RY    = 0x04
+	ABS_RZ    = 0x05
+	ABS_HAT0X = 0x10
+	ABS_HAT3Y = 0x17
+)
+
+// Default dead zones, as a fraction of the range
+const (
+	DefaultDeadZone        = 0.15
+	DefaultTriggerDeadZone = 0.05
+)
+
+// The state of a controller at a Poll
+type ControllerState struct {
+	Connected bool
+	Buttons   [ButtonCount]bool
+	Axes      [AxisCount]float32 // sticks in [-1,1], triggers in [0,1], with dead zones applied
+}
+
+// A Controller is a joystick or gamepad device, mapped to the buttons and axes
+// of an Xbox controller. Its state changes only at Poll, once per frame, so
+// a frame sees one consistent state.
+type Controller struct {
+	Device          *Device
+	Mapping         *Mapping
+	DeadZone        float32 // of the sticks, radial
+	TriggerDeadZone float32
+
+	buttons []uint16 // key codes by SDL button index
+	axes    []uint16 // abs codes by SDL axis index
+
+	mu        sync.Mutex
+	connected bool
+	keys      map[uint16]bool
+	abs       map[uint16]int32
+	pending   []RawEvent // of the current report
+
+	state    ControllerState
+	previous ControllerState
+}
+
+func newController(dev *Device, db *MappingDB) *Controller {
+	c := &Controller{
+		Device:          dev,
+		DeadZone:        DefaultDeadZone,
+		TriggerDeadZone: DefaultTriggerDeadZone,
+		connected:       true,
+		keys:            map[uint16]bool{},
+		abs:             map[uint16]int32{},
+	}
+	// SDL counts buttons from BTN_JOYSTICK up, then from BTN_MISC, and
+	// axes up from 0 without the hats
+	for code := uint16(BTN_JOYSTICK); code < KEY_CNT; code++ {
+		if dev.Has(EV_KEY, code) {
+			c.buttons = append(c.buttons, code)
+		}
+	}
+	for code := uint16(BTN_MISC); code < BTN_JOYSTICK; code++ {
+		if dev.Has(EV_KEY, code) {
+			c.buttons = append(c.buttons, code)
+		}
+	}
+	for code := uint16(0); code < ABS_CNT; code++ {
+		if dev.Has(EV_ABS, code) && (code < ABS_HAT0X || code > ABS_HAT3Y) {
+			c.axes = append(c.axes, code)
+		}
+		if info, ok := dev.Abs(code); ok {
+			c.abs[code] = info.Value
+		}
+	}
+	c.Mapping = db.Lookup(dev)
+	if c.Mapping == nil {
+		c.Mapping = c.defaultMapping()
+	}
+	return c
+}
+
+// Return a mapping following the Linux gamepad conventions of
+// Documentation/input/gamepad.rst
+func (c *Controller) defaultMapping() *Mapping {
+	m := &Mapping{GUID: GUID(c.Device.ID), Name: c.Device.Name}
+	index := func(codes []uint16, code uint16) int {
+		for i, k := range codes {
+			if k == code {
+				return i
+			}
+		}
+		return -1
+	}
+	for _, b := range []struct {
+		button Button
+		code   uint16
+	}{
+		{ButtonA, BTN_SOUTH}, {ButtonB, BTN_EAST}, {ButtonX, BTN_WEST}, {ButtonY, BTN_NORTH},
+		{ButtonBack, BTN_SELECT}, {ButtonGuide, BTN_MODE}, {ButtonStart, BTN_START},
+		{ButtonLeftStick, BTN_THUMBL}, {ButtonRightStick, BTN_THUMBR},
+		{ButtonLeftShoulder, BTN_TL}, {ButtonRightShoulder, BTN_TR},
+		{ButtonDPadUp, BTN_DPAD_UP}, {ButtonDPadDown, BTN_DPAD_DOWN},
+		{ButtonDPadLeft, BTN_DPAD_LEFT}, {ButtonDPadRight, BTN_DPAD_RIGHT},
+	} {
+		if i := index(c.buttons, b.code); i >= 0 {
+			m.binds = append(m.binds, binding{src: source{kind: 'b', index: i}, button: b.button, axis: -1})
+		}
+	}
+	for _, a := range []struct {
+		axis Axis
+		code uint16
+	}{
+		{AxisLeftX, ABS_X}, {AxisLeftY, ABS_Y}, {AxisRightX, ABS_RX}, {AxisRightY, ABS_RY},
+		{AxisTriggerLeft, ABS_Z}, {AxisTriggerRight, ABS_RZ},
+	} {
+		if i := index(c.axes, a.code); i >= 0 {
+			m.binds = append(m.binds, binding{src: source{kind: 'a', index: i}, button: -1, axis: a.axis})
+		}
+	}
+	for _, t := range []struct {
+		axis Axis
+		code uint16
+	}{{AxisTriggerLeft, BTN_TL2}, {AxisTriggerRight, BTN_TR2}} {
+		if i := index(c.buttons, t.code); i >= 0 && index(c.axes, ABS_Z) < 0 {
+			m.binds = append(m.binds, binding{src: source{kind: 'b', index: i}, button: -1, axis: t.axis})
+		}
+	}
+	if c.Device.Has(EV_ABS, ABS_HAT0X) && index(c.buttons, BTN_DPAD_UP) < 0 {
+		for _, d := range []struct {
+			button Button
+			hat    int
+		}{{ButtonDPadUp, 1}, {ButtonDPadRight, 2}, {ButtonDPadDown, 4}, {ButtonDPadLeft, 8}} {
+			m.binds = append(m.binds, binding{src: source{kind: 'h', hat: d.hat}, button: d.button, axis: -1})
+		}
+	}
+	return m
+}
+
+// Record raw events, applied together at the end of the report
+func (c *Controller) decode(ev RawEvent) {
+	switch ev.Type {
+	case EV_KEY, EV_ABS:
+		c.pending = append(c.pending, ev)
+	case EV_SYN:
+		if ev.Code != SYN_REPORT {
+			return
+		}
+		c.mu.Lock()
+		for _, p := range c.pending {
+			if p.Type == EV_KEY {
+				c.keys[p.Code] = p.Value != 0
+			} else {
+				c.abs[p.Code] = p.Value
+			}
+		}
+		c.mu.Unlock()
+		c.pending = c.pending[:0]
+	}
+}
+
+func (c *Controller) disconnect() {
+	c.mu.Lock()
+	c.connected = false
+	c.keys = map[uint16]bool{}
+	c.mu.Unlock()
+}
+
+// Update the state from the device, and return it. Call once per frame.
+func (c *Controller) Poll() ControllerState {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.previous = c.state
+	c.state = ControllerState{Connected: c.connected}
+	if !c.connected {
+		return c.state
+	}
+	for _, b := range c.Mapping.binds {
+		v := c.value(b.src)
+		if b.button >= 0 {
+			c.state.Buttons[b.button] = c.state.Buttons[b.button] || v > 0.5
+			continue
+		}
+		switch {
+		case b.half != 0:
+			v *= float32(b.half) // a button or half axis driving half an axis
+		case b.axis >= AxisTriggerLeft && b.src.kind == 'a' && b.src.half == 0:
+			v = (v + 1) / 2 // a full axis driving a trigger
+		}
+		c.state.Axes[b.axis] += v
+	}
+	for i := range c.state.Axes {
+		c.state.Axes[i] = clamp(c.state.Axes[i], -1, 1)
+	}
+	c.deadZone(AxisLeftX, AxisLeftY)
+	c.deadZone(AxisRightX, AxisRightY)
+	for _, a := range []Axis{AxisTriggerLeft, AxisTriggerRight} {
+		c.state.Axes[a] = rescale(clamp(c.state.Axes[a], 0, 1), c.TriggerDeadZone)
+	}
+	return c.state
+}
+
+// Return the value of a source, 0 or 1 for buttons and hats, in [-1,1] for
+// axes or [0,1] for half axes
+func (c *Controller) value(src source) float32 {
+	switch src.kind {
+	case 'b':
+		if src.index < len(c.buttons) && c.keys[c.buttons[src.index]] {
+			return 1
+		}
+	case 'h':
+		x, y := c.abs[uint16(ABS_HAT0X+2*src.index)], c.abs[uint16(ABS_HAT0X+2*src.index+1)]
+		bits := 0
+		if y < 0 {
+			bits |= 1
+		} else if y > 0 {
+			bits |= 4
+		}
+		if x > 0 {
+			bits |= 2
+		} else if x < 0 {
+			bits |= 8
+		}
+		if bits&src.hat != 0 {
+			return 1
+		}
+	case 'a':
+		if src.index >= len(c.axes) {
+			return 0
+		}
+		code := c.axes[src.index]
+		info, _ := c.Device.Abs(code)
+		v := info.Normalize(c.abs[code])*2 - 1
+		if src.invert {
+			v = -v
+		}
+		switch src.half {
+		case 1:
+			return clamp(v, 0, 1)
+		case -1:
+			return clamp(-v, 0, 1)
+		}
+		return v
+	}
+	return 0
+}
+
+// Apply a radial dead zone to a stick, rescaling the rest to the full range
+func (c *Controller) deadZone(x, y Axis) {
+	vx, vy := c.state.Axes[x], c.state.Axes[y]
+	length := float32(math.Hypot(float64(vx), float64(vy)))
+	if length == 0 {
+		return
+	}
+	scale := rescale(clamp(length, 0, 1), c.DeadZone) / length
+	c.state.Axes[x], c.state.Axes[y] = clamp(vx*scale, -1, 1), clamp(vy*scale, -1, 1)
+}
+
+// Return v in [0,1] with [0,zone] mapped to 0 and the rest stretched to [0,1]
+func rescale(v, zone float32) float32 {
+	if v <= zone {
+		return 0
+	}
+	return (v - zone) / (1 - zone)
+}
+
+func clamp(v, min, max float32) float32 {
+	if v < min {
+		return min
+	} else if v > max {
+		return max
+	}
+	return v
+}
+
+// Return the state of the last Poll
+func (c *Controller) State() ControllerState {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.state
+}
+
+// Report whether a button went down between the last two polls
+func (c *Controller) Pressed(b Button) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.state.Buttons[b] && !c.previous.Buttons[b]
+}
+
+// Report whether a button went up between the last two polls
+func (c *Controller) Released(b Button) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return !c.state.Buttons[b] && c.previous.Buttons[b]
+}
@@ -0,0 +1,369 @@
+package input
+
+// Rotations of a touchscreen relative to the display, clockwise, as set
+// with lcd_rotate or display_rotate in config.txt
+type Rotation int
+
+const (
+	Rotate0   Rotation = 0
+	Rotate90  Rotation = 90
+	Rotate180 Rotation = 180
+	Rotate270 Rotation = 270
+)
+
+// A Display is the area touch and pointer positions are mapped to, usually
+// the size returned by piglet.GetDisplaySize
+type Display struct {
+	Width, Height int
+	Rotation      Rotation
+}
+
+// Return the position in display pixels of a touch at u,v in [0,1] across
+// the panel. With a zero size, the position stays in [0,1].
+func (d Display) Map(u, v float32) (float32, float32) {
+	switch d.Rotation {
+	case Rotate90:
+		u, v = 1-v, u
+	case Rotate180:
+		u, v = 1-u, 1-v
+	case Rotate270:
+		u, v = v, 1-u
+	}
+	if d.Width == 0 || d.Height == 0 {
+		return u, v
+	}
+	return u * float32(d.Width-1), v * float32(d.Height-1)
+}
+
+// contacts tracked without ABS_MT_SLOT
+const defaultSlots = 10
+
+type contact struct {
+	id         int32 // tracking id, -1 if not touching
+	last       int   // tracking id of the last touch, reported with its up
+	x, y       int32
+	lx, ly     int32 // position reported last, where a taken over touch ends
+	was, moved bool
+}
+
+// A decoder turns the raw events of a device into Events, one report at a
+// time, as delimited by SYN_REPORT
+type decoder struct {
+	dev        *Device
+	in         *Input
+	down       map[Key]bool
+	mods       Modifiers
+	pending    []Event // key and button events of the current report
+	dx, dy     int
+	wx, wy     int
+	mt         bool
+	slot       int
+	slots      []contact
+	xinfo      AbsInfo
+	yinfo      AbsInfo
+	dropped    bool
+	controller *Controller
+}
+
+func newDecoder(dev *Device, in *Input) *decoder {
+	d := &decoder{dev: dev, in: in, down: map[Key]bool{}, mods: ModNumLock}
+	if dev.Capabilities&Gamepad != 0 {
+		d.controller = newController(dev, in.mappings)
+	}
+	slots := defaultSlots
+	if info, ok := dev.Abs(ABS_MT_POSITION_X); ok {
+		d.mt = true
+		d.xinfo = info
+		d.yinfo, _ = dev.Abs(ABS_MT_POSITION_Y)
+		if info, ok := dev.Abs(ABS_MT_SLOT); ok && info.Maximum >= 0 {
+			slots = int(info.Maximum) + 1
+		}
+	} else {
+		d.xinfo, _ = dev.Abs(ABS_X)
+		d.yinfo, _ = dev.Abs(ABS_Y)
+		slots = 1
+	}
+	d.slots = make([]contact, slots)
+	for i := range d.slots {
+		d.slots[i].id = -1
+	}
+	return d
+}
+
+// Decode raw events, calling emit for every event of each completed report
+func (d *decoder) decode(events []RawEvent, emit func(Event)) {
+	for _, ev := range events {
+		if d.dropped {
+			// the kernel buffer overran: skip to the next report, and
+			// read the state whose changes were lost
+			if ev.Type == EV_SYN && ev.Code == SYN_REPORT {
+				d.dropped = false
+				d.resync(ev, emit)
+			}
+			continue
+		}
+		if d.controller != nil {
+			d.controller.decode(ev)
+			if ev.Type == EV_ABS || ev.Type == EV_KEY && ev.Code >= BTN_MISC {
+				continue
+			}
+		}
+		switch ev.Type {
+		case EV_SYN:
+			switch ev.Code {
+			case SYN_REPORT:
+				d.flush(ev, emit)
+			case SYN_DROPPED:
+				d.dropped = true
+				d.pending = d.pending[:0]
+			}
+		case EV_KEY:
+			d.key(ev)
+		case EV_REL:
+			switch ev.Code {
+			case REL_X:
+				d.dx += int(ev.Value)
+			case REL_Y:
+				d.dy += int(ev.Value)
+			case REL_WHEEL:
+				d.wy += int(ev.Value)
+			case REL_HWHEEL:
+				d.wx += int(ev.Value)
+			}
+		case EV_ABS:
+			d.abs(ev)
+		}
+	}
+}
+
+func (d *decoder) key(ev RawEvent) {
+	key := Key(ev.Code)
+	origin := Origin{Device: d.dev, Time: ev.Time}
+	switch {
+	case key == BtnTouch:
+		if !d.mt {
+			c := &d.slots[0]
+			if ev.Value != 0 {
+				c.id = 0
+			} else {
+				c.id = -1
+			}
+		}
+	case key >= BtnLeft && key <= BtnExtra:
+		d.pending = append(d.pending, &MouseButtonEvent{Origin: origin, Button: key, Pressed: ev.Value != 0})
+	case key < 0x100:
+		action := KeyAction(ev.Value)
+		d.modifier(key, action)
+		e := &KeyEvent{Origin: origin, Key: key, Action: action, Mods: d.mods}
+		if action != KeyRelease {
+			e.Rune = d.in.keymap.Rune(key, d.mods)
+		}
+		d.pending = append(d.pending, e)
+	}
+}
+
+var modifierKeys = map[Key]Modifiers{
+	KeyLeftShift: ModShift, KeyRightShift: ModShift,
+	KeyLeftCtrl: ModCtrl, KeyRightCtrl: ModCtrl,
+	KeyLeftAlt: ModAlt, KeyRightAlt: ModAltGr,
+	KeyLeftMeta: ModMeta, KeyRightMeta: ModMeta,
+}
+
+func (d *decoder) modifier(key Key, action KeyAction) {
+	switch key {
+	case KeyCapsLock:
+		if action == KeyPress {
+			d.mods ^= ModCapsLock
+		}
+		return
+	case KeyNumLock:
+		if action == KeyPress {
+			d.mods ^= ModNumLock
+		}
+		return
+	}
+	mod, ok := modifierKeys[key]
+	if !ok {
+		return
+	}
+	d.down[key] = action != KeyRelease
+	d.mods &^= mod
+	for k, m := range modifierKeys {
+		if m == mod && d.down[k] {
+			d.mods |= mod
+		}
+	}
+}
+
+func (d *decoder) abs(ev RawEvent) {
+	if d.mt {
+		switch ev.Code {
+		case ABS_MT_SLOT:
+			d.slot = int(ev.Value)
+		case ABS_MT_TRACKING_ID:
+			if c := d.contact(); c != nil {
+				c.id = ev.Value
+			}
+		case ABS_MT_POSITION_X:
+			if c := d.contact(); c != nil {
+				c.x, c.moved = ev.Value, true
+			}
+		case ABS_MT_POSITION_Y:
+			if c := d.contact(); c != nil {
+				c.y, c.moved = ev.Value, true
+			}
+		}
+		return
+	}
+	switch ev.Code {
+	case ABS_X:
+		d.slots[0].x, d.slots[0].moved = ev.Value, true
+	case ABS_Y:
+		d.slots[0].y, d.slots[0].moved = ev.Value, true
+	}
+}
+
+func (d *decoder) contact() *contact {
+	if d.slot < 0 || d.slot >= len(d.slots) {
+		return nil
+	}
+	return &d.slots[d.slot]
+}
+
+// Emit the events of a completed report
+func (d *decoder) flush(syn RawEvent, emit func(Event)) {
+	origin := Origin{Device: d.dev, Time: syn.Time}
+	if d.dx != 0 || d.dy != 0 {
+		x, y := d.in.movePointer(d.dx, d.dy)
+		emit(&MouseMoveEvent{Origin: origin, DX: d.dx, DY: d.dy, X: x, Y: y})
+		d.dx, d.dy = 0, 0
+	}
+	for _, e := range d.pending {
+		if b, ok := e.(*MouseButtonEvent); ok {
+			b.X, b.Y = d.in.Pointer()
+		}
+		emit(e)
+	}
+	d.pending = d.pending[:0]
+	if d.wx != 0 || d.wy != 0 {
+		emit(&WheelEvent{Origin: origin, DX: d.wx, DY: d.wy})
+		d.wx, d.wy = 0, 0
+	}
+	display := d.in.Display()
+	for i := range d.slots {
+		c := &d.slots[i]
+		touching := c.id >= 0
+		if touching && c.was && int(c.id) != c.last {
+			// a new contact took over the slot within one report
+			x, y := display.Map(d.xinfo.Normalize(c.lx), d.yinfo.Normalize(c.ly))
+			emit(&TouchEvent{Origin: origin, ID: c.last, Action: TouchUp, X: x, Y: y})
+			c.was = false
+		}
+		var action TouchAction
+		switch {
+		case touching && !c.was:
+			action = TouchDown
+		case !touching && c.was:
+			action = TouchUp
+		case touching && c.moved:
+			action = TouchMove
+		default:
+			c.moved = false
+			continue
+		}
+		if touching {
+			c.last = int(c.id)
+		}
+		x, y := display.Map(d.xinfo.Normalize(c.x), d.yinfo.Normalize(c.y))
+		emit(&TouchEvent{Origin: origin, ID: c.last, Action: action, X: x, Y: y})
+		c.was, c.moved = touching, false
+		c.lx, c.ly = c.x, c.y
+	}
+}
+
+// Start over after dropped events, from the keys and contacts read from
+// the device, reporting the contacts that changed. Replayed devices, and
+// devices failing to tell, are reset instead.
+func (d *decoder) resync(syn RawEvent, emit func(Event)) {
+	if err := d.readState(); err != nil {
+		d.reset(syn, emit)
+		return
+	}
+	d.pending = d.pending[:0]
+	d.dx, d.dy, d.wx, d.wy = 0, 0, 0, 0
+	d.flush(syn, emit)
+}
+
+// Read the modifiers held and the contacts touching from the device, as
+// EVIOCGKEY, EVIOCGABS and EVIOCGMTSLOTS
+func (d *decoder) readState() error {
+	keys, err := d.dev.keyState()
+	if err != nil {
+		return err
+	}
+	d.mods &^= ModShift | ModCtrl | ModAlt | ModAltGr | ModMeta
+	for k, m := range modifierKeys {
+		d.down[k] = hasBit(keys, uint16(k))
+		if d.down[k] {
+			d.mods |= m
+		}
+	}
+	if !d.mt {
+		if !d.dev.Has(EV_ABS, ABS_X) {
+			return nil
+		}
+		c := &d.slots[0]
+		x, err := d.dev.absValue(ABS_X)
+		if err != nil {
+			return err
+		}
+		y, err := d.dev.absValue(ABS_Y)
+		if err != nil {
+			return err
+		}
+		c.id = -1
+		if hasBit(keys, uint16(BtnTouch)) {
+			c.id = 0
+		}
+		if x != c.x || y != c.y {
+			c.x, c.y, c.moved = x, y, true
+		}
+		return nil
+	}
+	ids, err := d.dev.mtSlots(ABS_MT_TRACKING_ID, len(d.slots))
+	if err != nil {
+		return err
+	}
+	xs, err := d.dev.mtSlots(ABS_MT_POSITION_X, len(d.slots))
+	if err != nil {
+		return err
+	}
+	ys, err := d.dev.mtSlots(ABS_MT_POSITION_Y, len(d.slots))
+	if err != nil {
+		return err
+	}
+	slot, err := d.dev.absValue(ABS_MT_SLOT)
+	if err != nil {
+		return err
+	}
+	for i := range d.slots {
+		c := &d.slots[i]
+		c.id = ids[i]
+		if xs[i] != c.x || ys[i] != c.y {
+			c.x, c.y, c.moved = xs[i], ys[i], true
+		}
+	}
+	d.slot = int(slot)
+	return nil
+}
+
+// Start over after the device failed: keep the pointer and modifiers, and
+// end all contacts
+func (d *decoder) reset(syn RawEvent, emit func(Event)) {
+	d.pending = d.pending[:0]
+	d.dx, d.dy, d.wx, d.wy = 0, 0, 0, 0
+	for i := range d.slots {
+		d.slots[i].id = -1
+	}
+	d.flush(syn, emit)
+}
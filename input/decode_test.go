@@ -0,0 +1,167 @@
+package input
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func ev(typ, code uint16, value int32) RawEvent {
+	return RawEvent{Type: typ, Code: code, Value: value}
+}
+
+var syn = ev(EV_SYN, SYN_REPORT, 0)
+
+func key(k Key, value int32) RawEvent { return ev(EV_KEY, uint16(k), value) }
+
+var (
+	keyboard    = Recording{Name: "keyboard", Capabilities: Keyboard}
+	mouse       = Recording{Name: "mouse", Capabilities: Mouse}
+	touchscreen = Recording{Name: "touchscreen", Capabilities: Touchscreen, Abs: map[uint16]AbsInfo{
+		ABS_MT_SLOT:        {Maximum: 1},
+		ABS_MT_TRACKING_ID: {Maximum: 65535},
+		ABS_MT_POSITION_X:  {Maximum: 99},
+		ABS_MT_POSITION_Y:  {Maximum: 99},
+	}}
+)
+
+// Replay events recorded from a device through its decoder, as Input does
+func replay(t *testing.T, rec Recording, display Display, events []RawEvent) []string {
+	var buf bytes.Buffer
+	if err := WriteEvents(&buf, events...); err != nil {
+		t.Fatal(err)
+	}
+	dev := Replay(&buf, rec)
+	in := &Input{keymap: KeymapUS}
+	in.SetDisplay(display)
+	d := newDecoder(dev, in)
+	var ret []string
+	raw := make([]RawEvent, 4) // fewer than a test sends, to decode across reads
+	for {
+		n, err := dev.ReadEvents(raw)
+		d.decode(raw[:n], func(e Event) { ret = append(ret, describe(e)) })
+		if err == io.EOF {
+			return ret
+		} else if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// Return the string of e, with the modifiers of key events but NumLock
+func describe(e Event) string {
+	ret := fmt.Sprint(e)
+	if k, ok := e.(*KeyEvent); ok {
+		for _, mod := range []struct {
+			mod  Modifiers
+			name string
+		}{{ModShift, "shift"}, {ModCtrl, "ctrl"}, {ModAlt, "alt"}, {ModCapsLock, "capslock"}} {
+			if k.Mods&mod.mod != 0 {
+				ret += " " + mod.name
+			}
+		}
+	}
+	return ret
+}
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		name    string
+		rec     Recording
+		display Display
+		events  []RawEvent
+		want    []string
+	}{
+		{"key repeat and modifiers", keyboard, Display{}, []RawEvent{
+			key(KeyLeftShift, 1), syn,
+			key(KeyA, 1), syn,
+			key(KeyA, 2), syn,
+			key(KeyA, 0), syn,
+			key(KeyLeftShift, 0), syn,
+			key(KeyCapsLock, 1), syn,
+			key(KeyCapsLock, 0), syn,
+			key(KeyA, 1), key(KeyA, 0), syn,
+		}, []string{
+			"key LEFTSHIFT press shift",
+			`key A press 'A' shift`,
+			`key A repeat 'A' shift`,
+			"key A release shift",
+			"key LEFTSHIFT release",
+			"key CAPSLOCK press capslock",
+			"key CAPSLOCK release capslock",
+			`key A press 'A' capslock`,
+			"key A release capslock",
+		}},
+		{"multitouch slot takeover", touchscreen, Display{Width: 100, Height: 100}, []RawEvent{
+			ev(EV_ABS, ABS_MT_SLOT, 0), ev(EV_ABS, ABS_MT_TRACKING_ID, 5), ev(EV_ABS, ABS_MT_POSITION_X, 10), ev(EV_ABS, ABS_MT_POSITION_Y, 20), syn,
+			// contact 5 lifts and 6 lands in the same slot within one report
+			ev(EV_ABS, ABS_MT_TRACKING_ID, 6), ev(EV_ABS, ABS_MT_POSITION_X, 30), ev(EV_ABS, ABS_MT_POSITION_Y, 40),
+			ev(EV_ABS, ABS_MT_SLOT, 1), ev(EV_ABS, ABS_MT_TRACKING_ID, 7), ev(EV_ABS, ABS_MT_POSITION_X, 50), ev(EV_ABS, ABS_MT_POSITION_Y, 60), syn,
+			ev(EV_ABS, ABS_MT_SLOT, 0), ev(EV_ABS, ABS_MT_POSITION_X, 35), syn,
+			ev(EV_ABS, ABS_MT_TRACKING_ID, -1), ev(EV_ABS, ABS_MT_SLOT, 1), ev(EV_ABS, ABS_MT_TRACKING_ID, -1), syn,
+		}, []string{
+			"touch 5 down at 10,20",
+			"touch 5 up at 10,20",
+			"touch 6 down at 30,40",
+			"touch 7 down at 50,60",
+			"touch 6 move at 35,40",
+			"touch 6 up at 35,40",
+			"touch 7 up at 50,60",
+		}},
+		{"SYN_DROPPED reset", touchscreen, Display{Width: 100, Height: 100}, []RawEvent{
+			ev(EV_ABS, ABS_MT_TRACKING_ID, 1), ev(EV_ABS, ABS_MT_POSITION_X, 10), ev(EV_ABS, ABS_MT_POSITION_Y, 10), syn,
+			// the press and the motion of the overrun report are lost
+			key(KeyA, 1), ev(EV_SYN, SYN_DROPPED, 0), ev(EV_ABS, ABS_MT_POSITION_X, 50), syn,
+			ev(EV_ABS, ABS_MT_TRACKING_ID, 2), ev(EV_ABS, ABS_MT_POSITION_X, 20), ev(EV_ABS, ABS_MT_POSITION_Y, 20), syn,
+		}, []string{
+			"touch 1 down at 10,10",
+			"touch 1 up at 10,10",
+			"touch 2 down at 20,20",
+		}},
+		{"REL motion clamped to the display", mouse, Display{Width: 100, Height: 50}, []RawEvent{
+			ev(EV_REL, REL_X, 80), ev(EV_REL, REL_Y, -10), syn,
+			ev(EV_REL, REL_X, -500), key(BtnLeft, 1), syn,
+			ev(EV_REL, REL_Y, 100), ev(EV_REL, REL_WHEEL, -1), syn,
+			key(BtnLeft, 0), syn,
+		}, []string{
+			"mouse move +80,-10 to 99,15",
+			"mouse move -500,+0 to 0,15",
+			"mouse BTN_LEFT press at 0,15",
+			"mouse move +0,+100 to 0,49",
+			"wheel +0,-1",
+			"mouse BTN_LEFT release at 0,49",
+		}},
+	}
+	for _, test := range tests {
+		got := replay(t, test.rec, test.display, test.events)
+		if strings.Join(got, "\n") != strings.Join(test.want, "\n") {
+			t.Errorf("%s: got\n\t%s\nwant\n\t%s", test.name, strings.Join(got, "\n\t"), strings.Join(test.want, "\n\t"))
+		}
+	}
+}
+
+// Events recorded on 32 and 64 bit machines read the same
+func TestEventReader(t *testing.T) {
+	want := RawEvent{Time: time.Unix(1, 500000000), Type: EV_KEY, Code: uint16(KeyA), Value: 2}
+	tests := []struct {
+		wordSize int
+		data     []byte
+	}{
+		{4, []byte{1, 0, 0, 0, 0x20, 0xa1, 0x07, 0, 1, 0, 30, 0, 2, 0, 0, 0}},
+		{8, []byte{1, 0, 0, 0, 0, 0, 0, 0, 0x20, 0xa1, 0x07, 0, 0, 0, 0, 0, 1, 0, 30, 0, 2, 0, 0, 0}},
+	}
+	for _, test := range tests {
+		events := make([]RawEvent, 2)
+		n, err := NewEventReader(bytes.NewReader(test.data), test.wordSize).Read(events)
+		if err != nil || n != 1 {
+			t.Errorf("word size %d: read %d events: %v", test.wordSize, n, err)
+			continue
+		}
+		if got := events[0]; !got.Time.Equal(want.Time) || got.Type != want.Type || got.Code != want.Code || got.Value != want.Value {
+			t.Errorf("word size %d: read %+v, want %+v", test.wordSize, got, want)
+		}
+	}
+}
@@ -0,0 +1,491 @@
+package input
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+// Event types and codes of linux/input-event-codes.h
+const (
+	EV_SYN = 0x00
+	EV_KEY = 0x01
+	EV_REL = 0x02
+	EV_ABS = 0x03
+	EV_MSC = 0x04
+	EV_LED = 0x11
+	EV_CNT = 0x20
+
+	SYN_REPORT  = 0
+	SYN_DROPPED = 3
+
+	REL_X      = 0x00
+	REL_Y      = 0x01
+	REL_HWHEEL = 0x06
+	REL_WHEEL  = 0x08
+	REL_CNT    = 0x10
+
+	ABS_X              = 0x00
+	ABS_Y              = 0x01
+	ABS_MT_SLOT        = 0x2f
+	ABS_MT_POSITION_X  = 0x35
+	ABS_MT_POSITION_Y  = 0x36
+	ABS_MT_TRACKING_ID = 0x39
+	ABS_CNT            = 0x40
+
+	KEY_CNT = 0x300
+
+	BTN_JOYSTICK = 0x120
+	BTN_GAMEPAD  = 0x130
+
+	INPUT_PROP_POINTER = 0x00
+	INPUT_PROP_DIRECT  = 0x01
+	INPUT_PROP_CNT     = 0x20
+)
+
+// A RawEvent is a struct input_event as read from an evdev device
+type RawEvent struct {
+	Time  time.Time
+	Type  uint16
+	Code  uint16
+	Value int32
+}
+
+// size of a C long, the fields of the struct timeval heading input_event
+const nativeWordSize = 4 << (^uint(0) >> 63)
+
+// An EventReader reads raw events, as written by the kernel or recorded with
+// cat /dev/input/eventN > file
+type EventReader struct {
+	r        io.Reader
+	wordSize int
+	buf      []byte
+}
+
+// Return a reader of events from r. wordSize is the size of a C long on the
+// machine that wrote them, 4 on 32 bit Raspberry Pi OS and 8 on 64 bit, or 0
+// for this machine.
+func NewEventReader(r io.Reader, wordSize int) *EventReader {
+	if wordSize == 0 {
+		wordSize = nativeWordSize
+	}
+	return &EventReader{r: r, wordSize: wordSize}
+}
+
+// Read whole events into events, blocking until at least one is read
+func (er *EventReader) Read(events []RawEvent) (int, error) {
+	size := 2*er.wordSize + 8
+	if len(er.buf) < len(events)*size {
+		er.buf = make([]byte, len(events)*size)
+	}
+	buf := er.buf[:len(events)*size]
+	n, err := io.ReadAtLeast(er.r, buf, size)
+	if rem := n % size; rem != 0 && err == nil {
+		var m int
+		m, err = io.ReadFull(er.r, buf[n:n+size-rem])
+		n += m
+	}
+	count := n / size
+	for i := 0; i < count; i++ {
+		b := buf[i*size:]
+		var sec, usec int64
+		if er.wordSize == 8 {
+			sec, usec = int64(binary.LittleEndian.Uint64(b)), int64(binary.LittleEndian.Uint64(b[8:]))
+		} else {
+			sec, usec = int64(int32(binary.LittleEndian.Uint32(b))), int64(int32(binary.LittleEndian.Uint32(b[4:])))
+		}
+		b = b[2*er.wordSize:]
+		events[i] = RawEvent{
+			Time:  time.Unix(sec, usec*1000),
+			Type:  binary.LittleEndian.Uint16(b),
+			Code:  binary.LittleEndian.Uint16(b[2:]),
+			Value: int32(binary.LittleEndian.Uint32(b[4:])),
+		}
+	}
+	if count > 0 {
+		return count, nil
+	}
+	return 0, err
+}
+
+// Write events in the layout of this machine, eg. to record them
+func WriteEvents(w io.Writer, events ...RawEvent) error {
+	size := 2*nativeWordSize + 8
+	buf := make([]byte, len(events)*size)
+	for i, ev := range events {
+		b := buf[i*size:]
+		if !ev.Time.IsZero() {
+			sec, usec := ev.Time.Unix(), int64(ev.Time.Nanosecond()/1000)
+			if nativeWordSize == 8 {
+				binary.LittleEndian.PutUint64(b, uint64(sec))
+				binary.LittleEndian.PutUint64(b[8:], uint64(usec))
+			} else {
+				binary.LittleEndian.PutUint32(b, uint32(sec))
+				binary.LittleEndian.PutUint32(b[4:], uint32(usec))
+			}
+		}
+		b = b[2*nativeWordSize:]
+		binary.LittleEndian.PutUint16(b, ev.Type)
+		binary.LittleEndian.PutUint16(b[2:], ev.Code)
+		binary.LittleEndian.PutUint32(b[4:], uint32(ev.Value))
+	}
+	_, err := w.Write(buf)
+	return err
+}
+
+// An AbsInfo is the range of an absolute axis, as struct input_absinfo
+type AbsInfo struct {
+	Value      int32 `json:"value"`
+	Minimum    int32 `json:"min"`
+	Maximum    int32 `json:"max"`
+	Fuzz       int32 `json:"fuzz,omitempty"`
+	Flat       int32 `json:"flat,omitempty"`
+	Resolution int32 `json:"resolution,omitempty"`
+}
+
+// Return v relative to the range, in [0,1]
+func (a AbsInfo) Normalize(v int32) float32 {
+	if a.Maximum <= a.Minimum {
+		return 0
+	}
+	ret := float32(v-a.Minimum) / float32(a.Maximum-a.Minimum)
+	if ret < 0 {
+		return 0
+	} else if ret > 1 {
+		return 1
+	}
+	return ret
+}
+
+// The bus and ids of a device, as struct input_id
+type ID struct {
+	Bus     uint16 `json:"bus"`
+	Vendor  uint16 `json:"vendor"`
+	Product uint16 `json:"product"`
+	Version uint16 `json:"version"`
+}
+
+// Kinds of devices, by the events they report
+type Capability uint32
+
+const (
+	Keyboard Capability = 1 << iota
+	Mouse
+	Touchscreen
+	Gamepad
+)
+
+func (c Capability) String() string {
+	ret := ""
+	for _, n := range []struct {
+		c    Capability
+		name string
+	}{{Keyboard, "keyboard"}, {Mouse, "mouse"}, {Touchscreen, "touchscreen"}, {Gamepad, "gamepad"}} {
+		if c&n.c != 0 {
+			if ret != "" {
+				ret += "|"
+			}
+			ret += n.name
+		}
+	}
+	if ret == "" {
+		return "none"
+	}
+	return ret
+}
+
+// A Device is an evdev device, or a recording of one
+type Device struct {
+	Path         string
+	Name         string
+	ID           ID
+	Capabilities Capability
+
+	bits   [EV_CNT][]byte
+	props  []byte
+	abs    map[uint16]AbsInfo
+	file   *os.File
+	reader *EventReader
+	closer io.Closer
+}
+
+// Open the evdev device at path, eg. /dev/input/event0, and identify it
+func OpenDevice(path string) (*Device, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("fail to open input device: %w", err)
+	}
+	d := &Device{Path: path, file: f, reader: NewEventReader(f, 0), closer: f, abs: map[uint16]AbsInfo{}}
+	if err := d.identify(); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("fail to identify input device %s: %v", path, err)
+	}
+	return d, nil
+}
+
+// ioctl request numbers, as the _IOC macros of asm-generic/ioctl.h
+func ioc(dir, typ, nr, size uintptr) uintptr {
+	return dir<<30 | size<<16 | typ<<8 | nr
+}
+
+const (
+	iocNone  = 0
+	iocWrite = 1
+	iocRead  = 2
+)
+
+// Run call on the descriptor of f. Going through SyscallConn instead of
+// Fd keeps f non-blocking, so Close interrupts a pending Read.
+func control(f *os.File, call func(fd uintptr) syscall.Errno) error {
+	conn, err := f.SyscallConn()
+	if err != nil {
+		return err
+	}
+	var errno syscall.Errno
+	if err := conn.Control(func(fd uintptr) {
+		errno = call(fd)
+	}); err != nil {
+		return err
+	}
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+// Call ioctl on f with a pointer argument. The pointer only becomes a
+// uintptr within the Syscall expression, so what it points to stays put
+// and alive until the call returns.
+func ioctl(f *os.File, req uintptr, arg unsafe.Pointer) error {
+	err := control(f, func(fd uintptr) syscall.Errno {
+		_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, req, uintptr(arg))
+		return errno
+	})
+	runtime.KeepAlive(arg)
+	return err
+}
+
+// Call ioctl on f with an integer argument
+func ioctlValue(f *os.File, req, value uintptr) error {
+	return control(f, func(fd uintptr) syscall.Errno {
+		_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, req, value)
+		return errno
+	})
+}
+
+func (d *Device) identify() error {
+	name := make([]byte, 256)
+	if err := ioctl(d.file, ioc(iocRead, 'E', 0x06, uintptr(len(name))), unsafe.Pointer(&name[0])); err != nil {
+		return err
+	}
+	for i, c := range name {
+		if c == 0 {
+			name = name[:i]
+			break
+		}
+	}
+	d.Name = string(name)
+	if err := ioctl(d.file, ioc(iocRead, 'E', 0x02, unsafe.Sizeof(d.ID)), unsafe.Pointer(&d.ID)); err != nil {
+		return err
+	}
+	sizes := map[int]int{0: EV_CNT, EV_KEY: KEY_CNT, EV_REL: REL_CNT, EV_ABS: ABS_CNT}
+	for ev, count := range sizes {
+		d.bits[ev] = make([]byte, (count+7)/8)
+		if err := ioctl(d.file, ioc(iocRead, 'E', uintptr(0x20+ev), uintptr(len(d.bits[ev]))), unsafe.Pointer(&d.bits[ev][0])); err != nil {
+			return err
+		}
+	}
+	d.props = make([]byte, INPUT_PROP_CNT/8)
+	ioctl(d.file, ioc(iocRead, 'E', 0x09, uintptr(len(d.props))), unsafe.Pointer(&d.props[0])) // not on old kernels
+	for code := uint16(0); code < ABS_CNT; code++ {
+		if d.Has(EV_ABS, code) {
+			var info AbsInfo
+			if err := ioctl(d.file, ioc(iocRead, 'E', uintptr(0x40+code), unsafe.Sizeof(info)), unsafe.Pointer(&info)); err != nil {
+				return err
+			}
+			d.abs[code] = info
+		}
+	}
+	d.Capabilities = d.classify()
+	return nil
+}
+
+func hasBit(bits []byte, n uint16) bool {
+	return int(n/8) < len(bits) && bits[n/8]&(1<<(n%8)) != 0
+}
+
+// Report whether the device reports events of type evType with code, eg.
+// Has(EV_KEY, uint16(KeyA)). Type 0 asks for the type itself.
+func (d *Device) Has(evType, code uint16) bool {
+	if evType == 0 {
+		return hasBit(d.bits[0], code)
+	}
+	return int(evType) < len(d.bits) && hasBit(d.bits[evType], code)
+}
+
+// Return the range of an absolute axis, eg. ABS_MT_POSITION_X
+func (d *Device) Abs(code uint16) (AbsInfo, bool) {
+	info, ok := d.abs[code]
+	return info, ok
+}
+
+// Read the keys and buttons held, as bits by code
+func (d *Device) keyState() ([]byte, error) {
+	if d.file == nil {
+		return nil, fmt.Errorf("fail to read key state of %s: not a device", d.Path)
+	}
+	bits := make([]byte, KEY_CNT/8)
+	if err := ioctl(d.file, ioc(iocRead, 'E', 0x18, uintptr(len(bits))), unsafe.Pointer(&bits[0])); err != nil {
+		return nil, fmt.Errorf("fail to read key state of %s: %v", d.Path, err)
+	}
+	return bits, nil
+}
+
+// Read the current value of an absolute axis
+func (d *Device) absValue(code uint16) (int32, error) {
+	if d.file == nil {
+		return 0, fmt.Errorf("fail to read axis of %s: not a device", d.Path)
+	}
+	var info AbsInfo
+	if err := ioctl(d.file, ioc(iocRead, 'E', uintptr(0x40+code), unsafe.Sizeof(info)), unsafe.Pointer(&info)); err != nil {
+		return 0, fmt.Errorf("fail to read axis of %s: %v", d.Path, err)
+	}
+	return info.Value, nil
+}
+
+// Read the values of a multitouch axis in each of n slots, as struct
+// input_mt_request_layout
+func (d *Device) mtSlots(code uint16, n int) ([]int32, error) {
+	if d.file == nil {
+		return nil, fmt.Errorf("fail to read touch slots of %s: not a device", d.Path)
+	}
+	buf := make([]int32, 1+n)
+	buf[0] = int32(code)
+	if err := ioctl(d.file, ioc(iocRead, 'E', 0x0a, uintptr(4*len(buf))), unsafe.Pointer(&buf[0])); err != nil {
+		return nil, fmt.Errorf("fail to read touch slots of %s: %v", d.Path, err)
+	}
+	return buf[1:], nil
+}
+
+func (d *Device) classify() Capability {
+	var ret Capability
+	if d.Has(EV_KEY, uint16(KeyQ)) && d.Has(EV_KEY, uint16(KeyP)) && d.Has(EV_KEY, uint16(KeyEnter)) ||
+		d.Has(EV_KEY, uint16(Key1)) && d.Has(EV_KEY, uint16(Key0)) && d.Has(EV_KEY, uint16(KeyEnter)) {
+		ret |= Keyboard
+	}
+	if d.Has(EV_REL, REL_X) && d.Has(EV_REL, REL_Y) && d.Has(EV_KEY, uint16(BtnLeft)) {
+		ret |= Mouse
+	}
+	pointer := hasBit(d.props, INPUT_PROP_POINTER)
+	if !pointer && (d.Has(EV_ABS, ABS_MT_POSITION_X) || d.Has(EV_ABS, ABS_X) && d.Has(EV_KEY, uint16(BtnTouch))) {
+		ret |= Touchscreen
+	}
+	if d.Has(EV_KEY, BTN_GAMEPAD) || d.Has(EV_KEY, BTN_JOYSTICK) {
+		ret |= Gamepad
+	}
+	return ret
+}
+
+// Grab the device, so its events go to this program only and not also to
+// the console, or release it
+func (d *Device) Grab(grab bool) error {
+	if d.file == nil {
+		return nil
+	}
+	var arg uintptr
+	if grab {
+		arg = 1
+	}
+	if err := ioctlValue(d.file, ioc(iocWrite, 'E', 0x90, 4), arg); err != nil {
+		return fmt.Errorf("fail to grab input device %s: %v", d.Path, err)
+	}
+	return nil
+}
+
+// Read raw events, blocking until at least one is available
+func (d *Device) ReadEvents(events []RawEvent) (int, error) {
+	return d.reader.Read(events)
+}
+
+// Close the device
+func (d *Device) Close() error {
+	return d.closer.Close()
+}
+
+func (d *Device) String() string {
+	return fmt.Sprintf("%s %q (%s)", d.Path, d.Name, d.Capabilities)
+}
+
+// A Recording describes the device events in a file were recorded from, as
+// saved next to the events by cmd/evrecord
+type Recording struct {
+	Name         string             `json:"name"`
+	ID           ID                 `json:"id"`
+	Capabilities Capability         `json:"capabilities"`
+	Keys         []uint16           `json:"keys,omitempty"` // key and button codes reported
+	Abs          map[uint16]AbsInfo `json:"abs,omitempty"`
+	WordSize     int                `json:"wordSize"` // of a C long on the recording machine
+}
+
+// Return the description of a device for a recording of its events
+func (d *Device) Recording() Recording {
+	abs := map[uint16]AbsInfo{}
+	for code, info := range d.abs {
+		abs[code] = info
+	}
+	var keys []uint16
+	for code := uint16(0); code < KEY_CNT; code++ {
+		if d.Has(EV_KEY, code) {
+			keys = append(keys, code)
+		}
+	}
+	return Recording{Name: d.Name, ID: d.ID, Capabilities: d.Capabilities, Keys: keys, Abs: abs, WordSize: nativeWordSize}
+}
+
+// Return a device replaying events recorded from the device described by
+// rec. The events are delivered as fast as they are read, without delays.
+func Replay(r io.Reader, rec Recording) *Device {
+	d := &Device{Path: "replay", Name: rec.Name, ID: rec.ID, Capabilities: rec.Capabilities, abs: map[uint16]AbsInfo{}}
+	d.bits[EV_KEY] = make([]byte, KEY_CNT/8)
+	for _, code := range rec.Keys {
+		if code < KEY_CNT {
+			d.bits[EV_KEY][code/8] |= 1 << (code % 8)
+		}
+	}
+	d.bits[EV_ABS] = make([]byte, ABS_CNT/8)
+	for code, info := range rec.Abs {
+		d.abs[code] = info
+		d.bits[EV_ABS][code/8] |= 1 << (code % 8)
+	}
+	d.reader = NewEventReader(r, rec.WordSize)
+	d.closer = io.NopCloser(nil)
+	if c, ok := r.(io.Closer); ok {
+		d.closer = c
+	}
+	return d
+}
+
+// Open a recording of events at path, described by path.json
+func OpenRecording(path string) (*Device, error) {
+	data, err := os.ReadFile(path + ".json")
+	if err != nil {
+		return nil, fmt.Errorf("fail to read recording description: %v", err)
+	}
+	var rec Recording
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return nil, fmt.Errorf("fail to parse recording description %s.json: %v", path, err)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("fail to open recording: %v", err)
+	}
+	d := Replay(f, rec)
+	d.Path = path
+	return d, nil
+}
@@ -0,0 +1,172 @@
+package input
+
+import (
+	"fmt"
+	"time"
+)
+
+// A Key is a key or button code, as the KEY_ and BTN_ codes of evdev
+type Key uint16
+
+func (k Key) String() string {
+	if name, ok := keyNames[k]; ok {
+		return name
+	}
+	return fmt.Sprintf("KEY_0x%03X", uint16(k))
+}
+
+// Held modifier keys, and toggled locks
+type Modifiers uint16
+
+const (
+	ModShift Modifiers = 1 << iota
+	ModCtrl
+	ModAlt
+	ModAltGr
+	ModMeta
+	ModCapsLock
+	ModNumLock
+)
+
+// An Event is one of *KeyEvent, *MouseMoveEvent, *MouseButtonEvent,
+// *WheelEvent, *TouchEvent, *AttachEvent and *DetachEvent
+type Event interface {
+	Source() *Origin
+}
+
+// The device and time of an event
+type Origin struct {
+	Device *Device
+	Time   time.Time
+}
+
+// Return the device and time of the event
+func (o *Origin) Source() *Origin { return o }
+
+// Actions of key events
+type KeyAction uint8
+
+const (
+	KeyRelease KeyAction = iota
+	KeyPress
+	KeyRepeat
+)
+
+func (a KeyAction) String() string {
+	switch a {
+	case KeyRelease:
+		return "release"
+	case KeyPress:
+		return "press"
+	case KeyRepeat:
+		return "repeat"
+	}
+	return fmt.Sprintf("KeyAction(%d)", uint8(a))
+}
+
+// A KeyEvent reports a key pressed, released, or repeated while held
+type KeyEvent struct {
+	Origin
+	Key    Key
+	Action KeyAction
+	Rune   rune // typed by a press or repeat, 0 for other keys or with Ctrl, Alt or Meta
+	Mods   Modifiers
+}
+
+// A MouseMoveEvent reports relative mouse motion
+type MouseMoveEvent struct {
+	Origin
+	DX, DY int     // in mouse units
+	X, Y   float32 // of the pointer in display pixels, from the top left
+}
+
+// A MouseButtonEvent reports a mouse button pressed or released
+type MouseButtonEvent struct {
+	Origin
+	Button  Key // BtnLeft, BtnRight, BtnMiddle, BtnSide or BtnExtra
+	Pressed bool
+	X, Y    float32
+}
+
+// A WheelEvent reports the wheel turned, in detents
+type WheelEvent struct {
+	Origin
+	DX, DY int // DY positive turning away from the user
+}
+
+// Actions of touch events
+type TouchAction uint8
+
+const (
+	TouchDown TouchAction = iota
+	TouchMove
+	TouchUp
+)
+
+func (a TouchAction) String() string {
+	switch a {
+	case TouchDown:
+		return "down"
+	case TouchMove:
+		return "move"
+	case TouchUp:
+		return "up"
+	}
+	return fmt.Sprintf("TouchAction(%d)", uint8(a))
+}
+
+// A TouchEvent reports a contact on a touchscreen
+type TouchEvent struct {
+	Origin
+	ID     int // stays the same from down to up of a contact
+	Action TouchAction
+	X, Y   float32 // in display pixels from the top left, after rotation
+}
+
+// An AttachEvent reports a device added, when opened or plugged in. Its
+// Name and Capabilities tell eg. a barcode scanner from a keyboard.
+type AttachEvent struct {
+	Origin
+}
+
+// A DetachEvent reports a device removed, when unplugged or failing. Keys
+// held on it are not released, contacts touching end with a TouchUp first.
+type DetachEvent struct {
+	Origin
+	Err error // that ended reading the device
+}
+
+func (e *KeyEvent) String() string {
+	if e.Rune != 0 {
+		return fmt.Sprintf("key %s %s %q", e.Key, e.Action, e.Rune)
+	}
+	return fmt.Sprintf("key %s %s", e.Key, e.Action)
+}
+
+func (e *MouseMoveEvent) String() string {
+	return fmt.Sprintf("mouse move %+d,%+d to %.0f,%.0f", e.DX, e.DY, e.X, e.Y)
+}
+
+func (e *MouseButtonEvent) String() string {
+	action := "release"
+	if e.Pressed {
+		action = "press"
+	}
+	return fmt.Sprintf("mouse %s %s at %.0f,%.0f", e.Button, action, e.X, e.Y)
+}
+
+func (e *WheelEvent) String() string {
+	return fmt.Sprintf("wheel %+d,%+d", e.DX, e.DY)
+}
+
+func (e *TouchEvent) String() string {
+	return fmt.Sprintf("touch %d %s at %.0f,%.0f", e.ID, e.Action, e.X, e.Y)
+}
+
+func (e *AttachEvent) String() string {
+	return fmt.Sprintf("attach %s", e.Device)
+}
+
+func (e *DetachEvent) String() string {
+	return fmt.Sprintf("detach %s: %v", e.Device, e.Err)
+}
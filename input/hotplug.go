@@ -0,0 +1,143 @@
+package input
+
+import (
+	"encoding/binary"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// Retries opening a new device, while udev sets its permissions
+const (
+	retryInterval = 100 * time.Millisecond
+	retryCount    = 30
+)
+
+// A watcher follows the devices added to and removed from a directory
+// with inotify, and plugs them into an Input
+type watcher struct {
+	in      *Input
+	dir     string
+	file    *os.File
+	paths   chan string
+	retries chan string
+	done    chan struct{}
+}
+
+func newWatcher(in *Input, dir string) (*watcher, error) {
+	fd, err := syscall.InotifyInit1(syscall.IN_CLOEXEC | syscall.IN_NONBLOCK)
+	if err != nil {
+		return nil, fmt.Errorf("fail to watch %s: %v", dir, err)
+	}
+	if _, err := syscall.InotifyAddWatch(fd, dir, syscall.IN_CREATE|syscall.IN_ATTRIB|syscall.IN_DELETE); err != nil {
+		syscall.Close(fd)
+		return nil, fmt.Errorf("fail to watch %s: %v", dir, err)
+	}
+	w := &watcher{
+		in:      in,
+		dir:     dir,
+		file:    os.NewFile(uintptr(fd), "inotify"), // non-blocking, so close interrupts read
+		paths:   make(chan string, 16),
+		retries: make(chan string, 16),
+		done:    make(chan struct{}),
+	}
+	go w.read()
+	go w.run()
+	return w, nil
+}
+
+// Read inotify events, passing on the paths of event devices
+func (w *watcher) read() {
+	defer close(w.paths)
+	buf := make([]byte, 4096)
+	for {
+		n, err := w.file.Read(buf)
+		if err != nil {
+			return
+		}
+		// struct inotify_event, followed by a padded name
+		for b := buf[:n]; len(b) >= 16; {
+			mask := binary.LittleEndian.Uint32(b[4:])
+			size := int(binary.LittleEndian.Uint32(b[12:]))
+			if len(b) < 16+size {
+				break
+			}
+			name := strings.TrimRight(string(b[16:16+size]), "\x00")
+			b = b[16+size:]
+			if !strings.HasPrefix(name, "event") || mask&syscall.IN_ISDIR != 0 {
+				continue
+			}
+			path := filepath.Join(w.dir, name)
+			if mask&syscall.IN_DELETE != 0 {
+				w.unplug(path)
+				continue
+			}
+			select {
+			case w.paths <- path:
+			case <-w.done:
+				return
+			}
+		}
+	}
+}
+
+// Close a removed device. Reading it fails, which detaches it.
+func (w *watcher) unplug(path string) {
+	if dev := w.in.device(path); dev != nil {
+		dev.Close()
+	}
+}
+
+// Plug in new devices, retrying those that can not be opened yet
+func (w *watcher) run() {
+	pending := map[string]int{}
+	ticker := time.NewTicker(retryInterval)
+	defer ticker.Stop()
+	plug := func(path string) {
+		if w.in.plug(path) {
+			if _, ok := pending[path]; !ok {
+				pending[path] = retryCount
+			}
+		} else {
+			delete(pending, path)
+		}
+	}
+	for {
+		select {
+		case path, ok := <-w.paths:
+			if !ok {
+				return
+			}
+			plug(path)
+		case path := <-w.retries:
+			plug(path)
+		case <-ticker.C:
+			for path, count := range pending {
+				if count <= 0 {
+					delete(pending, path)
+					continue
+				}
+				pending[path] = count - 1
+				plug(path)
+			}
+		case <-w.done:
+			return
+		}
+	}
+}
+
+// Try to reopen a device after it failed
+func (w *watcher) retry(path string) {
+	select {
+	case w.retries <- path:
+	default:
+	}
+}
+
+func (w *watcher) close() {
+	close(w.done)
+	w.file.Close()
+}
@@ -0,0 +1,360 @@
+// Package input reads keyboards, mice and touchscreens from the Linux evdev
+// devices in /dev/input, as there is no X server to deliver input events.
+// Events of all devices arrive on one channel:
+//
+//	w, h := piglet.GetDisplaySize()
+//	in, err := input.Open(input.Options{Display: input.Display{Width: int(w), Height: int(h)}})
+//	...
+//	for e := range in.Events() {
+//		switch e := e.(type) {
+//		case *input.KeyEvent:
+//			...
+//		case *input.TouchEvent:
+//			...
+//		}
+//	}
+//
+// With Options.Hotplug, devices plugged in later are opened as they appear,
+// and reported with an AttachEvent; removed ones with a DetachEvent.
+//
+// Game controllers, opened with Gamepad in Options.Capabilities, are polled
+// once per frame instead, see Controller and MappingDB.
+//
+// Reading /dev/input needs root, or membership in the input group. For tests,
+// devices can be replayed from recorded events, or created with uinput.
+package input
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// Options of Open
+type Options struct {
+	Devices      []string               // paths to open, default all devices in /dev/input accepted by Capabilities and Match
+	Capabilities Capability             // devices with any of these are opened, Keyboard|Mouse|Touchscreen if zero
+	Match        func(dev *Device) bool // if set, devices must also match, eg. by Name
+	Hotplug      bool                   // watch /dev/input and open devices plugged in later
+	Display      Display                // touch and pointer positions are mapped to
+	Keymap       Keymap                 // KeymapUS if nil
+	Mappings     *MappingDB             // of controllers, opened with Capabilities including Gamepad
+	Grab         bool                   // grab devices, so keys typed do not also reach the console
+	Buffer       int                    // events buffered in the channel, 256 if zero
+}
+
+// Directory of evdev devices
+const inputDir = "/dev/input"
+
+// An Input reads events from a set of devices
+type Input struct {
+	keymap   Keymap
+	mappings *MappingDB
+	grab     bool
+	caps     Capability
+	match    func(dev *Device) bool
+	watcher  *watcher
+	events   chan Event
+	raw      chan batch
+	done     chan struct{}
+	wg       sync.WaitGroup
+	once     sync.Once
+
+	plugging sync.Mutex
+	mu       sync.Mutex
+	devices  map[*Device]*decoder
+	display  Display
+	pointerX float32
+	pointerY float32
+}
+
+// raw events read from a device, or the error that ended reading it, or
+// the device being added
+type batch struct {
+	dev    *Device
+	events []RawEvent
+	err    error
+	attach bool
+}
+
+// Open devices and start reading them
+func Open(opts Options) (*Input, error) {
+	in := New(opts)
+	if opts.Hotplug {
+		// watch before listing, to not miss devices plugged in between
+		w, err := newWatcher(in, inputDir)
+		if err != nil {
+			in.Close()
+			return nil, err
+		}
+		in.watcher = w
+	}
+	for _, path := range opts.Devices {
+		dev, err := OpenDevice(path)
+		if err == nil {
+			err = in.Add(dev)
+		}
+		if err != nil {
+			in.Close()
+			return nil, err
+		}
+	}
+	if len(opts.Devices) == 0 {
+		paths, _ := filepath.Glob(filepath.Join(inputDir, "event*"))
+		sort.Strings(paths)
+		for _, path := range paths {
+			in.plug(path)
+		}
+	}
+	return in, nil
+}
+
+// Report whether a device is opened when found in /dev/input
+func (in *Input) accept(dev *Device) bool {
+	return dev.Capabilities&in.caps != 0 && (in.match == nil || in.match(dev))
+}
+
+// Open and add the device at path, unless it is open already or not
+// accepted. Report whether to try again, as udev may not have set the
+// permissions of a new device yet.
+func (in *Input) plug(path string) (retry bool) {
+	in.plugging.Lock()
+	defer in.plugging.Unlock()
+	if in.device(path) != nil {
+		return false
+	}
+	dev, err := OpenDevice(path)
+	if err != nil {
+		return !errors.Is(err, os.ErrNotExist) && !errors.Is(err, syscall.ENODEV)
+	}
+	if !in.accept(dev) || in.Add(dev) != nil {
+		dev.Close()
+	}
+	return false
+}
+
+// Return the open device at path, or nil
+func (in *Input) device(path string) *Device {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	for dev := range in.devices {
+		if dev.Path == path {
+			return dev
+		}
+	}
+	return nil
+}
+
+// Return an Input without devices, to Add them
+func New(opts Options) *Input {
+	if opts.Keymap == nil {
+		opts.Keymap = KeymapUS
+	}
+	if opts.Buffer == 0 {
+		opts.Buffer = 256
+	}
+	if opts.Capabilities == 0 {
+		opts.Capabilities = Keyboard | Mouse | Touchscreen
+	}
+	in := &Input{
+		keymap:   opts.Keymap,
+		mappings: opts.Mappings,
+		grab:     opts.Grab,
+		caps:     opts.Capabilities,
+		match:    opts.Match,
+		events:   make(chan Event, opts.Buffer),
+		raw:      make(chan batch, 16),
+		done:     make(chan struct{}),
+		devices:  map[*Device]*decoder{},
+	}
+	in.SetDisplay(opts.Display)
+	go in.loop()
+	return in
+}
+
+// Return the channel events arrive on. It is closed by Close.
+func (in *Input) Events() <-chan Event {
+	return in.events
+}
+
+// Start reading a device, eg. from OpenDevice or Replay, and report it with
+// an AttachEvent. The Input closes it when reading fails or on Close.
+func (in *Input) Add(dev *Device) error {
+	if in.grab {
+		if err := dev.Grab(true); err != nil {
+			return err
+		}
+	}
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	select {
+	case <-in.done:
+		return fmt.Errorf("fail to add input device %s: input closed", dev.Path)
+	default:
+	}
+	in.devices[dev] = newDecoder(dev, in)
+	in.wg.Add(1)
+	go in.read(dev)
+	return nil
+}
+
+// Read raw events of a device until it fails, eg. when closed or unplugged
+func (in *Input) read(dev *Device) {
+	defer in.wg.Done()
+	select {
+	case in.raw <- batch{dev: dev, attach: true}:
+	case <-in.done:
+		return
+	}
+	buf := make([]RawEvent, 64)
+	for {
+		n, err := dev.ReadEvents(buf)
+		if n > 0 {
+			b := batch{dev: dev, events: append([]RawEvent(nil), buf[:n]...)}
+			select {
+			case in.raw <- b:
+			case <-in.done:
+				return
+			}
+		}
+		if err != nil {
+			select {
+			case in.raw <- batch{dev: dev, err: err}:
+			case <-in.done:
+			}
+			return
+		}
+	}
+}
+
+// Return the connected game controllers, to Poll once per frame
+func (in *Input) Controllers() []*Controller {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	var ret []*Controller
+	for _, d := range in.devices {
+		if d.controller != nil {
+			ret = append(ret, d.controller)
+		}
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].Device.Path < ret[j].Device.Path })
+	return ret
+}
+
+// Return the devices read
+func (in *Input) Devices() []*Device {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	ret := make([]*Device, 0, len(in.devices))
+	for dev := range in.devices {
+		ret = append(ret, dev)
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].Path < ret[j].Path })
+	return ret
+}
+
+// Set the display touch and pointer positions are mapped to, eg. after the
+// display size changed. The pointer moves to the center.
+func (in *Input) SetDisplay(display Display) {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	in.display = display
+	in.pointerX, in.pointerY = float32(display.Width)/2, float32(display.Height)/2
+}
+
+// Return the display touch and pointer positions are mapped to
+func (in *Input) Display() Display {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	return in.display
+}
+
+// Return the position of the pointer moved by mice, in display pixels
+// from the top left
+func (in *Input) Pointer() (float32, float32) {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	return in.pointerX, in.pointerY
+}
+
+// Move the pointer, keeping it on the display
+func (in *Input) movePointer(dx, dy int) (float32, float32) {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	clamp := func(v float32, size int) float32 {
+		if v < 0 || size == 0 {
+			return 0
+		} else if v > float32(size-1) {
+			return float32(size - 1)
+		}
+		return v
+	}
+	in.pointerX = clamp(in.pointerX+float32(dx), in.display.Width)
+	in.pointerY = clamp(in.pointerY+float32(dy), in.display.Height)
+	return in.pointerX, in.pointerY
+}
+
+// Stop reading all devices and close them, and close the events channel
+func (in *Input) Close() error {
+	in.once.Do(func() {
+		if in.watcher != nil {
+			in.watcher.close()
+		}
+		in.mu.Lock()
+		close(in.done)
+		for dev := range in.devices {
+			dev.Close()
+		}
+		in.mu.Unlock()
+		in.wg.Wait()
+	})
+	return nil
+}
+
+// Decode raw events of all devices into events
+func (in *Input) loop() {
+	defer close(in.events)
+	emit := func(e Event) {
+		select {
+		case in.events <- e:
+		case <-in.done:
+		}
+	}
+	for {
+		select {
+		case b := <-in.raw:
+			in.mu.Lock()
+			d := in.devices[b.dev]
+			if b.err != nil {
+				delete(in.devices, b.dev)
+				b.dev.Close()
+			}
+			in.mu.Unlock()
+			if d == nil {
+				continue
+			}
+			switch {
+			case b.attach:
+				emit(&AttachEvent{Origin: Origin{Device: b.dev, Time: time.Now()}})
+			case b.err != nil:
+				d.reset(RawEvent{Time: time.Now()}, emit)
+				if d.controller != nil {
+					d.controller.disconnect()
+				}
+				emit(&DetachEvent{Origin: Origin{Device: b.dev, Time: time.Now()}, Err: b.err})
+				if in.watcher != nil && b.dev.file != nil && !errors.Is(b.err, syscall.ENODEV) {
+					in.watcher.retry(b.dev.Path) // failed but not unplugged, reopen
+				}
+			default:
+				d.decode(b.events, emit)
+			}
+		case <-in.done:
+			return
+		}
+	}
+}
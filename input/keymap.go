@@ -0,0 +1,78 @@
+package input
+
+import (
+	"unicode"
+)
+
+// A Keymap maps keys to the runes they type: plain, with Shift, and with
+// AltGr. Zero runes type nothing.
+type Keymap map[Key][3]rune
+
+// Return the rune key types with mods held, or 0
+func (m Keymap) Rune(key Key, mods Modifiers) rune {
+	if mods&(ModCtrl|ModAlt|ModMeta) != 0 {
+		return 0
+	}
+	if key >= KeyKP7 && key <= KeyKPDot && key != KeyKPMinus && key != KeyKPPlus && mods&ModNumLock == 0 {
+		return 0 // navigation keys without NumLock
+	}
+	runes, ok := m[key]
+	if !ok {
+		runes, ok = keypad[key]
+	}
+	if !ok {
+		return 0
+	}
+	if mods&ModAltGr != 0 && runes[2] != 0 {
+		return runes[2]
+	}
+	shift := mods&ModShift != 0
+	if mods&ModCapsLock != 0 && unicode.IsLetter(runes[0]) {
+		shift = !shift
+	}
+	if shift && runes[1] != 0 {
+		return runes[1]
+	}
+	return runes[0]
+}
+
+// keys typing the same on every layout
+var keypad = Keymap{
+	KeySpace: {' ', ' '}, KeyEnter: {'\n', '\n'}, KeyTab: {'\t', '\t'},
+	KeyKP0: {'0'}, KeyKP1: {'1'}, KeyKP2: {'2'}, KeyKP3: {'3'}, KeyKP4: {'4'},
+	KeyKP5: {'5'}, KeyKP6: {'6'}, KeyKP7: {'7'}, KeyKP8: {'8'}, KeyKP9: {'9'},
+	KeyKPDot: {'.'}, KeyKPSlash: {'/'}, KeyKPAsterisk: {'*'}, KeyKPMinus: {'-'},
+	KeyKPPlus: {'+'}, KeyKPEnter: {'\n'}, KeyKPEqual: {'='}, KeyKPComma: {','},
+}
+
+// The US layout
+var KeymapUS = Keymap{
+	KeyGrave: {'`', '~'}, Key1: {'1', '!'}, Key2: {'2', '@'}, Key3: {'3', '#'}, Key4: {'4', '$'},
+	Key5: {'5', '%'}, Key6: {'6', '^'}, Key7: {'7', '&'}, Key8: {'8', '*'}, Key9: {'9', '('},
+	Key0: {'0', ')'}, KeyMinus: {'-', '_'}, KeyEqual: {'=', '+'},
+	KeyQ: {'q', 'Q'}, KeyW: {'w', 'W'}, KeyE: {'e', 'E'}, KeyR: {'r', 'R'}, KeyT: {'t', 'T'},
+	KeyY: {'y', 'Y'}, KeyU: {'u', 'U'}, KeyI: {'i', 'I'}, KeyO: {'o', 'O'}, KeyP: {'p', 'P'},
+	KeyLeftBrace: {'[', '{'}, KeyRightBrace: {']', '}'}, KeyBackslash: {'\\', '|'},
+	KeyA: {'a', 'A'}, KeyS: {'s', 'S'}, KeyD: {'d', 'D'}, KeyF: {'f', 'F'}, KeyG: {'g', 'G'},
+	KeyH: {'h', 'H'}, KeyJ: {'j', 'J'}, KeyK: {'k', 'K'}, KeyL: {'l', 'L'},
+	KeySemicolon: {';', ':'}, KeyApostrophe: {'\'', '"'},
+	Key102nd: {'\\', '|'}, KeyZ: {'z', 'Z'}, KeyX: {'x', 'X'}, KeyC: {'c', 'C'}, KeyV: {'v', 'V'},
+	KeyB: {'b', 'B'}, KeyN: {'n', 'N'}, KeyM: {'m', 'M'}, KeyComma: {',', '<'},
+	KeyDot: {'.', '>'}, KeySlash: {'/', '?'},
+}
+
+// The German layout, with the dead keys ^ and ´ typing themselves
+var KeymapDE = Keymap{
+	KeyGrave: {'^', '°'}, Key1: {'1', '!'}, Key2: {'2', '"', '²'}, Key3: {'3', '§', '³'}, Key4: {'4', '$'},
+	Key5: {'5', '%'}, Key6: {'6', '&'}, Key7: {'7', '/', '{'}, Key8: {'8', '(', '['}, Key9: {'9', ')', ']'},
+	Key0: {'0', '=', '}'}, KeyMinus: {'ß', '?', '\\'}, KeyEqual: {'´', '`'},
+	KeyQ: {'q', 'Q', '@'}, KeyW: {'w', 'W'}, KeyE: {'e', 'E', '€'}, KeyR: {'r', 'R'}, KeyT: {'t', 'T'},
+	KeyY: {'z', 'Z'}, KeyU: {'u', 'U'}, KeyI: {'i', 'I'}, KeyO: {'o', 'O'}, KeyP: {'p', 'P'},
+	KeyLeftBrace: {'ü', 'Ü'}, KeyRightBrace: {'+', '*', '~'}, KeyBackslash: {'#', '\''},
+	KeyA: {'a', 'A'}, KeyS: {'s', 'S'}, KeyD: {'d', 'D'}, KeyF: {'f', 'F'}, KeyG: {'g', 'G'},
+	KeyH: {'h', 'H'}, KeyJ: {'j', 'J'}, KeyK: {'k', 'K'}, KeyL: {'l', 'L'},
+	KeySemicolon: {'ö', 'Ö'}, KeyApostrophe: {'ä', 'Ä'},
+	Key102nd: {'<', '>', '|'}, KeyZ: {'y', 'Y'}, KeyX: {'x', 'X'}, KeyC: {'c', 'C'}, KeyV: {'v', 'V'},
+	KeyB: {'b', 'B'}, KeyN: {'n', 'N'}, KeyM: {'m', 'M', 'µ'}, KeyComma: {',', ';'},
+	KeyDot: {'.', ':'}, KeySlash: {'-', '_'},
+}
@@ -0,0 +1,243 @@
+package input
+
+// Keys and buttons, as the KEY_ and BTN_ codes of linux/input-event-codes.h
+const (
+	KeyEsc        Key = 1
+	Key1          Key = 2
+	Key2          Key = 3
+	Key3          Key = 4
+	Key4          Key = 5
+	Key5          Key = 6
+	Key6          Key = 7
+	Key7          Key = 8
+	Key8          Key = 9
+	Key9          Key = 10
+	Key0          Key = 11
+	KeyMinus      Key = 12
+	KeyEqual      Key = 13
+	KeyBackspace  Key = 14
+	KeyTab        Key = 15
+	KeyQ          Key = 16
+	KeyW          Key = 17
+	KeyE          Key = 18
+	KeyR          Key = 19
+	KeyT          Key = 20
+	KeyY          Key = 21
+	KeyU          Key = 22
+	KeyI          Key = 23
+	KeyO          Key = 24
+	KeyP          Key = 25
+	KeyLeftBrace  Key = 26
+	KeyRightBrace Key = 27
+	KeyEnter      Key = 28
+	KeyLeftCtrl   Key = 29
+	KeyA          Key = 30
+	KeyS          Key = 31
+	KeyD          Key = 32
+	KeyF          Key = 33
+	KeyG          Key = 34
+	KeyH          Key = 35
+	KeyJ          Key = 36
+	KeyK          Key = 37
+	KeyL          Key = 38
+	KeySemicolon  Key = 39
+	KeyApostrophe Key = 40
+	KeyGrave      Key = 41
+	KeyLeftShift  Key = 42
+	KeyBackslash  Key = 43
+	KeyZ          Key = 44
+	KeyX          Key = 45
+	KeyC          Key = 46
+	KeyV          Key = 47
+	KeyB          Key = 48
+	KeyN          Key = 49
+	KeyM          Key = 50
+	KeyComma      Key = 51
+	KeyDot        Key = 52
+	KeySlash      Key = 53
+	KeyRightShift Key = 54
+	KeyKPAsterisk Key = 55
+	KeyLeftAlt    Key = 56
+	KeySpace      Key = 57
+	KeyCapsLock   Key = 58
+	KeyF1         Key = 59
+	KeyF2         Key = 60
+	KeyF3         Key = 61
+	KeyF4         Key = 62
+	KeyF5         Key = 63
+	KeyF6         Key = 64
+	KeyF7         Key = 65
+	KeyF8         Key = 66
+	KeyF9         Key = 67
+	KeyF10        Key = 68
+	KeyNumLock    Key = 69
+	KeyScrollLock Key = 70
+	KeyKP7        Key = 71
+	KeyKP8        Key = 72
+	KeyKP9        Key = 73
+	KeyKPMinus    Key = 74
+	KeyKP4        Key = 75
+	KeyKP5        Key = 76
+	KeyKP6        Key = 77
+	KeyKPPlus     Key = 78
+	KeyKP1        Key = 79
+	KeyKP2        Key = 80
+	KeyKP3        Key = 81
+	KeyKP0        Key = 82
+	KeyKPDot      Key = 83
+	Key102nd      Key = 86
+	KeyF11        Key = 87
+	KeyF12        Key = 88
+	KeyKPEnter    Key = 96
+	KeyRightCtrl  Key = 97
+	KeyKPSlash    Key = 98
+	KeySysRq      Key = 99
+	KeyRightAlt   Key = 100
+	KeyHome       Key = 102
+	KeyUp         Key = 103
+	KeyPageUp     Key = 104
+	KeyLeft       Key = 105
+	KeyRight      Key = 106
+	KeyEnd        Key = 107
+	KeyDown       Key = 108
+	KeyPageDown   Key = 109
+	KeyInsert     Key = 110
+	KeyDelete     Key = 111
+	KeyMute       Key = 113
+	KeyVolumeDown Key = 114
+	KeyVolumeUp   Key = 115
+	KeyPower      Key = 116
+	KeyKPEqual    Key = 117
+	KeyPause      Key = 119
+	KeyKPComma    Key = 121
+	KeyLeftMeta   Key = 125
+	KeyRightMeta  Key = 126
+	KeyCompose    Key = 127
+
+	BtnLeft   Key = 0x110
+	BtnRight  Key = 0x111
+	BtnMiddle Key = 0x112
+	BtnSide   Key = 0x113
+	BtnExtra  Key = 0x114
+	BtnTouch  Key = 0x14a
+)
+
+var keyNames = map[Key]string{
+	KeyEsc:        "ESC",
+	Key1:          "1",
+	Key2:          "2",
+	Key3:          "3",
+	Key4:          "4",
+	Key5:          "5",
+	Key6:          "6",
+	Key7:          "7",
+	Key8:          "8",
+	Key9:          "9",
+	Key0:          "0",
+	KeyMinus:      "MINUS",
+	KeyEqual:      "EQUAL",
+	KeyBackspace:  "BACKSPACE",
+	KeyTab:        "TAB",
+	KeyQ:          "Q",
+	KeyW:          "W",
+	KeyE:          "E",
+	KeyR:          "R",
+	KeyT:          "T",
+	KeyY:          "Y",
+	KeyU:          "U",
+	KeyI:          "I",
+	KeyO:          "O",
+	KeyP:          "P",
+	KeyLeftBrace:  "LEFTBRACE",
+	KeyRightBrace: "RIGHTBRACE",
+	KeyEnter:      "ENTER",
+	KeyLeftCtrl:   "LEFTCTRL",
+	KeyA:          "A",
+	KeyS:          "S",
+	KeyD:          "D",
+	KeyF:          "F",
+	KeyG:          "G",
+	KeyH:          "H",
+	KeyJ:          "J",
+	KeyK:          "K",
+	KeyL:          "L",
+	KeySemicolon:  "SEMICOLON",
+	KeyApostrophe: "APOSTROPHE",
+	KeyGrave:      "GRAVE",
+	KeyLeftShift:  "LEFTSHIFT",
+	KeyBackslash:  "BACKSLASH",
+	KeyZ:          "Z",
+	KeyX:          "X",
+	KeyC:          "C",
+	KeyV:          "V",
+	KeyB:          "B",
+	KeyN:          "N",
+	KeyM:          "M",
+	KeyComma:      "COMMA",
+	KeyDot:        "DOT",
+	KeySlash:      "SLASH",
+	KeyRightShift: "RIGHTSHIFT",
+	KeyKPAsterisk: "KPASTERISK",
+	KeyLeftAlt:    "LEFTALT",
+	KeySpace:      "SPACE",
+	KeyCapsLock:   "CAPSLOCK",
+	KeyF1:         "F1",
+	KeyF2:         "F2",
+	KeyF3:         "F3",
+	KeyF4:         "F4",
+	KeyF5:         "F5",
+	KeyF6:         "F6",
+	KeyF7:         "F7",
+	KeyF8:         "F8",
+	KeyF9:         "F9",
+	KeyF10:        "F10",
+	KeyNumLock:    "NUMLOCK",
+	KeyScrollLock: "SCROLLLOCK",
+	KeyKP7:        "KP7",
+	KeyKP8:        "KP8",
+	KeyKP9:        "KP9",
+	KeyKPMinus:    "KPMINUS",
+	KeyKP4:        "KP4",
+	KeyKP5:        "KP5",
+	KeyKP6:        "KP6",
+	KeyKPPlus:     "KPPLUS",
+	KeyKP1:        "KP1",
+	KeyKP2:        "KP2",
+	KeyKP3:        "KP3",
+	KeyKP0:        "KP0",
+	KeyKPDot:      "KPDOT",
+	Key102nd:      "102ND",
+	KeyF11:        "F11",
+	KeyF12:        "F12",
+	KeyKPEnter:    "KPENTER",
+	KeyRightCtrl:  "RIGHTCTRL",
+	KeyKPSlash:    "KPSLASH",
+	KeySysRq:      "SYSRQ",
+	KeyRightAlt:   "RIGHTALT",
+	KeyHome:       "HOME",
+	KeyUp:         "UP",
+	KeyPageUp:     "PAGEUP",
+	KeyLeft:       "LEFT",
+	KeyRight:      "RIGHT",
+	KeyEnd:        "END",
+	KeyDown:       "DOWN",
+	KeyPageDown:   "PAGEDOWN",
+	KeyInsert:     "INSERT",
+	KeyDelete:     "DELETE",
+	KeyMute:       "MUTE",
+	KeyVolumeDown: "VOLUMEDOWN",
+	KeyVolumeUp:   "VOLUMEUP",
+	KeyPower:      "POWER",
+	KeyKPEqual:    "KPEQUAL",
+	KeyPause:      "PAUSE",
+	KeyKPComma:    "KPCOMMA",
+	KeyLeftMeta:   "LEFTMETA",
+	KeyRightMeta:  "RIGHTMETA",
+	KeyCompose:    "COMPOSE",
+	BtnLeft:       "BTN_LEFT",
+	BtnRight:      "BTN_RIGHT",
+	BtnMiddle:     "BTN_MIDDLE",
+	BtnSide:       "BTN_SIDE",
+	BtnExtra:      "BTN_EXTRA",
+	BtnTouch:      "BTN_TOUCH",
+}
@@ -0,0 +1,298 @@
+package input
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Buttons of a gamepad, laid out like an Xbox controller
+type Button int
+
+const (
+	ButtonA Button = iota // bottom face button
+	ButtonB               // right face button
+	ButtonX               // left face button
+	ButtonY               // top face button
+	ButtonBack
+	ButtonGuide
+	ButtonStart
+	ButtonLeftStick
+	ButtonRightStick
+	ButtonLeftShoulder
+	ButtonRightShoulder
+	ButtonDPadUp
+	ButtonDPadDown
+	ButtonDPadLeft
+	ButtonDPadRight
+	ButtonCount
+)
+
+// Axes of a gamepad
+type Axis int
+
+const (
+	AxisLeftX        Axis = iota // -1 left to 1 right
+	AxisLeftY                    // -1 up to 1 down
+	AxisRightX                   // -1 left to 1 right
+	AxisRightY                   // -1 up to 1 down
+	AxisTriggerLeft              // 0 released to 1 pressed
+	AxisTriggerRight             // 0 released to 1 pressed
+	AxisCount
+)
+
+// names of buttons and axes in SDL_GameControllerDB mappings
+var (
+	buttonNames = [ButtonCount]string{"a", "b", "x", "y", "back", "guide", "start", "leftstick", "rightstick",
+		"leftshoulder", "rightshoulder", "dpup", "dpdown", "dpleft", "dpright"}
+	axisNames = [AxisCount]string{"leftx", "lefty", "rightx", "righty", "lefttrigger", "righttrigger"}
+)
+
+func (b Button) String() string {
+	if b >= 0 && b < ButtonCount {
+		return buttonNames[b]
+	}
+	return fmt.Sprintf("Button(%d)", int(b))
+}
+
+func (a Axis) String() string {
+	if a >= 0 && a < AxisCount {
+		return axisNames[a]
+	}
+	return fmt.Sprintf("Axis(%d)", int(a))
+}
+
+// An input of a device a mapping reads: a button, an axis or a hat
+// direction, by index as counted by SDL
+type source struct {
+	kind   byte // 'b', 'a' or 'h'
+	index  int
+	hat    int // direction bits of a hat: 1 up, 2 right, 4 down, 8 left
+	half   int // of an axis, 1 positive, -1 negative, 0 both
+	invert bool
+}
+
+// A binding maps a source to a button, or to an axis or half of it
+type binding struct {
+	src    source
+	button Button
+	axis   Axis
+	half   int // of the axis, 1 positive, -1 negative, 0 both
+}
+
+// A Mapping maps the buttons, axes and hats of a device to a gamepad, as
+// parsed from an SDL_GameControllerDB string like
+//
+//	030000005e0400008e02000014010000,Xbox 360 Controller,a:b0,b:b1,...,leftx:a0,...,platform:Linux,
+type Mapping struct {
+	GUID  string
+	Name  string
+	binds []binding
+}
+
+// Parse a mapping in SDL_GameControllerDB format. Targets and inputs not
+// known, eg. paddles, are ignored.
+func ParseMapping(line string) (*Mapping, error) {
+	fields := strings.Split(strings.TrimSpace(line), ",")
+	if len(fields) < 2 || len(fields[0]) != 32 {
+		return nil, fmt.Errorf("fail to parse gamepad mapping %.40q: no guid and name", line)
+	}
+	if _, err := hex.DecodeString(fields[0]); err != nil {
+		return nil, fmt.Errorf("fail to parse gamepad mapping %.40q: bad guid", line)
+	}
+	m := &Mapping{GUID: strings.ToLower(fields[0]), Name: fields[1]}
+	for _, field := range fields[2:] {
+		kv := strings.SplitN(field, ":", 2)
+		if len(kv) != 2 || kv[0] == "platform" || kv[1] == "" {
+			continue
+		}
+		b := binding{button: -1, axis: -1}
+		target := kv[0]
+		if strings.HasPrefix(target, "+") || strings.HasPrefix(target, "-") {
+			b.half = 1
+			if target[0] == '-' {
+				b.half = -1
+			}
+			target = target[1:]
+		}
+		for i, name := range buttonNames {
+			if name == target {
+				b.button = Button(i)
+			}
+		}
+		for i, name := range axisNames {
+			if name == target {
+				b.axis = Axis(i)
+			}
+		}
+		if b.button < 0 && b.axis < 0 {
+			continue
+		}
+		src, err := parseSource(kv[1])
+		if err != nil {
+			return nil, fmt.Errorf("fail to parse gamepad mapping %q of %s: %v", field, m.Name, err)
+		}
+		b.src = src
+		m.binds = append(m.binds, b)
+	}
+	return m, nil
+}
+
+func parseSource(s string) (source, error) {
+	var src source
+	if strings.HasPrefix(s, "+") || strings.HasPrefix(s, "-") {
+		src.half = 1
+		if s[0] == '-' {
+			src.half = -1
+		}
+		s = s[1:]
+	}
+	if strings.HasSuffix(s, "~") {
+		src.invert = true
+		s = s[:len(s)-1]
+	}
+	if len(s) < 2 {
+		return src, fmt.Errorf("bad input %q", s)
+	}
+	src.kind = s[0]
+	var err error
+	switch src.kind {
+	case 'b', 'a':
+		src.index, err = strconv.Atoi(s[1:])
+	case 'h':
+		parts := strings.SplitN(s[1:], ".", 2)
+		if len(parts) != 2 {
+			return src, fmt.Errorf("bad hat %q", s)
+		}
+		if src.index, err = strconv.Atoi(parts[0]); err == nil {
+			src.hat, err = strconv.Atoi(parts[1])
+		}
+	default:
+		return src, fmt.Errorf("bad input %q", s)
+	}
+	if err != nil {
+		return src, fmt.Errorf("bad input %q", s)
+	}
+	return src, nil
+}
+
+// Return the mapping as an SDL_GameControllerDB string
+func (m *Mapping) String() string {
+	var b strings.Builder
+	b.WriteString(m.GUID + "," + m.Name + ",")
+	for _, bind := range m.binds {
+		b.WriteString(halfPrefix(bind.half))
+		if bind.button >= 0 {
+			b.WriteString(bind.button.String())
+		} else {
+			b.WriteString(bind.axis.String())
+		}
+		b.WriteString(":" + halfPrefix(bind.src.half))
+		switch bind.src.kind {
+		case 'h':
+			fmt.Fprintf(&b, "h%d.%d", bind.src.index, bind.src.hat)
+		default:
+			fmt.Fprintf(&b, "%c%d", bind.src.kind, bind.src.index)
+		}
+		if bind.src.invert {
+			b.WriteString("~")
+		}
+		b.WriteString(",")
+	}
+	b.WriteString("platform:Linux,")
+	return b.String()
+}
+
+func halfPrefix(half int) string {
+	switch half {
+	case 1:
+		return "+"
+	case -1:
+		return "-"
+	}
+	return ""
+}
+
+// Return the SDL guid of a device: bus, vendor, product and version as
+// little endian 16 bit values, each padded to 32 bit, in hex
+func GUID(id ID) string {
+	var b [16]byte
+	binary.LittleEndian.PutUint16(b[0:], id.Bus)
+	binary.LittleEndian.PutUint16(b[4:], id.Vendor)
+	binary.LittleEndian.PutUint16(b[8:], id.Product)
+	binary.LittleEndian.PutUint16(b[12:], id.Version)
+	return hex.EncodeToString(b[:])
+}
+
+// A MappingDB holds gamepad mappings by guid
+type MappingDB struct {
+	mappings map[string]*Mapping
+}
+
+// Return an empty database. Devices without a mapping that follow the
+// Linux gamepad conventions get a default mapping.
+func NewMappingDB() *MappingDB {
+	return &MappingDB{mappings: map[string]*Mapping{}}
+}
+
+// Add mappings, one per line as in gamecontrollerdb.txt or the
+// SDL_GAMECONTROLLERCONFIG environment variable. Lines for platforms other
+// than Linux, and comments, are skipped. A mapping replaces one of the
+// same guid.
+func (db *MappingDB) Add(mappings string) error {
+	return db.Load(strings.NewReader(mappings))
+}
+
+// Add mappings read from r, eg. from gamecontrollerdb.txt. Lines that fail
+// to parse are skipped, and reported together after adding the others.
+func (db *MappingDB) Load(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	var bad []string
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if i := strings.Index(line, "platform:"); i >= 0 && !strings.HasPrefix(line[i+len("platform:"):], "Linux") {
+			continue
+		}
+		m, err := ParseMapping(line)
+		if err != nil {
+			bad = append(bad, fmt.Sprintf("line %d: %v", n, err))
+			continue
+		}
+		db.mappings[m.GUID] = m
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("fail to read gamepad mappings: %v", err)
+	}
+	if len(bad) > 0 {
+		return fmt.Errorf("fail to parse %d gamepad mappings, skipped: %s", len(bad), strings.Join(bad, "; "))
+	}
+	return nil
+}
+
+// Return the mapping of a device, matching its guid, or else ignoring the
+// version and the name crc of newer SDL guids, or nil. Of several loose
+// matches, the one with the lowest guid is returned.
+func (db *MappingDB) Lookup(dev *Device) *Mapping {
+	if db == nil {
+		return nil
+	}
+	guid := GUID(dev.ID)
+	if m, ok := db.mappings[guid]; ok {
+		return m
+	}
+	loose := func(g string) string { return g[:4] + g[8:24] } // without crc and version
+	var ret *Mapping
+	for g, m := range db.mappings {
+		if loose(g) == loose(guid) && (ret == nil || g < ret.GUID) {
+			ret = m
+		}
+	}
+	return ret
+}
@@ -0,0 +1,46 @@
+package input
+
+import (
+	"strings"
+	"testing"
+)
+
+// A bad line is skipped and reported, the others are still added
+func TestLoad(t *testing.T) {
+	db := NewMappingDB()
+	err := db.Add(strings.Join([]string{
+		"# comment",
+		"030000005e0400008e02000014010000,Xbox 360 Controller,a:b0,b:b1,leftx:a0,platform:Linux,",
+		"030000005e0400008e02000010010000,Broken,a:q0,platform:Linux,",
+		"nohex,Broken,a:b0,platform:Linux,",
+		"03000000790000000600000010010000,Generic USB Joystick,a:b2,b:b1,dpup:h0.1,platform:Linux,",
+		"03000000790000000600000000000000,Windows only,a:b0,platform:Windows,",
+	}, "\n"))
+	if err == nil || !strings.Contains(err.Error(), "line 3:") || !strings.Contains(err.Error(), "line 4:") {
+		t.Errorf("Load reports %v, want lines 3 and 4", err)
+	}
+	if len(db.mappings) != 2 {
+		t.Errorf("Load adds %d mappings, want 2", len(db.mappings))
+	}
+}
+
+// Of several loose matches, Lookup always returns the same one
+func TestLookupLoose(t *testing.T) {
+	db := NewMappingDB()
+	err := db.Add(`03001a2b5e0400008e02000000000000,Newer SDL,a:b0,platform:Linux,
+030000005e0400008e02000010010000,Older version,a:b0,platform:Linux,
+030000005e0400008e02000000020000,Other version,a:b0,platform:Linux,`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dev := &Device{ID: ID{Bus: 3, Vendor: 0x045e, Product: 0x028e, Version: 0x114}}
+	for i := 0; i < 20; i++ {
+		if m := db.Lookup(dev); m == nil || m.Name != "Other version" {
+			t.Fatalf("Lookup returns %v, want Other version", m)
+		}
+	}
+	db.mappings[GUID(dev.ID)] = &Mapping{GUID: GUID(dev.ID), Name: "Exact"}
+	if m := db.Lookup(dev); m == nil || m.Name != "Exact" {
+		t.Errorf("Lookup returns %v, want Exact", m)
+	}
+}
@@ -0,0 +1,180 @@
+package input
+
+import (
+	"encoding/binary"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+	"unsafe"
+)
+
+// A Virtual is a device created through /dev/uinput, to test programs
+// reading input without plugging in hardware. Set the fields and Create it.
+type Virtual struct {
+	Name   string
+	ID     ID
+	Keys   []Key
+	Rel    []uint16
+	Abs    map[uint16]AbsInfo
+	Direct bool // a touchscreen rather than a touchpad
+
+	file *os.File
+}
+
+// Return a virtual keyboard with the keys of a full keyboard
+func VirtualKeyboard(name string) *Virtual {
+	v := &Virtual{Name: name, ID: ID{Bus: 0x06, Vendor: 0xfeed, Product: 0x0001}}
+	for key := range keyNames {
+		if key < 0x100 {
+			v.Keys = append(v.Keys, key)
+		}
+	}
+	return v
+}
+
+// Return a virtual mouse with three buttons and a wheel
+func VirtualMouse(name string) *Virtual {
+	return &Virtual{
+		Name: name, ID: ID{Bus: 0x06, Vendor: 0xfeed, Product: 0x0002},
+		Keys: []Key{BtnLeft, BtnRight, BtnMiddle},
+		Rel:  []uint16{REL_X, REL_Y, REL_WHEEL},
+	}
+}
+
+// Return a virtual multitouch screen of w*h units and 10 slots, like the
+// official 7" touchscreen with w 800 and h 480
+func VirtualTouchscreen(name string, w, h int32) *Virtual {
+	return &Virtual{
+		Name: name, ID: ID{Bus: 0x06, Vendor: 0xfeed, Product: 0x0003},
+		Keys: []Key{BtnTouch},
+		Abs: map[uint16]AbsInfo{
+			ABS_X: {Maximum: w - 1}, ABS_Y: {Maximum: h - 1},
+			ABS_MT_SLOT: {Maximum: 9}, ABS_MT_TRACKING_ID: {Maximum: 65535},
+			ABS_MT_POSITION_X: {Maximum: w - 1}, ABS_MT_POSITION_Y: {Maximum: h - 1},
+		},
+		Direct: true,
+	}
+}
+
+// Create the device. Its evdev node appears in /dev/input shortly after.
+func (v *Virtual) Create() error {
+	f, err := os.OpenFile("/dev/uinput", os.O_WRONLY, 0)
+	if err != nil {
+		return fmt.Errorf("fail to open uinput: %v", err)
+	}
+	set := func(nr uintptr, value uint16) error {
+		return ioctlValue(f, ioc(iocWrite, 'U', nr, 4), uintptr(value))
+	}
+	setup := func() error {
+		for ev, on := range map[uint16]bool{EV_KEY: len(v.Keys) > 0, EV_REL: len(v.Rel) > 0, EV_ABS: len(v.Abs) > 0} {
+			if on {
+				if err := set(100, ev); err != nil {
+					return err
+				}
+			}
+		}
+		for _, key := range v.Keys {
+			if err := set(101, uint16(key)); err != nil {
+				return err
+			}
+		}
+		for _, code := range v.Rel {
+			if err := set(102, code); err != nil {
+				return err
+			}
+		}
+		for code := range v.Abs {
+			if err := set(103, code); err != nil {
+				return err
+			}
+		}
+		if v.Direct {
+			if err := set(110, INPUT_PROP_DIRECT); err != nil {
+				return err
+			}
+		}
+		// struct uinput_user_dev, understood by all kernels
+		dev := make([]byte, 80+8+4+4*4*ABS_CNT)
+		copy(dev[:79], v.Name)
+		binary.LittleEndian.PutUint16(dev[80:], v.ID.Bus)
+		binary.LittleEndian.PutUint16(dev[82:], v.ID.Vendor)
+		binary.LittleEndian.PutUint16(dev[84:], v.ID.Product)
+		binary.LittleEndian.PutUint16(dev[86:], v.ID.Version)
+		for code, info := range v.Abs {
+			for i, value := range []int32{info.Maximum, info.Minimum, info.Fuzz, info.Flat} {
+				binary.LittleEndian.PutUint32(dev[92+4*(i*ABS_CNT+int(code)):], uint32(value))
+			}
+		}
+		if _, err := f.Write(dev); err != nil {
+			return err
+		}
+		return ioctlValue(f, ioc(iocNone, 'U', 1, 0), 0)
+	}
+	if err := setup(); err != nil {
+		f.Close()
+		return fmt.Errorf("fail to create virtual device %q: %v", v.Name, err)
+	}
+	v.file = f
+	return nil
+}
+
+// Return the path of the evdev node of the created device, waiting up to
+// timeout for it to appear
+func (v *Virtual) Path(timeout time.Duration) (string, error) {
+	name := make([]byte, 64)
+	if err := ioctl(v.file, ioc(iocRead, 'U', 44, uintptr(len(name))), unsafe.Pointer(&name[0])); err != nil {
+		return "", fmt.Errorf("fail to get virtual device name: %v", err)
+	}
+	for i, c := range name {
+		if c == 0 {
+			name = name[:i]
+			break
+		}
+	}
+	deadline := time.Now().Add(timeout)
+	for {
+		nodes, _ := filepath.Glob(filepath.Join("/sys/devices/virtual/input", string(name), "event*"))
+		if len(nodes) > 0 {
+			path := filepath.Join("/dev/input", filepath.Base(nodes[0]))
+			if _, err := os.Stat(path); err == nil {
+				return path, nil
+			}
+		}
+		if time.Now().After(deadline) {
+			return "", fmt.Errorf("fail to find evdev node of virtual device %q", v.Name)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// Send an event. Readers see events up to the next Sync as one report.
+func (v *Virtual) Emit(evType, code uint16, value int32) error {
+	return WriteEvents(v.file, RawEvent{Type: evType, Code: code, Value: value})
+}
+
+// End a report
+func (v *Virtual) Sync() error {
+	return v.Emit(EV_SYN, SYN_REPORT, 0)
+}
+
+// Press and release a key
+func (v *Virtual) Tap(key Key) error {
+	return WriteEvents(v.file,
+		RawEvent{Type: EV_KEY, Code: uint16(key), Value: int32(KeyPress)},
+		RawEvent{Type: EV_SYN, Code: SYN_REPORT},
+		RawEvent{Type: EV_KEY, Code: uint16(key), Value: int32(KeyRelease)},
+		RawEvent{Type: EV_SYN, Code: SYN_REPORT},
+	)
+}
+
+// Destroy the device
+func (v *Virtual) Close() error {
+	if v.file == nil {
+		return nil
+	}
+	ioctlValue(v.file, ioc(iocNone, 'U', 2, 0), 0)
+	err := v.file.Close()
+	v.file = nil
+	return err
+}
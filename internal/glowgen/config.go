@@ -0,0 +1,126 @@
+package main
+
+// An api describes one generated binding package.
+type api struct {
+	Package   string   // go package name and output directory
+	Title     string   // human readable api name, for the package doc
+	Spec      string   // registry file, eg. gl.xml
+	API       string   // registry api name, eg. gles2
+	Version   string   // highest feature number to include
+	RemExt    string   // extensions to exclude..
+	AddExt    string   // ..unless they match this
+	Refpages  string   // refpages directory, empty for no docs
+	Prefix    string   // C prefix stripped from command names
+	Errors    []string // enums that make up the ErrorString table
+	ErrorType string   // go type of error codes
+	Notice    string   // license notice for the generated docs
+	Cgo       []string // cgo directives
+	Entry     string   // calling convention macro of function pointers
+	Preamble  string   // C definitions ahead of the typedefs
+	Tracked   []string // functions that call a hand written track<GoName> hook, when tracking is on
+	Counted   []string // void functions that call a hand written countDraw hook, always
+}
+
+var cgoBroadcom = []string{
+	"linux,arm  CFLAGS: -I/opt/vc/include",
+	"linux,arm LDFLAGS: -L/opt/vc/lib",
+}
+
+// gles2 functions that create, delete or size objects, for the leak tracker
+// in gles2/track.go
+var glTracked = []string{
+	"GenBuffers", "DeleteBuffers", "BindBuffer", "BufferData",
+	"GenTextures", "DeleteTextures", "BindTexture", "ActiveTexture",
+	"TexImage2D", "CompressedTexImage2D", "CopyTexImage2D", "GenerateMipmap",
+	"GenFramebuffers", "DeleteFramebuffers",
+	"GenRenderbuffers", "DeleteRenderbuffers", "BindRenderbuffer", "RenderbufferStorage",
+	"CreateShader", "DeleteShader", "CreateProgram", "DeleteProgram",
+}
+
+// gles2 functions counted by gles2.DrawCalls
+var glCounted = []string{"DrawArrays", "DrawElements"}
+
+var apis = map[string]api{
+
+	"gles2": {
+		Package:   "gles2",
+		Title:     "OpenGL",
+		Spec:      "gl.xml",
+		API:       "gles2",
+		Version:   "2.0",
+		RemExt:    "GL_EXT_win32_|GL_APPLE_|GL_QCOM_|GL_INTEL_|GL_NVX_|GL_NV_|GL_AMD_|GL_KHR_|GL_EXT_|",
+		AddExt:    "GL_EXT_discard_framebuffer|GL_EXT_debug_marker|GL_EXT_texture_format_BGRA8888|GL_OES_compressed_ETC1_RGB8_texture|GL_OES_compressed_paletted_texture|GL_OES_depth24|GL_OES_depth32|GL_OES_EGL_image|GL_OES_EGL_image_external|GL_OES_element_index_uint|GL_OES_mapbuffer|GL_OES_packed_depth_stencil|GL_OES_rgb8_rgba8|GL_OES_texture_npot|GL_OES_vertex_array_object|GL_OES_vertex_half_float",
+		Refpages:  "es2.0",
+		Prefix:    "gl",
+		ErrorType: "uint32",
+		Errors:    []string{"NO_ERROR", "INVALID_ENUM", "INVALID_VALUE", "INVALID_OPERATION", "INVALID_FRAMEBUFFER_OPERATION", "OUT_OF_MEMORY"},
+		Notice:    refpagesNotice,
+		Cgo:       cgoBroadcom,
+		Entry:     "APIENTRYP",
+		Preamble:  glPreamble,
+		Tracked:   glTracked,
+		Counted:   glCounted,
+	},
+
+	"egl": {
+		Package:   "egl",
+		Title:     "EGL",
+		Spec:      "egl.xml",
+		API:       "egl",
+		Version:   "1.4",
+		RemExt:    "EGL_ANDROID_|EGL_ANGLE_|EGL_ARM_|EGL_EXT_|EGL_HI_|EGL_IMG_|EGL_MESA_|EGL_NOK_|EGL_NV_|EGL_TIZEN_|EGL_KHR_|",
+		AddExt:    "^(EGL_KHR_image|EGL_KHR_image_base|EGL_KHR_image_pixmap|EGL_KHR_vg_parent_image|EGL_KHR_gl_texture_2D_image|EGL_KHR_gl_texture_cubemap_image|EGL_KHR_lock_surface|EGL_KHR_fence_sync|EGL_KHR_reusable_sync)$",
+		Prefix:    "egl",
+		ErrorType: "int32",
+		Errors:    []string{"SUCCESS", "NOT_INITIALIZED", "BAD_ACCESS", "BAD_ALLOC", "BAD_ATTRIBUTE", "BAD_CONTEXT", "BAD_CONFIG", "BAD_CURRENT_SURFACE", "BAD_DISPLAY", "BAD_SURFACE", "BAD_MATCH", "BAD_PARAMETER", "BAD_NATIVE_PIXMAP", "BAD_NATIVE_WINDOW", "CONTEXT_LOST"},
+		Notice:    registryNotice,
+		Cgo:       append(cgoBroadcom, "CFLAGS: -DEGL_NO_PLATFORM_SPECIFIC_TYPES"),
+		Entry:     "EGLAPIENTRYP",
+		Preamble:  eglPreamble,
+	},
+}
+
+const refpagesNotice = `// Copyright (c) 2010 Khronos Group.
+// This material may be distributed subject to the terms and conditions
+// set forth in the Open Publication License, v 1.0, 8 June 1999.
+// http://opencontent.org/openpub/.
+//
+// Copyright (c) 1991-2006 Silicon Graphics, Inc.
+// This document is licensed under the SGI Free Software B License.
+// For details, see http://oss.sgi.com/projects/FreeB.`
+
+const registryNotice = `// Copyright (c) 2013-2017 The Khronos Group Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a
+// copy of this software and/or associated documentation files (the
+// "Materials"), to deal in the Materials without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Materials, and to
+// permit persons to whom the Materials are furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be included
+// in all copies or substantial portions of the Materials.`
+
+const glPreamble = `// #if defined(_WIN32) && !defined(APIENTRY) && !defined(__CYGWIN__) && !defined(__SCITECH_SNAP__)
+// #ifndef WIN32_LEAN_AND_MEAN
+// #define WIN32_LEAN_AND_MEAN 1
+// #endif
+// #include <windows.h>
+// #endif
+// #ifndef APIENTRY
+// #define APIENTRY
+// #endif
+// #ifndef APIENTRYP
+// #define APIENTRYP APIENTRY *
+// #endif
+// #ifndef GLAPI
+// #define GLAPI extern
+// #endif`
+
+const eglPreamble = `// #ifndef EGLAPIENTRY
+// #define EGLAPIENTRY
+// #endif
+// #ifndef EGLAPIENTRYP
+// #define EGLAPIENTRYP EGLAPIENTRY *
+// #endif`
@@ -0,0 +1,253 @@
+// Command glowgen generates the gles2 and egl binding packages from the
+// Khronos XML API registries, in the style of Glow.
+//
+// It is run by go generate from within the package directory:
+//
+//	//go:generate go run ../internal/glowgen -api=gles2
+//
+// The registries and refpages are checked in under specs next to this file,
+// trimmed to the APIs and extensions generated, and pinned by their sha256
+// in specs.sum, so that regenerating is reproducible. With -fetch, they are
+// fetched again from the upstream commits pinned in upstream.sum, checked
+// against the sha256 pinned there, and trimmed; with -fetch -update, the
+// current upstream heads get pinned instead. With -check, nothing gets
+// written; instead glowgen fails if the checked-in files differ from what
+// it would generate.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+var (
+	apiFlag   = flag.String("api", "", "api to generate, eg. gles2 or egl")
+	outFlag   = flag.String("out", ".", "output directory")
+	specsFlag = flag.String("specs", "", "directory of registries and refpages (default <module>/internal/glowgen/specs)")
+	sumsFlag  = flag.String("sums", "", "file of pinned spec sums (default <module>/internal/glowgen/specs.sum)")
+	checkFlag = flag.Bool("check", false, "check that the output is up to date, write nothing")
+	upFlag    = flag.String("upstream", "", "file of pinned upstream urls and sums (default <module>/internal/glowgen/upstream.sum)")
+	fetchFlag = flag.Bool("fetch", false, "fetch and trim the specs from their pinned upstream commits")
+	updFlag   = flag.Bool("update", false, "accept changed specs, and pin their new sums and upstream heads")
+	onlyFlag  = flag.Bool("specsonly", false, "fetch and pin the specs, generate nothing")
+)
+
+// moduleRoot finds the directory of go.mod, starting from the working directory
+func moduleRoot() (string, error) {
+	dir, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	for {
+		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+			return dir, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", fmt.Errorf("fail to find go.mod")
+		}
+		dir = parent
+	}
+}
+
+// loadDocs maps command names to the purpose line of their refpage
+func loadDocs(dir string) (map[string]string, error) {
+	ret := map[string]string{}
+	if dir == "" {
+		return ret, nil
+	}
+	pages, err := filepath.Glob(filepath.Join(dir, "*.xml"))
+	if err != nil {
+		return nil, err
+	}
+	for _, page := range pages {
+		f, err := os.Open(page)
+		if err != nil {
+			return nil, err
+		}
+		names, purpose, err := parseRefpage(f)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("fail to parse %s: %s", page, err)
+		}
+		for _, name := range names {
+			ret[name] = purpose
+		}
+	}
+	return ret, nil
+}
+
+func render(tmpl *template.Template, data interface{}, gofmt bool) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+	if !gofmt {
+		return buf.Bytes(), nil
+	}
+	ret, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("fail to format %s: %s", tmpl.Name(), err)
+	}
+	return ret, nil
+}
+
+// generate returns the contents of all generated files of api a, by name
+func generate(a api, spec *Spec) (map[string][]byte, error) {
+	data := struct {
+		API  api
+		Spec *Spec
+	}{a, spec}
+	files := []struct {
+		name  string
+		tmpl  *template.Template
+		data  interface{}
+		gofmt bool
+	}{
+		{"package.go", packageTemplate, data, true},
+		{"conversions.go", conversionsTemplate, a, true},
+		{"procaddr.go", procaddrTemplate, a, true},
+		{"error_string.go", errorStringTemplate, a, true},
+		{"symbols.txt", symbolsTemplate, data, false},
+	}
+	ret := map[string][]byte{}
+	for _, file := range files {
+		buf, err := render(file.tmpl, file.data, file.gofmt)
+		if err != nil {
+			return nil, err
+		}
+		ret[file.name] = buf
+	}
+	return ret, nil
+}
+
+// generateFrom parses the registry and refpages of api a in the specs dir,
+// and returns the contents of its generated files, by name
+func generateFrom(specs string, a api) (map[string][]byte, error) {
+	docs := map[string]string{}
+	if a.Refpages != "" {
+		var err error
+		if docs, err = loadDocs(filepath.Join(specs, a.Refpages)); err != nil {
+			return nil, err
+		}
+	}
+	f, err := os.Open(filepath.Join(specs, a.Spec))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	spec, err := parseSpec(f, a, docs)
+	if err != nil {
+		return nil, err
+	}
+	if err := spec.check(); err != nil {
+		return nil, err
+	}
+	return generate(a, spec)
+}
+
+func run() error {
+	a, ok := apis[*apiFlag]
+	if !ok {
+		var names []string
+		for name := range apis {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return fmt.Errorf("unknown api %q, use one of %s", *apiFlag, strings.Join(names, ", "))
+	}
+
+	root, err := moduleRoot()
+	if err != nil {
+		return err
+	}
+	specs, sumsPath, upPath := *specsFlag, *sumsFlag, *upFlag
+	if specs == "" {
+		specs = filepath.Join(root, "internal", "glowgen", "specs")
+	}
+	if sumsPath == "" {
+		sumsPath = filepath.Join(root, "internal", "glowgen", "specs.sum")
+	}
+	if upPath == "" {
+		upPath = filepath.Join(root, "internal", "glowgen", "upstream.sum")
+	}
+
+	if *fetchFlag {
+		upstream, err := readPins(upPath)
+		if err != nil {
+			return err
+		}
+		if err := fetchSpecs(specs, a, upstream, *updFlag); err != nil {
+			return err
+		}
+		if err := upstream.write(upPath); err != nil {
+			return err
+		}
+	}
+	inputs, err := specFiles(specs, a)
+	if err != nil {
+		return err
+	}
+	pinned, err := readSums(sumsPath)
+	if err != nil {
+		return err
+	}
+	changed, err := pinned.verify(specs, inputs, *updFlag)
+	if err != nil {
+		return err
+	}
+	if changed && !*checkFlag {
+		if err := pinned.write(sumsPath); err != nil {
+			return err
+		}
+	}
+	if *onlyFlag {
+		return nil
+	}
+
+	files, err := generateFrom(specs, a)
+	if err != nil {
+		return err
+	}
+
+	var names []string
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	stale := false
+	for _, name := range names {
+		path := filepath.Join(*outFlag, name)
+		if *checkFlag {
+			old, err := ioutil.ReadFile(path)
+			if err != nil || !bytes.Equal(old, files[name]) {
+				fmt.Fprintf(os.Stderr, "%s is out of date\n", filepath.Join(a.Package, name))
+				stale = true
+			}
+			continue
+		}
+		if err := ioutil.WriteFile(path, files[name], 0644); err != nil {
+			return err
+		}
+	}
+	if stale {
+		return fmt.Errorf("%s is out of date, run go generate ./%s", a.Package, a.Package)
+	}
+	return nil
+}
+
+func main() {
+	flag.Parse()
+	if err := run(); err != nil {
+		fmt.Fprintf(os.Stderr, "glowgen: %s\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+// TestUpToDate fails if the checked-in bindings differ from what the pinned
+// specs generate, or if the specs differ from their pinned sums
+func TestUpToDate(t *testing.T) {
+	pinned, err := readSums("specs.sum")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, a := range apis {
+		inputs, err := specFiles("specs", a)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if changed, err := pinned.verify("specs", inputs, false); err != nil {
+			t.Errorf("%s: %s", name, err)
+		} else if changed {
+			t.Errorf("%s: specs are not pinned in specs.sum, run make specs", name)
+		}
+		files, err := generateFrom("specs", a)
+		if err != nil {
+			t.Fatalf("%s: %s", name, err)
+		}
+		for file, want := range files {
+			path := filepath.Join("..", "..", a.Package, file)
+			got, err := ioutil.ReadFile(path)
+			if err != nil {
+				t.Errorf("%s: %s", name, err)
+				continue
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("%s is out of date, run go generate ./%s", path, a.Package)
+			}
+		}
+	}
+}
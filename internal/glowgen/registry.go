@@ -0,0 +1,403 @@
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"html"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// xml layout of a khronos api registry, eg. gl.xml or egl.xml
+
+type xmlRegistry struct {
+	Types      []xmlType      `xml:"types>type"`
+	Enums      []xmlEnums     `xml:"enums"`
+	Commands   []xmlCommand   `xml:"commands>command"`
+	Features   []xmlFeature   `xml:"feature"`
+	Extensions []xmlExtension `xml:"extensions>extension"`
+}
+
+type xmlType struct {
+	Name     string `xml:"name,attr"`
+	API      string `xml:"api,attr"`
+	Requires string `xml:"requires,attr"`
+	Inner    string `xml:",innerxml"`
+}
+
+type xmlEnums struct {
+	Enums []xmlEnum `xml:"enum"`
+}
+
+type xmlEnum struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+	API   string `xml:"api,attr"`
+}
+
+type xmlCommand struct {
+	Proto  xmlInner   `xml:"proto"`
+	Params []xmlInner `xml:"param"`
+}
+
+type xmlInner struct {
+	Inner string `xml:",innerxml"`
+}
+
+type xmlFeature struct {
+	API      string       `xml:"api,attr"`
+	Name     string       `xml:"name,attr"`
+	Number   string       `xml:"number,attr"`
+	Requires []xmlRequire `xml:"require"`
+	Removes  []xmlRequire `xml:"remove"`
+}
+
+type xmlExtension struct {
+	Name      string       `xml:"name,attr"`
+	Supported string       `xml:"supported,attr"`
+	Requires  []xmlRequire `xml:"require"`
+}
+
+type xmlRequire struct {
+	API      string    `xml:"api,attr"`
+	Enums    []xmlName `xml:"enum"`
+	Commands []xmlName `xml:"command"`
+}
+
+type xmlName struct {
+	Name string `xml:"name,attr"`
+}
+
+// the selected subset of a registry, ready for the templates
+
+type Spec struct {
+	Typedefs  []string
+	Enums     []Enum
+	Functions []Function
+}
+
+type Enum struct {
+	GoName string
+	Value  string
+}
+
+type Function struct {
+	Name       string // C name, eg. glActiveTexture
+	GoName     string // eg. ActiveTexture
+	Doc        string
+	Core       bool   // part of a feature, not only of an extension
+	Tracked    bool   // calls track<GoName> with its arguments and result
+	Counted    bool   // calls countDraw
+	Requiredby string // feature or extension that pulled this in
+	Return     Param
+	Params     []Param
+}
+
+type Param struct {
+	Name     string // C name
+	Const    string // "const " or ""
+	Base     string // base C type, eg. GLenum or void
+	Pointers string // eg. "*", "**" or "*const*"
+}
+
+var tagRegexp = regexp.MustCompile(`<[^>]*>`)
+
+// text returns the character data of an innerxml fragment
+func text(inner string) string {
+	inner = strings.Replace(inner, "<apientry/>", "APIENTRY", -1)
+	inner = strings.Replace(inner, "<apientry></apientry>", "APIENTRY", -1)
+	return html.UnescapeString(tagRegexp.ReplaceAllString(inner, ""))
+}
+
+var nameRegexp = regexp.MustCompile(`<name>(.*?)</name>`)
+
+// parseParam splits a proto or param fragment into name and C type
+func parseParam(inner string) (Param, error) {
+	m := nameRegexp.FindStringSubmatchIndex(inner)
+	if m == nil {
+		return Param{}, fmt.Errorf("fail to find name in %q", inner)
+	}
+	ret := Param{Name: inner[m[2]:m[3]]}
+	decl := strings.TrimSpace(text(inner[:m[0]]))
+	if strings.HasPrefix(decl, "const ") {
+		ret.Const = "const "
+		decl = strings.TrimSpace(decl[len("const "):])
+	}
+	i := strings.IndexAny(decl, " *")
+	if i < 0 {
+		ret.Base = decl
+	} else {
+		ret.Base = decl[:i]
+		ret.Pointers = strings.Replace(decl[i:], " ", "", -1)
+	}
+	return ret, nil
+}
+
+// CType returns the C type as glow prints it, eg. "const GLchar *" or "GLenum "
+func (p Param) CType() string {
+	return p.Const + p.Base + " " + p.Pointers
+}
+
+// CDecl returns the C parameter declaration, eg. "GLenum  target"
+func (p Param) CDecl() string {
+	return p.CType() + " " + p.Name
+}
+
+func (p Param) IsVoid() bool {
+	return p.Base == "void" && p.Pointers == ""
+}
+
+// matches reports whether an api attribute applies to api
+func matches(attr, api string) bool {
+	if attr == "" {
+		return true
+	}
+	for _, a := range strings.Split(attr, "|") {
+		if a == api {
+			return true
+		}
+	}
+	return false
+}
+
+// versionLE compares dotted feature numbers
+func versionLE(a, b string) bool {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		var x, y int
+		if i < len(as) {
+			x, _ = strconv.Atoi(as[i])
+		}
+		if i < len(bs) {
+			y, _ = strconv.Atoi(bs[i])
+		}
+		if x != y {
+			return x < y
+		}
+	}
+	return true
+}
+
+// goEnumValue turns a registry value into a go constant, or "" to skip it
+func goEnumValue(value string) string {
+	switch {
+	case strings.HasPrefix(value, "EGL_CAST(EGLint,"):
+		return strings.TrimSuffix(strings.TrimPrefix(value, "EGL_CAST(EGLint,"), ")")
+	case strings.Contains(value, "("):
+		return "" // casts to handle types, eg. EGL_NO_DISPLAY
+	}
+	return strings.TrimRight(value, "uUlL")
+}
+
+// A selection names the parts of a registry that make up an api
+type selection struct {
+	enums      map[string]bool
+	commands   map[string]string // command name to the feature or extension that pulled it in
+	core       map[string]bool   // commands that are part of a feature
+	features   []xmlFeature
+	extensions []xmlExtension
+}
+
+// selectAPI picks the features up to the version of api a, and the
+// extensions its filters let through
+func selectAPI(reg *xmlRegistry, a api) (*selection, error) {
+	remExt, err := regexp.Compile(a.RemExt)
+	if err != nil {
+		return nil, err
+	}
+	addExt, err := regexp.Compile(a.AddExt)
+	if err != nil {
+		return nil, err
+	}
+
+	sel := &selection{
+		enums:    map[string]bool{},
+		commands: map[string]string{},
+		core:     map[string]bool{},
+	}
+
+	require := func(reqs []xmlRequire, by string, isCore bool) {
+		for _, req := range reqs {
+			if !matches(req.API, a.API) {
+				continue
+			}
+			for _, e := range req.Enums {
+				sel.enums[e.Name] = true
+			}
+			for _, c := range req.Commands {
+				if _, ok := sel.commands[c.Name]; !ok {
+					sel.commands[c.Name] = by
+				}
+				if isCore {
+					sel.core[c.Name] = true
+				}
+			}
+		}
+	}
+
+	for _, f := range reg.Features {
+		if f.API == a.API && versionLE(f.Number, a.Version) {
+			sel.features = append(sel.features, f)
+			require(f.Requires, f.Name, true)
+		}
+	}
+	for _, f := range sel.features {
+		for _, rem := range f.Removes {
+			for _, e := range rem.Enums {
+				delete(sel.enums, e.Name)
+			}
+			for _, c := range rem.Commands {
+				delete(sel.commands, c.Name)
+			}
+		}
+	}
+	for _, x := range reg.Extensions {
+		if !matches(x.Supported, a.API) {
+			continue
+		}
+		if remExt.MatchString(x.Name) && !addExt.MatchString(x.Name) {
+			continue
+		}
+		sel.extensions = append(sel.extensions, x)
+		require(x.Requires, x.Name, false)
+	}
+	return sel, nil
+}
+
+// requiredTypes adds the types that the types in used require, recursively
+func requiredTypes(reg *xmlRegistry, a api, used map[string]bool) {
+	names := map[string]xmlType{}
+	for _, t := range reg.Types {
+		if !matches(t.API, a.API) {
+			continue
+		}
+		names[typeName(t)] = t
+	}
+	var todo []string
+	for name := range used {
+		todo = append(todo, name)
+	}
+	for len(todo) > 0 {
+		t, ok := names[todo[0]]
+		todo = todo[1:]
+		if ok && t.Requires != "" && !used[t.Requires] {
+			used[t.Requires] = true
+			todo = append(todo, t.Requires)
+		}
+	}
+}
+
+// parseSpec reads a registry and selects the enums, commands and types
+// that make up api a
+func parseSpec(r io.Reader, a api, docs map[string]string) (*Spec, error) {
+	tracked := map[string]bool{}
+	for _, name := range a.Tracked {
+		tracked[name] = true
+	}
+	counted := map[string]bool{}
+	for _, name := range a.Counted {
+		counted[name] = true
+	}
+	var reg xmlRegistry
+	if err := xml.NewDecoder(r).Decode(&reg); err != nil {
+		return nil, fmt.Errorf("fail to parse %s: %s", a.Spec, err)
+	}
+	sel, err := selectAPI(&reg, a)
+	if err != nil {
+		return nil, err
+	}
+
+	spec := &Spec{}
+	enumPrefix := strings.ToUpper(a.Prefix) + "_"
+	seen := map[string]bool{}
+	for _, group := range reg.Enums {
+		for _, e := range group.Enums {
+			if !sel.enums[e.Name] || seen[e.Name] || !matches(e.API, a.API) {
+				continue
+			}
+			value := goEnumValue(e.Value)
+			if value == "" {
+				continue
+			}
+			seen[e.Name] = true
+			spec.Enums = append(spec.Enums, Enum{GoName: strings.TrimPrefix(e.Name, enumPrefix), Value: value})
+		}
+	}
+	sort.Slice(spec.Enums, func(i, j int) bool { return spec.Enums[i].GoName < spec.Enums[j].GoName })
+
+	ptypes := map[string]bool{}
+	for _, c := range reg.Commands {
+		proto, err := parseParam(c.Proto.Inner)
+		if err != nil {
+			return nil, err
+		}
+		by, ok := sel.commands[proto.Name]
+		if !ok {
+			continue
+		}
+		fun := Function{
+			Name:       proto.Name,
+			GoName:     strings.TrimPrefix(proto.Name, a.Prefix),
+			Tracked:    tracked[strings.TrimPrefix(proto.Name, a.Prefix)],
+			Counted:    counted[strings.TrimPrefix(proto.Name, a.Prefix)],
+			Doc:        docs[proto.Name],
+			Core:       sel.core[proto.Name],
+			Requiredby: by,
+			Return:     proto,
+		}
+		ptypes[proto.Base] = true
+		for _, p := range c.Params {
+			param, err := parseParam(p.Inner)
+			if err != nil {
+				return nil, err
+			}
+			ptypes[param.Base] = true
+			fun.Params = append(fun.Params, param)
+		}
+		spec.Functions = append(spec.Functions, fun)
+	}
+	sort.Slice(spec.Functions, func(i, j int) bool { return spec.Functions[i].GoName < spec.Functions[j].GoName })
+
+	// include the used types, and whatever they require, in registry order
+	requiredTypes(&reg, a, ptypes)
+	for _, t := range reg.Types {
+		if !matches(t.API, a.API) || !ptypes[typeName(t)] {
+			continue
+		}
+		if decl := strings.TrimSpace(text(t.Inner)); decl != "" {
+			spec.Typedefs = append(spec.Typedefs, decl)
+		}
+	}
+
+	return spec, nil
+}
+
+// typeName returns the name of a type, given either as attribute or as child
+func typeName(t xmlType) string {
+	if t.Name != "" {
+		return t.Name
+	}
+	if m := nameRegexp.FindStringSubmatch(t.Inner); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+// parseRefpage returns the command names and the purpose line of a refpage
+func parseRefpage(r io.Reader) ([]string, string, error) {
+	var page struct {
+		Names   []string `xml:"refnamediv>refname"`
+		Purpose xmlInner `xml:"refnamediv>refpurpose"`
+	}
+	dec := xml.NewDecoder(r)
+	dec.Strict = false
+	dec.Entity = xml.HTMLEntity
+	if err := dec.Decode(&page); err != nil {
+		return nil, "", err
+	}
+	purpose := strings.TrimSpace(strings.Replace(text(page.Purpose.Inner), "\n", " ", -1))
+	return page.Names, purpose, nil
+}
@@ -0,0 +1,293 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// An upstream is where a registry or refpages directory is fetched from
+type upstream struct {
+	Repo string // github repository, eg. KhronosGroup/OpenGL-Registry
+	Path string // path of the file or directory in the repository
+}
+
+var upstreams = map[string]upstream{
+	"gl.xml":  {"KhronosGroup/OpenGL-Registry", "xml/gl.xml"},
+	"egl.xml": {"KhronosGroup/EGL-Registry", "api/egl.xml"},
+	"es2.0":   {"KhronosGroup/OpenGL-Refpages", "es2.0"},
+}
+
+const (
+	rawURL      = "https://raw.githubusercontent.com/%s/%s/%s"
+	commitsURL  = "https://api.github.com/repos/%s/commits/HEAD"
+	contentsURL = "https://api.github.com/repos/%s/contents/%s?ref=%s"
+)
+
+func get(url, accept string) ([]byte, error) {
+	fmt.Fprintf(os.Stderr, "fetch %s\n", url)
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	rsp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer rsp.Body.Close()
+	if rsp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fail to fetch %s: %s", url, rsp.Status)
+	}
+	return ioutil.ReadAll(rsp.Body)
+}
+
+// headCommit returns the commit that the default branch of repo is at
+func headCommit(repo string) (string, error) {
+	buf, err := get(fmt.Sprintf(commitsURL, repo), "application/vnd.github.sha")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(buf)), nil
+}
+
+// A pin records the upstream url, at a fixed commit, that a spec file is
+// trimmed from, and the sha256 of the untrimmed file
+type pin struct {
+	Sum string
+	URL string
+}
+
+// pins maps spec files, relative to the specs dir, to their upstream
+type pins map[string]pin
+
+func readPins(path string) (pins, error) {
+	ret := pins{}
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return ret, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 3 {
+			ret[fields[1]] = pin{Sum: fields[0], URL: fields[2]}
+		}
+	}
+	return ret, scanner.Err()
+}
+
+func (p pins) write(path string) error {
+	var names []string
+	for name := range p {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var buf strings.Builder
+	for _, name := range names {
+		fmt.Fprintf(&buf, "%s  %s  %s\n", p[name].Sum, name, p[name].URL)
+	}
+	return ioutil.WriteFile(path, []byte(buf.String()), 0644)
+}
+
+// fetch downloads the upstream of spec file name from url, and checks it
+// against its pin. Unpinned files, or all files if update is set, get
+// pinned to url and their sum.
+func (p pins) fetch(name, url string, update bool) ([]byte, error) {
+	buf, err := get(url, "")
+	if err != nil {
+		return nil, err
+	}
+	h := sha256.Sum256(buf)
+	sum := hex.EncodeToString(h[:])
+	if old, ok := p[name]; ok && !update && old.Sum != sum {
+		return nil, fmt.Errorf("fail to verify %s: sha256 %s, pinned %s (rerun with -update to accept)", url, sum, old.Sum)
+	}
+	p[name] = pin{Sum: sum, URL: url}
+	return buf, nil
+}
+
+// commit returns the upstream commit that the files under prefix are
+// pinned to, or the current head of repo if there are none or update is set
+func (p pins) commit(prefix, repo string, update bool) (string, error) {
+	if !update {
+		for name, pin := range p {
+			if name == prefix || strings.HasPrefix(name, prefix+"/") {
+				parts := strings.SplitN(strings.TrimPrefix(pin.URL, "https://raw.githubusercontent.com/"+repo+"/"), "/", 2)
+				return parts[0], nil
+			}
+		}
+	}
+	return headCommit(repo)
+}
+
+// fetchSpecs fetches the registry and refpages of api a from their pinned
+// upstream commits, checks them against their pinned sums, and writes them
+// trimmed to a into dir. With update, it pins the current upstream heads.
+func fetchSpecs(dir string, a api, pinned pins, update bool) error {
+	up := upstreams[a.Spec]
+	commit, err := pinned.commit(a.Spec, up.Repo, update)
+	if err != nil {
+		return err
+	}
+	buf, err := pinned.fetch(a.Spec, fmt.Sprintf(rawURL, up.Repo, commit, up.Path), update)
+	if err != nil {
+		return err
+	}
+	if buf, err = trimRegistry(bytes.NewReader(buf), a); err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, a.Spec), buf, 0644); err != nil {
+		return err
+	}
+	if a.Refpages == "" {
+		return nil
+	}
+
+	up = upstreams[a.Refpages]
+	if commit, err = pinned.commit(a.Refpages, up.Repo, update); err != nil {
+		return err
+	}
+	buf, err = get(fmt.Sprintf(contentsURL, up.Repo, up.Path, commit), "")
+	if err != nil {
+		return err
+	}
+	var list []struct {
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal(buf, &list); err != nil {
+		return err
+	}
+	pages := filepath.Join(dir, a.Refpages)
+	if err := os.RemoveAll(pages + ".part"); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(pages+".part", 0755); err != nil {
+		return err
+	}
+	fetched := map[string]bool{}
+	for _, item := range list {
+		if !strings.HasPrefix(item.Name, "gl") || !strings.HasSuffix(item.Name, ".xml") {
+			continue
+		}
+		name := a.Refpages + "/" + item.Name
+		fetched[name] = true
+		buf, err := pinned.fetch(name, fmt.Sprintf(rawURL, up.Repo, commit, up.Path+"/"+item.Name), update)
+		if err != nil {
+			return err
+		}
+		if buf, err = trimRefpage(buf); err != nil {
+			return fmt.Errorf("fail to trim %s: %s", name, err)
+		}
+		if err := ioutil.WriteFile(filepath.Join(pages+".part", item.Name), buf, 0644); err != nil {
+			return err
+		}
+	}
+	for name := range pinned {
+		if strings.HasPrefix(name, a.Refpages+"/") && !fetched[name] {
+			delete(pinned, name)
+		}
+	}
+	if err := os.RemoveAll(pages); err != nil {
+		return err
+	}
+	return os.Rename(pages+".part", pages)
+}
+
+// specFiles lists the input files of api a, relative to the specs dir
+func specFiles(dir string, a api) ([]string, error) {
+	ret := []string{a.Spec}
+	if a.Refpages != "" {
+		pages, err := filepath.Glob(filepath.Join(dir, a.Refpages, "*.xml"))
+		if err != nil {
+			return nil, err
+		}
+		for _, page := range pages {
+			ret = append(ret, filepath.ToSlash(filepath.Join(a.Refpages, filepath.Base(page))))
+		}
+	}
+	return ret, nil
+}
+
+// A sums file pins the input files by their sha256, like go.sum
+type sums map[string]string
+
+func readSums(path string) (sums, error) {
+	ret := sums{}
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return ret, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 {
+			ret[fields[1]] = fields[0]
+		}
+	}
+	return ret, scanner.Err()
+}
+
+func (s sums) write(path string) error {
+	var names []string
+	for name := range s {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var buf strings.Builder
+	for _, name := range names {
+		fmt.Fprintf(&buf, "%s  %s\n", s[name], name)
+	}
+	return ioutil.WriteFile(path, []byte(buf.String()), 0644)
+}
+
+func hashFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// verify checks the input files against the pinned sums. Files that are
+// not pinned yet get added; mismatches are errors unless update is set.
+func (s sums) verify(dir string, files []string, update bool) (changed bool, err error) {
+	for _, name := range files {
+		sum, err := hashFile(filepath.Join(dir, name))
+		if err != nil {
+			return changed, err
+		}
+		pinned, ok := s[name]
+		switch {
+		case !ok || update && pinned != sum:
+			s[name] = sum
+			changed = true
+		case pinned != sum:
+			return changed, fmt.Errorf("fail to verify %s: sha256 %s, pinned %s (rerun with -update to accept)", name, sum, pinned)
+		}
+	}
+	return changed, nil
+}
@@ -0,0 +1,283 @@
+package main
+
+import (
+	"strings"
+	"text/template"
+)
+
+const generatedBy = "// Code generated by glowgen. DO NOT EDIT.\n"
+
+var funcs = template.FuncMap{
+	"upper": strings.ToUpper,
+	"cparams": func(params []Param) string {
+		var ret []string
+		for _, p := range params {
+			ret = append(ret, p.CDecl())
+		}
+		return strings.Join(ret, ", ")
+	},
+	"cnames": func(params []Param) string {
+		var ret []string
+		for _, p := range params {
+			ret = append(ret, p.Name)
+		}
+		return strings.Join(ret, ", ")
+	},
+	"goparams": func(params []Param) string {
+		var ret []string
+		for _, p := range params {
+			ret = append(ret, p.GoDecl())
+		}
+		return strings.Join(ret, ", ")
+	},
+	"gonames": func(params []Param) string {
+		var ret []string
+		for _, p := range params {
+			ret = append(ret, p.GoName())
+		}
+		return strings.Join(ret, ", ")
+	},
+	"cargs": func(params []Param) string {
+		var ret []string
+		for _, p := range params {
+			ret = append(ret, ", "+p.CArg())
+		}
+		return strings.Join(ret, "")
+	},
+	"gotype": func(p Param) string {
+		typ, _ := p.GoType()
+		return typ
+	},
+}
+
+var packageTemplate = template.Must(template.New("package").Funcs(funcs).Parse(generatedBy + `
+{{.API.Notice}}
+
+// Package {{.API.Package}} implements Go bindings to {{.API.Title}}.
+//
+// This package was generated from the Khronos XML API registry by glowgen,
+// a cut-down port of Glow (https://github.com/go-gl/glow).
+package {{.API.Package}}
+
+{{range .API.Cgo}}// #cgo {{.}}
+{{end}}//
+{{.API.Preamble}}
+{{range .Spec.Typedefs}}// {{.}}
+{{end}}{{range .Spec.Functions}}// typedef {{.Return.CType}} ({{$.API.Entry}} GP{{upper .GoName}})({{cparams .Params}});
+{{end}}{{range .Spec.Functions}}// static {{.Return.CType}} glow{{.GoName}}(GP{{upper .GoName}} fnptr{{if .Params}}, {{cparams .Params}}{{end}}) {
+//   {{if not .Return.IsVoid}}return {{end}}(*fnptr)({{cnames .Params}});
+// }
+{{end}}import "C"
+import (
+	"errors"
+	"unsafe"
+)
+
+const (
+{{range .Spec.Enums}}	{{.GoName}} = {{.Value}}
+{{end}})
+
+var (
+{{range .Spec.Functions}}	gp{{.GoName}} C.GP{{upper .GoName}}
+{{end}})
+
+// Helper functions
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+{{range .Spec.Functions}}{{if .Doc}}
+// {{.Doc}}{{end}}
+func {{.GoName}}({{goparams .Params}}) {{gotype .Return}} {
+{{- if .Return.IsVoid}}
+	C.glow{{.GoName}}(gp{{.GoName}}{{cargs .Params}})
+{{- if .Counted}}
+	countDraw()
+{{- end}}
+{{- if .Tracked}}
+	if Tracking() {
+		track{{.GoName}}({{gonames .Params}})
+	}
+{{- end}}
+{{- else}}
+	ret := C.glow{{.GoName}}(gp{{.GoName}}{{cargs .Params}})
+{{- if .Tracked}}
+	if Tracking() {
+		track{{.GoName}}({{gonames .Params}}{{if .Params}}, {{end}}{{.Return.GoReturn}})
+	}
+{{- end}}
+	return {{.Return.GoReturn}}
+{{- end}}
+}{{end}}
+
+// Init initializes the {{.API.Title}} bindings by loading the function pointers (for
+// each {{.API.Title}} function) with getProcAddress, which piglet does not provide.
+//
+// Call InitWithProcAddrFunc(piglet.GetProcAddress) instead, always after
+// calling piglet.MakeCurrent() and always before calling any functions
+// exported by this package.
+func Init() error {
+	return InitWithProcAddrFunc(getProcAddress)
+}
+
+// InitWithProcAddrFunc intializes the package using the specified {{.API.Title}}
+// function pointer loading function. For more cases Init should be used
+// instead.
+func InitWithProcAddrFunc(getProcAddr func(name string) unsafe.Pointer) error {
+{{- range .Spec.Functions}}
+	gp{{.GoName}} = (C.GP{{upper .GoName}})(getProcAddr("{{.Name}}"))
+{{- if .Core}}
+	if gp{{.GoName}} == nil {
+		return errors.New("{{.Name}}")
+	}
+{{- end}}
+{{- end}}
+	return nil
+}
+`))
+
+var procaddrTemplate = template.Must(template.New("procaddr").Parse(generatedBy + `
+package {{.Package}}
+
+import "unsafe"
+
+// this is just to satisfy {{.Package}}.Init()! instead call:
+// {{.Package}}.InitWithProcAddrFunc( piglet.GetProcAddress )
+
+func getProcAddress(string) unsafe.Pointer {
+	panic("DO NOT CALL {{.Package}}.getProcAddress!! IT WILL CRASH!!")
+}
+`))
+
+var errorStringTemplate = template.Must(template.New("error_string").Parse(generatedBy + `
+package {{.Package}}
+
+// Return the name of an error code
+func ErrorString(error {{.ErrorType}}) string {
+	switch error {
+{{- range .Errors}}
+	case {{.}}:
+		return "{{.}}"
+{{- end}}
+	}
+	return "UNKNOWN"
+}
+`))
+
+var symbolsTemplate = template.Must(template.New("symbols").Parse(`# Code generated by glowgen. DO NOT EDIT.
+#
+# {{.API.Title}} entry points of package {{.API.Package}}, and the feature or
+# extension that requires them. Compare against the exports of the driver.
+{{range .Spec.Functions}}
+{{.Name}} {{.Requiredby}}{{end}}
+`))
+
+// conversions.go is the same for all packages
+var conversionsTemplate = template.Must(template.New("conversions").Parse(generatedBy + `
+package {{.Package}}
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"unsafe"
+)
+
+// #include <stdlib.h>
+import "C"
+
+// Ptr takes a slice or pointer (to a singular scalar value or the first
+// element of an array or slice) and returns its GL-compatible address.
+//
+// For example:
+//
+// 	var data []uint8
+// 	...
+// 	gl.TexImage2D(gl.TEXTURE_2D, ..., gl.UNSIGNED_BYTE, gl.Ptr(&data[0]))
+func Ptr(data interface{}) unsafe.Pointer {
+	if data == nil {
+		return unsafe.Pointer(nil)
+	}
+	var addr unsafe.Pointer
+	v := reflect.ValueOf(data)
+	switch v.Type().Kind() {
+	case reflect.Ptr:
+		e := v.Elem()
+		switch e.Kind() {
+		case
+			reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+			reflect.Float32, reflect.Float64:
+			addr = unsafe.Pointer(e.UnsafeAddr())
+		default:
+			panic(fmt.Errorf("unsupported pointer to type %s; must be a slice or pointer to a singular scalar value or the first element of an array or slice", e.Kind()))
+		}
+	case reflect.Uintptr:
+		addr = unsafe.Pointer(v.Pointer())
+	case reflect.Slice:
+		addr = unsafe.Pointer(v.Index(0).UnsafeAddr())
+	default:
+		panic(fmt.Errorf("unsupported type %s; must be a slice or pointer to a singular scalar value or the first element of an array or slice", v.Type()))
+	}
+	return addr
+}
+
+// PtrOffset takes a pointer offset and returns a GL-compatible pointer.
+// Useful for functions such as glVertexAttribPointer that take pointer
+// parameters indicating an offset rather than an absolute memory address.
+func PtrOffset(offset int) unsafe.Pointer {
+	ptr := uintptr(offset)
+	return *(*unsafe.Pointer)(unsafe.Pointer(&ptr))
+}
+
+// Str takes a null-terminated Go string and returns its GL-compatible address.
+// This function reaches into Go string storage in an unsafe way so the caller
+// must ensure the string is not garbage collected.
+func Str(str string) *uint8 {
+	if !strings.HasSuffix(str, "\x00") {
+		panic("str argument missing null terminator: " + str)
+	}
+	header := (*reflect.StringHeader)(unsafe.Pointer(&str))
+	return (*uint8)(unsafe.Pointer(header.Data))
+}
+
+// GoStr takes a null-terminated string returned by OpenGL and constructs a
+// corresponding Go string.
+func GoStr(cstr *uint8) string {
+	return C.GoString((*C.char)(unsafe.Pointer(cstr)))
+}
+
+// Strs takes a list of Go strings (with or without null-termination) and
+// returns their C counterpart.
+//
+// The returned free function must be called once you are done using the strings
+// in order to free the memory.
+//
+// If no strings are provided as a parameter this function will panic.
+func Strs(strs ...string) (cstrs **uint8, free func()) {
+	if len(strs) == 0 {
+		panic("Strs: expected at least 1 string")
+	}
+
+	// Allocate a contiguous array large enough to hold all the strings' contents.
+	n := 0
+	for i := range strs {
+		n += len(strs[i])
+	}
+	data := C.malloc(C.size_t(n))
+
+	// Copy all the strings into data.
+	dataSlice := (*[1 << 30]byte)(data)[:n:n]
+	css := make([]*uint8, len(strs)) // Populated with pointers to each string.
+	offset := 0
+	for i := range strs {
+		copy(dataSlice[offset:offset+len(strs[i])], strs[i][:]) // Copy strs[i] into proper data location.
+		css[i] = (*uint8)(unsafe.Pointer(&dataSlice[offset]))   // Set a pointer to it.
+		offset += len(strs[i])
+	}
+
+	return (**uint8)(&css[0]), func() { C.free(data) }
+}
+`))
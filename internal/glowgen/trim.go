@@ -0,0 +1,166 @@
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// attr formats an xml attribute, or nothing if value is empty
+func attr(name, value string) string {
+	if value == "" {
+		return ""
+	}
+	var buf bytes.Buffer
+	xml.EscapeText(&buf, []byte(value))
+	return fmt.Sprintf(" %s=\"%s\"", name, buf.String())
+}
+
+// writeRequires writes the require or remove blocks of a feature or
+// extension that apply to api, indented by indent
+func writeRequires(w io.Writer, indent, tag string, reqs []xmlRequire, api string) {
+	for _, req := range reqs {
+		if !matches(req.API, api) {
+			continue
+		}
+		fmt.Fprintf(w, "%s<%s%s>\n", indent, tag, attr("api", req.API))
+		for _, e := range req.Enums {
+			fmt.Fprintf(w, "%s  <enum%s/>\n", indent, attr("name", e.Name))
+		}
+		for _, c := range req.Commands {
+			fmt.Fprintf(w, "%s  <command%s/>\n", indent, attr("name", c.Name))
+		}
+		fmt.Fprintf(w, "%s</%s>\n", indent, tag)
+	}
+}
+
+// trimRegistry reduces an upstream registry to the types, enums, commands,
+// features and extensions of api a, in registry order, so that the copy
+// checked in under specs stays small and reviewable
+func trimRegistry(r io.Reader, a api) ([]byte, error) {
+	var reg xmlRegistry
+	if err := xml.NewDecoder(r).Decode(&reg); err != nil {
+		return nil, fmt.Errorf("fail to parse %s: %s", a.Spec, err)
+	}
+	sel, err := selectAPI(&reg, a)
+	if err != nil {
+		return nil, err
+	}
+
+	used := map[string]bool{}
+	var commands []xmlCommand
+	for _, c := range reg.Commands {
+		proto, err := parseParam(c.Proto.Inner)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := sel.commands[proto.Name]; !ok {
+			continue
+		}
+		commands = append(commands, c)
+		used[proto.Base] = true
+		for _, p := range c.Params {
+			param, err := parseParam(p.Inner)
+			if err != nil {
+				return nil, err
+			}
+			used[param.Base] = true
+		}
+	}
+	requiredTypes(&reg, a, used)
+
+	namespace := strings.ToUpper(a.Prefix)
+	var buf bytes.Buffer
+	buf.WriteString("<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n<registry>\n")
+
+	buf.WriteString("  <types>\n")
+	for _, t := range reg.Types {
+		if !matches(t.API, a.API) || !used[typeName(t)] {
+			continue
+		}
+		if t.Inner == "" {
+			fmt.Fprintf(&buf, "    <type%s%s%s/>\n", attr("name", t.Name), attr("api", t.API), attr("requires", t.Requires))
+			continue
+		}
+		fmt.Fprintf(&buf, "    <type%s%s%s>%s</type>\n", attr("name", t.Name), attr("api", t.API), attr("requires", t.Requires), t.Inner)
+	}
+	buf.WriteString("  </types>\n")
+
+	fmt.Fprintf(&buf, "  <enums%s>\n", attr("namespace", namespace))
+	seen := map[string]bool{}
+	for _, group := range reg.Enums {
+		for _, e := range group.Enums {
+			if !sel.enums[e.Name] || seen[e.Name] || !matches(e.API, a.API) {
+				continue
+			}
+			seen[e.Name] = true
+			fmt.Fprintf(&buf, "    <enum%s%s%s/>\n", attr("value", e.Value), attr("name", e.Name), attr("api", e.API))
+		}
+	}
+	buf.WriteString("  </enums>\n")
+
+	fmt.Fprintf(&buf, "  <commands%s>\n", attr("namespace", namespace))
+	for _, c := range commands {
+		fmt.Fprintf(&buf, "    <command>\n      <proto>%s</proto>\n", c.Proto.Inner)
+		for _, p := range c.Params {
+			fmt.Fprintf(&buf, "      <param>%s</param>\n", p.Inner)
+		}
+		buf.WriteString("    </command>\n")
+	}
+	buf.WriteString("  </commands>\n")
+
+	for _, f := range sel.features {
+		fmt.Fprintf(&buf, "  <feature%s%s%s>\n", attr("api", f.API), attr("name", f.Name), attr("number", f.Number))
+		writeRequires(&buf, "    ", "require", f.Requires, a.API)
+		writeRequires(&buf, "    ", "remove", f.Removes, a.API)
+		buf.WriteString("  </feature>\n")
+	}
+
+	buf.WriteString("  <extensions>\n")
+	for _, x := range sel.extensions {
+		fmt.Fprintf(&buf, "    <extension%s%s>\n", attr("name", x.Name), attr("supported", x.Supported))
+		writeRequires(&buf, "      ", "require", x.Requires, a.API)
+		buf.WriteString("    </extension>\n")
+	}
+	buf.WriteString("  </extensions>\n</registry>\n")
+	return buf.Bytes(), nil
+}
+
+const refpageHeader = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE refentry PUBLIC "-//OASIS//DTD DocBook MathML Module V1.1b1//EN" "http://www.oasis-open.org/docbook/xml/mathml/1.1CR1/dbmathml.dtd">
+`
+
+// trimRefpage reduces an upstream refpage to the names and purpose line
+// that the docs are generated from
+func trimRefpage(data []byte) ([]byte, error) {
+	var entry struct {
+		ID string `xml:"id,attr"`
+	}
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	dec.Strict = false
+	dec.Entity = xml.HTMLEntity
+	if err := dec.Decode(&entry); err != nil {
+		return nil, err
+	}
+	names, purpose, err := parseRefpage(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("fail to find refname")
+	}
+	var buf bytes.Buffer
+	buf.WriteString(refpageHeader)
+	fmt.Fprintf(&buf, "<refentry%s>\n  <refnamediv>\n", attr("id", entry.ID))
+	for _, name := range names {
+		buf.WriteString("    <refname>")
+		xml.EscapeText(&buf, []byte(name))
+		buf.WriteString("</refname>\n")
+	}
+	buf.WriteString("    <refpurpose>")
+	xml.EscapeText(&buf, []byte(purpose))
+	buf.WriteString("</refpurpose>\n  </refnamediv>\n</refentry>\n")
+	return buf.Bytes(), nil
+}
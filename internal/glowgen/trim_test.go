@@ -0,0 +1,219 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const upstreamRegistry = `<?xml version="1.0" encoding="UTF-8"?>
+<registry>
+  <comment>dropped</comment>
+  <types>
+    <type name="khrplatform">#include &lt;KHR/khrplatform.h&gt;</type>
+    <type>typedef unsigned int <name>GLenum</name>;</type>
+    <type requires="khrplatform">typedef khronos_float_t <name>GLfloat</name>;</type>
+    <type>typedef double <name>GLdouble</name>;</type>
+    <type api="gles1">typedef int <name>GLfixed</name>;</type>
+  </types>
+  <enums namespace="GL" group="SpecialNumbers" vendor="ARB">
+    <enum value="0x0BE2" name="GL_BLEND"/>
+    <enum value="0x0B71" name="GL_DEPTH_TEST"/>
+    <enum value="0x80E1" name="GL_BGRA_EXT"/>
+    <enum value="0x8D61" name="GL_HALF_FLOAT_OES"/>
+    <enum value="0x84F2" name="GL_ALL_COMPLETED_NV"/>
+    <enum value="0xFFFFFFFFu" name="GL_TIMEOUT_IGNORED" api="gles2"/>
+  </enums>
+  <commands namespace="GL">
+    <command>
+      <proto>void <name>glEnable</name></proto>
+      <param group="EnableCap"><ptype>GLenum</ptype> <name>cap</name></param>
+      <glx type="render" opcode="139"/>
+    </command>
+    <command>
+      <proto>void <name>glDepthRange</name></proto>
+      <param><ptype>GLdouble</ptype> <name>n</name></param>
+      <param><ptype>GLdouble</ptype> <name>f</name></param>
+    </command>
+    <command>
+      <proto>void <name>glLineWidth</name></proto>
+      <param><ptype>GLfloat</ptype> <name>width</name></param>
+    </command>
+    <command>
+      <proto>void <name>glFinishFenceNV</name></proto>
+      <param><ptype>GLenum</ptype> <name>fence</name></param>
+    </command>
+  </commands>
+  <feature api="gl" name="GL_VERSION_1_0" number="1.0">
+    <require>
+      <command name="glDepthRange"/>
+    </require>
+  </feature>
+  <feature api="gles2" name="GL_ES_VERSION_2_0" number="2.0">
+    <require>
+      <enum name="GL_BLEND"/>
+      <enum name="GL_DEPTH_TEST"/>
+      <enum name="GL_TIMEOUT_IGNORED"/>
+      <command name="glEnable"/>
+      <command name="glLineWidth"/>
+    </require>
+  </feature>
+  <feature api="gles2" name="GL_ES_VERSION_3_0" number="3.0">
+    <require>
+      <command name="glDepthRange"/>
+    </require>
+  </feature>
+  <extensions>
+    <extension name="GL_EXT_texture_format_BGRA8888" supported="gles1|gles2">
+      <require>
+        <enum name="GL_BGRA_EXT"/>
+      </require>
+    </extension>
+    <extension name="GL_NV_fence" supported="gles1|gles2">
+      <require>
+        <enum name="GL_ALL_COMPLETED_NV"/>
+        <command name="glFinishFenceNV"/>
+      </require>
+    </extension>
+    <extension name="GL_OES_vertex_half_float" supported="gles1|gles2">
+      <require api="gles1">
+        <command name="glDepthRange"/>
+      </require>
+      <require>
+        <enum name="GL_HALF_FLOAT_OES"/>
+      </require>
+    </extension>
+  </extensions>
+</registry>
+`
+
+// TestTrimRegistry checks that trimming keeps what the bindings are
+// generated from, and drops the rest
+func TestTrimRegistry(t *testing.T) {
+	a := apis["gles2"]
+	trimmed, err := trimRegistry(strings.NewReader(upstreamRegistry), a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, gone := range []string{"comment", "GLdouble", "GLfixed", "glDepthRange", "GL_NV_fence", "GL_ES_VERSION_3_0", "<glx", `api="gles1"`} {
+		if bytes.Contains(trimmed, []byte(gone)) {
+			t.Errorf("trimmed registry still contains %s", gone)
+		}
+	}
+	want, err := parseSpec(strings.NewReader(upstreamRegistry), a, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := parseSpec(bytes.NewReader(trimmed), a, nil)
+	if err != nil {
+		t.Fatalf("%s\n%s", err, trimmed)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("trimmed registry generates %+v, want %+v", got, want)
+	}
+	again, err := trimRegistry(bytes.NewReader(trimmed), a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(again, trimmed) {
+		t.Errorf("trimming again gives\n%s\nwant\n%s", again, trimmed)
+	}
+}
+
+const upstreamRefpage = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE refentry PUBLIC "-//OASIS//DTD DocBook MathML Module V1.1b1//EN" "http://www.oasis-open.org/docbook/xml/mathml/1.1CR1/dbmathml.dtd">
+<refentry id="glUniform">
+    <refmeta>
+        <refmiscinfo>Copyright</refmiscinfo>
+    </refmeta>
+    <refnamediv>
+        <refname>glUniform1f</refname>
+        <refname>glUniform2f</refname>
+        <refpurpose>specify the value of a uniform variable for the current program&nbsp;object</refpurpose>
+    </refnamediv>
+    <refsect1 id="description"><title>Description</title>
+        <para><function>glUniform</function> modifies the value of &alpha; <mml:math><mml:mi>x</mml:mi></mml:math></para>
+    </refsect1>
+</refentry>
+`
+
+func TestTrimRefpage(t *testing.T) {
+	trimmed, err := trimRefpage([]byte(upstreamRefpage))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(trimmed, []byte("refsect1")) || !bytes.Contains(trimmed, []byte(`id="glUniform"`)) {
+		t.Errorf("trimmed refpage is\n%s", trimmed)
+	}
+	names, purpose, err := parseRefpage(bytes.NewReader(trimmed))
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantNames, wantPurpose, _ := parseRefpage(strings.NewReader(upstreamRefpage))
+	if !reflect.DeepEqual(names, wantNames) || purpose != wantPurpose {
+		t.Errorf("trimmed refpage gives %q %q, want %q %q", names, purpose, wantNames, wantPurpose)
+	}
+}
+
+// TestTrimmed fails if the checked-in specs are not what trimming gives
+func TestTrimmed(t *testing.T) {
+	for _, a := range apis {
+		f, err := os.Open(filepath.Join("specs", a.Spec))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, err := ioutil.ReadAll(f)
+		f.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, err := trimRegistry(bytes.NewReader(want), a); err != nil {
+			t.Errorf("%s: %s", a.Spec, err)
+		} else if !bytes.Equal(got, want) {
+			t.Errorf("specs/%s is not trimmed", a.Spec)
+		}
+		if a.Refpages == "" {
+			continue
+		}
+		pages, err := filepath.Glob(filepath.Join("specs", a.Refpages, "*.xml"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, page := range pages {
+			want, err := ioutil.ReadFile(page)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, err := trimRefpage(want); err != nil {
+				t.Errorf("%s: %s", page, err)
+			} else if !bytes.Equal(got, want) {
+				t.Errorf("%s is not trimmed", page)
+			}
+		}
+	}
+}
+
+func TestPins(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "upstream.sum")
+	p := pins{
+		"gl.xml":             {Sum: "aa", URL: "https://raw.githubusercontent.com/KhronosGroup/OpenGL-Registry/0123abc/xml/gl.xml"},
+		"es2.0/glEnable.xml": {Sum: "bb", URL: "https://raw.githubusercontent.com/KhronosGroup/OpenGL-Refpages/4567def/es2.0/glEnable.xml"},
+	}
+	if err := p.write(path); err != nil {
+		t.Fatal(err)
+	}
+	got, err := readPins(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, p) {
+		t.Errorf("read %v, want %v", got, p)
+	}
+	if commit, err := got.commit("es2.0", "KhronosGroup/OpenGL-Refpages", false); err != nil || commit != "4567def" {
+		t.Errorf("commit of es2.0 is %q, %v, want 4567def", commit, err)
+	}
+}
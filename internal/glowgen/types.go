@@ -0,0 +1,141 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// go types of the scalar C types
+var goScalars = map[string]string{
+	"GLenum":     "uint32",
+	"GLbitfield": "uint32",
+	"GLuint":     "uint32",
+	"GLint":      "int32",
+	"GLsizei":    "int32",
+	"GLfixed":    "int32",
+	"GLboolean":  "bool",
+	"GLbyte":     "int8",
+	"GLubyte":    "uint8",
+	"GLshort":    "int16",
+	"GLushort":   "uint16",
+	"GLfloat":    "float32",
+	"GLclampf":   "float32",
+	"GLchar":     "uint8",
+	"GLintptr":   "int",
+	"GLsizeiptr": "int",
+	"GLhalf":     "uint16",
+	"EGLint":     "int32",
+	"EGLenum":    "uint32",
+	"EGLBoolean": "bool",
+	"EGLTimeKHR": "uint64",
+	"char":       "uint8",
+}
+
+// opaque C handle types, passed as unsafe.Pointer
+var goHandles = map[string]bool{
+	"GLeglImageOES":        true,
+	"EGLDisplay":           true,
+	"EGLConfig":            true,
+	"EGLContext":           true,
+	"EGLSurface":           true,
+	"EGLClientBuffer":      true,
+	"EGLImageKHR":          true,
+	"EGLSyncKHR":           true,
+	"EGLNativeDisplayType": true,
+	"EGLNativeWindowType":  true,
+	"EGLNativePixmapType":  true,
+	"__eglMustCastToProperFunctionPointerType": true,
+}
+
+// handle types that cgo maps to uintptr, as they may hold values that are
+// not pointers
+var cgoUintptrs = map[string]bool{
+	"EGLDisplay": true,
+	"EGLConfig":  true,
+}
+
+// go keywords used as C parameter names
+var goKeywords = map[string]bool{
+	"type": true, "string": true, "func": true, "range": true, "map": true,
+	"var": true, "const": true, "go": true, "select": true, "chan": true,
+	"interface": true, "package": true, "import": true, "default": true,
+}
+
+func (p Param) GoName() string {
+	if goKeywords[p.Name] {
+		return "x" + p.Name
+	}
+	return p.Name
+}
+
+func (p Param) depth() int {
+	return strings.Count(p.Pointers, "*")
+}
+
+// GoType returns the go type of a parameter or return value
+func (p Param) GoType() (string, error) {
+	if p.IsVoid() {
+		return "", nil
+	}
+	if p.Base == "void" {
+		return strings.Repeat("*", p.depth()-1) + "unsafe.Pointer", nil
+	}
+	if goHandles[p.Base] {
+		return strings.Repeat("*", p.depth()) + "unsafe.Pointer", nil
+	}
+	scalar, ok := goScalars[p.Base]
+	if !ok {
+		return "", fmt.Errorf("fail to map C type %s", p.CType())
+	}
+	return strings.Repeat("*", p.depth()) + scalar, nil
+}
+
+// GoDecl returns the go parameter declaration, eg. "target uint32"
+func (p Param) GoDecl() string {
+	typ, _ := p.GoType()
+	return p.GoName() + " " + typ
+}
+
+// CArg converts a go parameter for the call into C
+func (p Param) CArg() string {
+	n := p.GoName()
+	switch {
+	case p.Base == "void":
+		return n
+	case p.depth() > 0:
+		return fmt.Sprintf("(%sC.%s)(unsafe.Pointer(%s))", strings.Repeat("*", p.depth()), p.Base, n)
+	case p.Base == "GLboolean" || p.Base == "EGLBoolean":
+		return fmt.Sprintf("(C.%s)(boolToInt(%s))", p.Base, n)
+	}
+	return fmt.Sprintf("(C.%s)(%s)", p.Base, n)
+}
+
+// GoReturn converts a C return value named ret back into go
+func (p Param) GoReturn() string {
+	typ, _ := p.GoType()
+	switch {
+	case p.Base == "GLboolean" || p.Base == "EGLBoolean":
+		return "ret == TRUE"
+	case p.Base == "char":
+		return fmt.Sprintf("(%s)(unsafe.Pointer(ret))", typ)
+	case cgoUintptrs[p.Base] && p.depth() == 0:
+		// copy the bits, as converting the uintptr fails go vet
+		return "*(*unsafe.Pointer)(unsafe.Pointer(&ret))"
+	}
+	return fmt.Sprintf("(%s)(ret)", typ)
+}
+
+// check makes sure all types of a spec can be mapped to go
+func (s *Spec) check() error {
+	for _, f := range s.Functions {
+		if _, err := f.Return.GoType(); err != nil {
+			return fmt.Errorf("%s: %s", f.Name, err)
+		}
+		for _, p := range f.Params {
+			if _, err := p.GoType(); err != nil {
+				return fmt.Errorf("%s: %s", f.Name, err)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package overlay
+
+import (
+	"image/color"
+
+	gl "github.com/FEEDFACE-COM/piglet/gles2"
+	"github.com/FEEDFACE-COM/piglet/glx"
+	"golang.org/x/image/font/basicfont"
+)
+
+// Size of the font texture, with the glyphs in a grid of 16 columns
+const fontSize = 128
+
+// Texel that is opaque, for untextured quads
+const solid = fontSize - 2
+
+// Upload the glyphs of basicfont.Face7x13 into an ALPHA texture, rows from
+// the top
+func uploadFont() glx.Texture {
+	face := basicfont.Face7x13
+	gw, gh := face.Width, face.Height
+	pixels := make([]byte, fontSize*fontSize)
+	for i := 0; i < face.Mask.Bounds().Dy()/gh; i++ {
+		x0, y0 := i%16*gw, i/16*gh
+		for y := 0; y < gh; y++ {
+			for x := 0; x < gw; x++ {
+				_, _, _, a := face.Mask.At(x, i*gh+y).RGBA()
+				pixels[(y0+y)*fontSize+x0+x] = byte(a >> 8)
+			}
+		}
+	}
+	for y := solid - 2; y < fontSize; y++ {
+		for x := solid - 2; x < fontSize; x++ {
+			pixels[y*fontSize+x] = 255
+		}
+	}
+	tex := glx.NewTexture(glx.Texture2D)
+	gl.PixelStorei(gl.UNPACK_ALIGNMENT, 1)
+	tex.SetImage(0, glx.Alpha, fontSize, fontSize, glx.UnsignedByte, pixels)
+	gl.PixelStorei(gl.UNPACK_ALIGNMENT, 4)
+	tex.SetFilter(glx.Nearest, glx.Nearest)
+	tex.SetWrap(glx.ClampToEdge, glx.ClampToEdge)
+	return tex
+}
+
+// Add a line of text with its top left at x,y
+func (o *Overlay) text(x, y float32, line string, c color.NRGBA) {
+	face := basicfont.Face7x13
+	s := o.scale
+	gw, gh := float32(face.Width), float32(face.Height)
+	for _, r := range line {
+		i := 95 // the replacement character
+		if r >= 0x20 && r < 0x7f {
+			i = int(r - 0x20)
+		}
+		if r != ' ' {
+			u, v := float32(i%16)*gw/fontSize, float32(i/16)*gh/fontSize
+			o.quad(x, y, gw*s, gh*s, u, v, u+gw/fontSize, v+gh/fontSize, c)
+		}
+		x += float32(face.Advance) * s
+	}
+}
+
+// Add an untextured w*h rectangle at x,y
+func (o *Overlay) rect(x, y, w, h float32, c color.NRGBA) {
+	const t = (solid + 0.5) / fontSize
+	o.quad(x, y, w, h, t, t, t, t, c)
+}
+
+// Add a w*h quad at x,y showing the font texture from u0,v0 to u1,v1
+func (o *Overlay) quad(x, y, w, h, u0, v0, u1, v1 float32, c color.NRGBA) {
+	a := uint32(c.A)
+	rgba := [4]uint8{uint8(uint32(c.R) * a / 255), uint8(uint32(c.G) * a / 255), uint8(uint32(c.B) * a / 255), c.A}
+	v := [4]vertex{
+		{[2]float32{x, y}, [2]float32{u0, v0}, rgba},
+		{[2]float32{x + w, y}, [2]float32{u1, v0}, rgba},
+		{[2]float32{x + w, y + h}, [2]float32{u1, v1}, rgba},
+		{[2]float32{x, y + h}, [2]float32{u0, v1}, rgba},
+	}
+	o.vertices = append(o.vertices, v[0], v[1], v[2], v[2], v[3], v[0])
+}
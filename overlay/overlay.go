@@ -0,0 +1,313 @@
+// Package overlay draws frame statistics over a piglet program: frames per
+// second, a graph of frame times, draw calls per frame, texture memory,
+// the display size and the GL renderer and version. It has its own shader
+// and bitmap font, so it draws the same whatever the program's pipeline:
+//
+//	ov, err := overlay.New(int(w), int(h))
+//	for piglet.Loop() {
+//		draw()
+//		ov.Draw()
+//		piglet.SwapBuffers()
+//	}
+//
+// The overlay starts visible if PIGLET_OVERLAY is set, and HandleEvent
+// toggles it with F3.
+package overlay
+
+import (
+	"fmt"
+	"image/color"
+	"os"
+	"sync/atomic"
+	"time"
+
+	gl "github.com/FEEDFACE-COM/piglet/gles2"
+	"github.com/FEEDFACE-COM/piglet/glx"
+	"github.com/FEEDFACE-COM/piglet/input"
+	"github.com/go-gl/mathgl/mgl32"
+	"golang.org/x/image/font/basicfont"
+)
+
+// Environment variable that shows the overlay from the start, unless empty
+// or 0
+const EnvVar = "PIGLET_OVERLAY"
+
+// Frames shown in the graph
+const graphFrames = 120
+
+// Interval the numbers are updated at, to keep them readable
+const refresh = 500 * time.Millisecond
+
+// Frame time the graph reaches up to, two frames at 60 Hz
+const graphMax = 2 * time.Second / 60
+
+type vertex struct {
+	Position [2]float32
+	TexCoord [2]float32
+	Color    [4]uint8 `attrib:"color,normalized"`
+}
+
+const vertexSource = `
+attribute vec2 position;
+attribute vec2 texCoord;
+attribute vec4 color;
+uniform mat4 projection;
+varying vec2 v_texcoord;
+varying vec4 v_color;
+void main() {
+	v_texcoord = texCoord;
+	v_color = color;
+	gl_Position = projection * vec4(position, 0.0, 1.0);
+}
+`
+
+const fragmentSource = `
+precision mediump float;
+uniform sampler2D font;
+varying vec2 v_texcoord;
+varying vec4 v_color;
+void main() {
+	gl_FragColor = v_color * texture2D(font, v_texcoord).a;
+}
+`
+
+var (
+	background = color.NRGBA{0, 0, 0, 176}
+	foreground = color.NRGBA{255, 255, 255, 255}
+	good       = color.NRGBA{64, 224, 64, 255}
+	late       = color.NRGBA{240, 200, 32, 255}
+	missed     = color.NRGBA{240, 48, 32, 255}
+	guide      = color.NRGBA{255, 255, 255, 96}
+)
+
+// An Overlay draws frame statistics on top of the frame. Show, Toggle and
+// HandleEvent may be called from any goroutine, eg. the one reading input.
+type Overlay struct {
+	Key input.Key // toggles the overlay in HandleEvent, F3 from New
+
+	visible       int32 // atomic
+	width, height int
+	scale         float32 // of the font and graph, 2 on large displays
+	renderer      string
+	version       string
+
+	program  *glx.Program
+	layout   *glx.Layout
+	buffer   *glx.VertexBuffer
+	font     glx.Texture
+	vertices []vertex
+
+	last   time.Time                  // of the previous Draw
+	times  [graphFrames]time.Duration // ring of frame times
+	next   int                        // in times
+	draws  uint64                     // gl.DrawCalls after the previous Draw
+	frame  uint64                     // draw calls of the previous frame
+	window struct {
+		start  time.Time
+		frames int
+		max    time.Duration
+	}
+	lines []string
+}
+
+// Create an overlay for a display of w*h pixels, eg. from
+// piglet.GetDisplaySize. Texture memory is shown while gles2 tracking is on.
+func New(w, h int) (*Overlay, error) {
+	layout, err := glx.LayoutOf(vertex{})
+	if err != nil {
+		return nil, err
+	}
+	program, err := glx.CompileProgram(vertexSource, fragmentSource)
+	if err != nil {
+		return nil, fmt.Errorf("fail to compile overlay shader: %s", err)
+	}
+	o := &Overlay{
+		Key:      input.KeyF3,
+		renderer: gl.GoStr(gl.GetString(gl.RENDERER)),
+		version:  gl.GoStr(gl.GetString(gl.VERSION)),
+		program:  program,
+		layout:   layout,
+		buffer:   glx.NewVertexBuffer(layout, glx.StreamDraw),
+		font:     uploadFont(),
+	}
+	o.Resize(w, h)
+	env := os.Getenv(EnvVar)
+	o.Show(env != "" && env != "0")
+	return o, nil
+}
+
+// Set the size of the display
+func (o *Overlay) Resize(w, h int) {
+	o.width, o.height = w, h
+	o.scale = 1
+	if h >= 1000 {
+		o.scale = 2
+	}
+}
+
+// Report whether the overlay is shown
+func (o *Overlay) Visible() bool {
+	return atomic.LoadInt32(&o.visible) != 0
+}
+
+// Show or hide the overlay
+func (o *Overlay) Show(visible bool) {
+	v := int32(0)
+	if visible {
+		v = 1
+	}
+	atomic.StoreInt32(&o.visible, v)
+}
+
+// Show the overlay if hidden, or hide it if shown
+func (o *Overlay) Toggle() {
+	for {
+		v := atomic.LoadInt32(&o.visible)
+		if atomic.CompareAndSwapInt32(&o.visible, v, 1-v) {
+			return
+		}
+	}
+}
+
+// Toggle the overlay if e presses its key, and report whether it did
+func (o *Overlay) HandleEvent(e input.Event) bool {
+	if k, ok := e.(*input.KeyEvent); ok && k.Key == o.Key && k.Action == input.KeyPress {
+		o.Toggle()
+		return true
+	}
+	return false
+}
+
+// Record the frame, and draw the overlay if visible. Call once per frame,
+// after drawing the frame and before swapping buffers.
+func (o *Overlay) Draw() {
+	now := time.Now()
+	if !o.last.IsZero() {
+		dt := now.Sub(o.last)
+		o.times[o.next] = dt
+		o.next = (o.next + 1) % graphFrames
+		o.window.frames++
+		if dt > o.window.max {
+			o.window.max = dt
+		}
+	}
+	o.last = now
+	draws := gl.DrawCalls()
+	o.frame = draws - o.draws
+	if o.window.start.IsZero() {
+		o.window.start = now
+	}
+	if elapsed := now.Sub(o.window.start); elapsed >= refresh || o.lines == nil {
+		o.update(elapsed)
+		o.window.start, o.window.frames, o.window.max = now, 0, 0
+	}
+	if o.Visible() {
+		o.render()
+	}
+	o.draws = gl.DrawCalls()
+}
+
+// Update the lines of text from the frames in the last elapsed time
+func (o *Overlay) update(elapsed time.Duration) {
+	fps := 0.0
+	if elapsed > 0 {
+		fps = float64(o.window.frames) / elapsed.Seconds()
+	}
+	memory := "textures ? (gles2 tracking is off)"
+	if gl.Tracking() {
+		var textures, total int64
+		for _, obj := range gl.TrackedObjects() {
+			if obj.Kind == gl.KindTexture {
+				textures += obj.Bytes
+			}
+			total += obj.Bytes
+		}
+		memory = fmt.Sprintf("textures %s  gl %s", formatBytes(textures), formatBytes(total))
+	}
+	o.lines = []string{
+		fmt.Sprintf("%5.1f fps  max %4.1f ms", fps, o.window.max.Seconds()*1000),
+		fmt.Sprintf("draws %d", o.frame),
+		memory,
+		fmt.Sprintf("display %dx%d", o.width, o.height),
+		o.renderer,
+		o.version,
+	}
+}
+
+// Draw the panel, text and graph
+func (o *Overlay) render() {
+	s := o.scale
+	cw, ch := float32(basicfont.Face7x13.Advance)*s, float32(basicfont.Face7x13.Height)*s
+	margin, pad := 8*s, 4*s
+	cols := 0
+	for _, line := range o.lines {
+		if len(line) > cols {
+			cols = len(line)
+		}
+	}
+	graphW, graphH := float32(graphFrames)*s, 40*s
+	w := float32(cols) * cw
+	if graphW > w {
+		w = graphW
+	}
+	h := float32(len(o.lines))*ch + pad + graphH
+	o.vertices = o.vertices[:0]
+	o.rect(margin, margin, w+2*pad, h+2*pad, background)
+	x, y := margin+pad, margin+pad
+	for i, line := range o.lines {
+		o.text(x, y+float32(i)*ch, line, foreground)
+	}
+	// oldest frame on the left, bars up from the bottom
+	bottom := margin + pad + h
+	for i := 0; i < graphFrames; i++ {
+		dt := o.times[(o.next+i)%graphFrames]
+		if dt == 0 {
+			continue
+		}
+		c := good
+		switch {
+		case dt > graphMax:
+			c, dt = missed, graphMax
+		case dt > graphMax/2+time.Millisecond:
+			c = late
+		}
+		bar := graphH * float32(dt) / float32(graphMax)
+		o.rect(x+float32(i)*s, bottom-bar, s, bar, c)
+	}
+	o.rect(x, bottom-graphH/2, graphW, s, guide)
+
+	// leave the state as the program had it
+	state := glx.StateCache()
+	blend := state.GetBlendFunc()
+	program := state.Program()
+	texture := state.Texture(0, gl.TEXTURE_2D)
+	saved := state.Begin2D()
+	state.BlendFunc(gl.ONE, gl.ONE_MINUS_SRC_ALPHA)
+	o.program.Use()
+	o.program.Uniform("projection").Mat4(mgl32.Ortho2D(0, float32(o.width), float32(o.height), 0))
+	o.program.Uniform("font").Sampler(o.font, 0)
+	o.buffer.SetVertices(o.vertices)
+	o.buffer.Draw(o.program, glx.Triangles)
+	o.layout.Disable(o.program)
+	state.BindTextureUnit(0, gl.TEXTURE_2D, texture)
+	state.UseProgram(program)
+	state.BlendFuncSeparate(blend[0], blend[1], blend[2], blend[3])
+	state.End2D(saved)
+}
+
+// Delete the program, buffer and font texture of the overlay
+func (o *Overlay) Delete() {
+	o.program.Delete()
+	o.buffer.Delete()
+	o.font.Delete()
+}
+
+func formatBytes(n int64) string {
+	switch {
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1f MiB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1f KiB", float64(n)/(1<<10))
+	}
+	return fmt.Sprintf("%d B", n)
+}
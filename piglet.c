@@ -50,16 +50,27 @@ static uint32_t height = 0;
 
 
 static EGLDisplay display;
+static EGLConfig  config;
 static EGLContext context;
 static EGLSurface surface;
 
 static DISPMANX_ELEMENT_HANDLE_T dispman_element;
 static DISPMANX_DISPLAY_HANDLE_T dispman_display;
 
+static DISPMANX_ELEMENT_HANDLE_T  cursor_element  = 0;
+static DISPMANX_RESOURCE_HANDLE_T cursor_resource = 0;
+static VC_RECT_T cursor_rect;
+static int cursor_visible = 1;
+
 
 int GetDisplayWidth()  { return (int) width;  }
 int GetDisplayHeight() { return (int) height; }
 
+void* GetEGLDisplay() { return (void*) display; }
+void* GetEGLConfig()  { return (void*) config;  }
+void* GetEGLContext() { return (void*) context; }
+void* GetEGLSurface() { return (void*) surface; }
+
 int
 CreateContext()
 {
@@ -89,7 +100,6 @@ CreateContext()
         EGL_NONE
     };
     
-    EGLConfig config;
     EGLint    config_count;
 
     EGLBoolean res;
@@ -234,6 +244,140 @@ GetProcAddress(const char *name)
 }
 
 
+int
+CreateCursor(int cursor_width, int cursor_height, void *pixels, int pitch, int layer)
+{
+
+    // a separate element above the EGL surface, so moving it needs no redraw
+
+
+    DISPMANX_UPDATE_HANDLE_T  dispman_update;
+    VC_DISPMANX_ALPHA_T alpha = { DISPMANX_FLAGS_ALPHA_FROM_SOURCE, 255, 0 };
+    uint32_t native_image;
+    VC_RECT_T src_rect;
+    VC_RECT_T data_rect;
+    int err;
+
+    if (cursor_element != 0) {
+        DestroyCursor();
+    }
+
+    cursor_resource = vc_dispmanx_resource_create(VC_IMAGE_RGBA32, cursor_width, cursor_height, &native_image);
+    if (cursor_resource == 0) {
+        PIGLET_ERROR("fail to create cursor resource!!");
+        return -1;
+    }
+
+    vc_dispmanx_rect_set(&data_rect, 0, 0, cursor_width, cursor_height);
+    err = vc_dispmanx_resource_write_data(cursor_resource, VC_IMAGE_RGBA32, pitch, pixels, &data_rect);
+    if ( err != 0 ) {
+        PIGLET_ERROR("fail to write cursor resource!!");
+        vc_dispmanx_resource_delete(cursor_resource);
+        cursor_resource = 0;
+        return -1;
+    }
+
+    vc_dispmanx_rect_set(&src_rect, 0, 0, cursor_width << 16, cursor_height << 16);
+    vc_dispmanx_rect_set(&cursor_rect, cursor_rect.x, cursor_rect.y, cursor_width, cursor_height);
+
+    dispman_update = vc_dispmanx_update_start(0);
+    cursor_element = vc_dispmanx_element_add(
+        dispman_update,
+        dispman_display,
+        layer, &cursor_rect,
+        cursor_resource, &src_rect,
+        DISPMANX_PROTECTION_NONE,
+        &alpha,
+        0, //clamp
+        0  //transform
+    );
+    vc_dispmanx_update_submit_sync(dispman_update);
+
+    if (cursor_element == 0) {
+        PIGLET_ERROR("fail to add cursor element!!");
+        vc_dispmanx_resource_delete(cursor_resource);
+        cursor_resource = 0;
+        return -1;
+    }
+
+    ShowCursor(cursor_visible);
+    return 0;
+}
+
+
+static void
+UpdateCursor()
+{
+    DISPMANX_UPDATE_HANDLE_T  dispman_update;
+    VC_RECT_T dst_rect = cursor_rect;
+
+    if (cursor_element == 0) {
+        return;
+    }
+
+    if (!cursor_visible) {
+        dst_rect.x = width; // off the display
+        dst_rect.y = height;
+    }
+
+    // submit without waiting for vsync, as moves come in faster than frames
+    dispman_update = vc_dispmanx_update_start(0);
+    vc_dispmanx_element_change_attributes(dispman_update, cursor_element, ELEMENT_CHANGE_DEST_RECT, 0, 255, &dst_rect, NULL, 0, 0);
+    vc_dispmanx_update_submit(dispman_update, NULL, NULL);
+}
+
+
+void
+MoveCursor(int x, int y)
+{
+    if (cursor_rect.x == x && cursor_rect.y == y) {
+        return;
+    }
+    cursor_rect.x = x;
+    cursor_rect.y = y;
+    if (cursor_visible) {
+        UpdateCursor();
+    }
+}
+
+
+void
+ShowCursor(int visible)
+{
+    cursor_visible = visible;
+    UpdateCursor();
+}
+
+
+int
+DestroyCursor()
+{
+    DISPMANX_UPDATE_HANDLE_T  dispman_update;
+    int err;
+
+    if (cursor_element == 0) {
+        return 0;
+    }
+
+    dispman_update = vc_dispmanx_update_start(0);
+    err = vc_dispmanx_element_remove(dispman_update, cursor_element);
+    vc_dispmanx_update_submit_sync(dispman_update);
+    cursor_element = 0;
+    if ( err != 0 ) {
+        PIGLET_ERROR("fail to remove cursor element!!");
+        return -1;
+    }
+
+    err = vc_dispmanx_resource_delete(cursor_resource);
+    cursor_resource = 0;
+    if ( err != 0 ) {
+        PIGLET_ERROR("fail to delete cursor resource!!");
+        return -1;
+    }
+    return 0;
+}
+
+
 int
 DestroyContext()
 {
@@ -242,6 +386,8 @@ DestroyContext()
     static DISPMANX_UPDATE_HANDLE_T  dispman_update;
 
 
+    DestroyCursor();
+
     eglDestroySurface(display, surface);
     PIGLET_CHECK("eglDestroySurface");
 
@@ -12,6 +12,10 @@ package piglet
 import "C"
 import "unsafe"
 import "errors"
+import "os"
+import "image"
+import "image/color"
+import "image/draw"
 import "github.com/FEEDFACE-COM/piglet/gles2"
 
 
@@ -21,6 +25,7 @@ import "github.com/FEEDFACE-COM/piglet/gles2"
 
 // Create a new EGL rendering context
 func CreateContext() error {
+	gles2.ResetExtensions()
 	err := int(C.CreateContext())
 	if err != 0 {
 		return errors.New("fail to create context!!")
@@ -52,8 +57,124 @@ func GetProcAddress(name string) unsafe.Pointer {
 	return C.GetProcAddress(cname)
 }
 
-// Destroy an EGL rendering context
+// Return the raw EGLDisplay handle, for use with the egl package
+func GetEGLDisplay() unsafe.Pointer {
+	return C.GetEGLDisplay()
+}
+
+// Return the raw EGLConfig handle the rendering context was created with
+func GetEGLConfig() unsafe.Pointer {
+	return C.GetEGLConfig()
+}
+
+// Return the raw EGLContext handle of the rendering context
+func GetEGLContext() unsafe.Pointer {
+	return C.GetEGLContext()
+}
+
+// Return the raw EGLSurface handle of the native window surface
+func GetEGLSurface() unsafe.Pointer {
+	return C.GetEGLSurface()
+}
+
+// Layer of the cursor element, above the EGL surface on layer 0
+const CursorLayer = 2000
+
+var cursorHotspot image.Point
+
+// Show a cursor above the EGL surface, as a separate dispmanx element that
+// moves without redrawing the GL scene. img is drawn with its alpha, and
+// hotspot is the pixel of img placed at the position given to MoveCursor.
+// Call after CreateContext. A cursor created before is replaced.
+func CreateCursor(img image.Image, hotspot image.Point) error {
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	if w == 0 || h == 0 {
+		return errors.New("fail to create cursor: empty image")
+	}
+	// dispmanx takes non-premultiplied RGBA with rows aligned to 32 bytes
+	pitch := (w*4 + 31) &^ 31
+	rgba := &image.NRGBA{Pix: make([]uint8, pitch*h), Stride: pitch, Rect: image.Rect(0, 0, w, h)}
+	draw.Draw(rgba, rgba.Rect, img, bounds.Min, draw.Src)
+	err := int(C.CreateCursor(C.int(w), C.int(h), unsafe.Pointer(&rgba.Pix[0]), C.int(pitch), CursorLayer))
+	if err != 0 {
+		return errors.New("fail to create cursor!!")
+	}
+	cursorHotspot = hotspot.Sub(bounds.Min)
+	return nil
+}
+
+// Move the cursor hotspot to x,y in display pixels from the top left, eg.
+// to the position of an input.MouseMoveEvent
+func MoveCursor(x, y int) {
+	C.MoveCursor(C.int(x-cursorHotspot.X), C.int(y-cursorHotspot.Y))
+}
+
+// Show or hide the cursor
+func ShowCursor(visible bool) {
+	v := 0
+	if visible {
+		v = 1
+	}
+	C.ShowCursor(C.int(v))
+}
+
+// Remove the cursor. DestroyContext removes it too.
+func DestroyCursor() error {
+	err := int(C.DestroyCursor())
+	if err != 0 {
+		return errors.New("fail to destroy cursor!!")
+	}
+	return nil
+}
+
+// an arrow pointing up left, with the hotspot at its tip
+var arrow = []string{
+	"X",
+	"XX",
+	"X.X",
+	"X..X",
+	"X...X",
+	"X....X",
+	"X.....X",
+	"X......X",
+	"X.......X",
+	"X........X",
+	"X.........X",
+	"X......XXXXX",
+	"X...X..X",
+	"X..XX..X",
+	"X.X  X..X",
+	"XX   X..X",
+	"X     X..X",
+	"      X..X",
+	"       XX",
+}
+
+// Return the image and hotspot of an arrow cursor, white with a black
+// outline, for CreateCursor
+func ArrowCursor() (image.Image, image.Point) {
+	img := image.NewNRGBA(image.Rect(0, 0, 12, len(arrow)))
+	for y, row := range arrow {
+		for x, c := range row {
+			switch c {
+			case 'X':
+				img.SetNRGBA(x, y, color.NRGBA{0, 0, 0, 255})
+			case '.':
+				img.SetNRGBA(x, y, color.NRGBA{255, 255, 255, 255})
+			}
+		}
+	}
+	return img, image.Point{}
+}
+
+// Destroy an EGL rendering context. With gles2.SetTracking on, report the
+// GL objects still alive to stderr first.
 func DestroyContext() error {
+	if gles2.Tracking() && len(gles2.TrackedObjects()) > 0 {
+		gles2.WriteTrackingReport(os.Stderr, true)
+	}
+	gles2.ResetExtensions()
 	err := int(C.DestroyContext())
 	if err != 0 {
 		return errors.New("fail to destroy context!!")
@@ -13,5 +13,15 @@ int GetDisplayHeight(void);
 
 void* GetProcAddress(const char *name);
 
+void* GetEGLDisplay(void);
+void* GetEGLConfig(void);
+void* GetEGLContext(void);
+void* GetEGLSurface(void);
+
+int CreateCursor(int width, int height, void *pixels, int pitch, int layer);
+void MoveCursor(int x, int y);
+void ShowCursor(int visible);
+int DestroyCursor(void);
+
 #endif //PIGLET_H
 
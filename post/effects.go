@@ -0,0 +1,174 @@
+package post
+
+import (
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+const copySource = `
+uniform sampler2D source;
+void main() {
+	gl_FragColor = texture2D(source, v_texcoord);
+}
+`
+
+// 9 tap gaussian along direction, as 5 bilinear samples
+const blurSource = `
+uniform sampler2D source;
+uniform vec2 texel;
+uniform vec2 direction;
+uniform float radius;
+void main() {
+	vec2 offset = direction * texel * radius;
+	vec4 sum = texture2D(source, v_texcoord) * 0.2270270270;
+	sum += texture2D(source, v_texcoord + offset * 1.3846153846) * 0.3162162162;
+	sum += texture2D(source, v_texcoord - offset * 1.3846153846) * 0.3162162162;
+	sum += texture2D(source, v_texcoord + offset * 3.2307692308) * 0.0702702703;
+	sum += texture2D(source, v_texcoord - offset * 3.2307692308) * 0.0702702703;
+	gl_FragColor = sum;
+}
+`
+
+// Return two passes blurring horizontally and vertically, at scale of the
+// display size. radius spreads the samples, 1 for a 9 pixel kernel.
+func Blur(radius, scale float32) []*Pass {
+	return []*Pass{
+		{Name: "blur-x", Fragment: blurSource, Scale: scale, Uniforms: map[string]interface{}{
+			"direction": mgl32.Vec2{1, 0}, "radius": radius,
+		}},
+		{Name: "blur-y", Fragment: blurSource, Scale: scale, Uniforms: map[string]interface{}{
+			"direction": mgl32.Vec2{0, 1}, "radius": radius,
+		}},
+	}
+}
+
+const brightSource = `
+uniform sampler2D source;
+uniform float threshold;
+void main() {
+	vec4 color = texture2D(source, v_texcoord);
+	float luma = dot(color.rgb, vec3(0.2126, 0.7152, 0.0722));
+	gl_FragColor = color * smoothstep(threshold, threshold + 0.1, luma);
+}
+`
+
+const bloomSource = `
+uniform sampler2D source;
+uniform sampler2D scene;
+uniform float intensity;
+void main() {
+	vec4 glow = texture2D(source, v_texcoord);
+	vec4 color = texture2D(scene, v_texcoord);
+	gl_FragColor = vec4(color.rgb + glow.rgb * intensity, color.a);
+}
+`
+
+// Return passes making pixels brighter than threshold, in [0,1] luma, glow:
+// a bright pass and a blur at quarter size, and a pass adding the glow
+// to the scene. Bloom replaces the output of earlier passes with the
+// scene, so put it first.
+func Bloom(threshold, intensity float32) []*Pass {
+	ret := []*Pass{{Name: "bloom-bright", Fragment: brightSource, Scale: 0.25, Uniforms: map[string]interface{}{
+		"threshold": threshold,
+	}}}
+	blur := Blur(1.5, 0.25)
+	blur[0].Name, blur[1].Name = "bloom-blur-x", "bloom-blur-y"
+	ret = append(ret, blur...)
+	return append(ret, &Pass{Name: "bloom", Fragment: bloomSource, Uniforms: map[string]interface{}{
+		"intensity": intensity,
+	}})
+}
+
+const gradeSource = `
+uniform sampler2D source;
+uniform float brightness;
+uniform float contrast;
+uniform float saturation;
+uniform vec3 tint;
+void main() {
+	vec4 color = texture2D(source, v_texcoord);
+	vec3 rgb = color.rgb + brightness;
+	rgb = (rgb - 0.5) * contrast + 0.5;
+	float luma = dot(rgb, vec3(0.2126, 0.7152, 0.0722));
+	rgb = mix(vec3(luma), rgb, saturation) * tint;
+	gl_FragColor = vec4(clamp(rgb, 0.0, 1.0), color.a);
+}
+`
+
+// Return a color grading pass, neutral with brightness 0, contrast 1,
+// saturation 1 and a white tint
+func ColorGrade(brightness, contrast, saturation float32, tint mgl32.Vec3) *Pass {
+	return &Pass{Name: "grade", Fragment: gradeSource, Uniforms: map[string]interface{}{
+		"brightness": brightness, "contrast": contrast, "saturation": saturation, "tint": tint,
+	}}
+}
+
+const vignetteSource = `
+uniform sampler2D source;
+uniform vec2 resolution;
+uniform float strength;
+uniform float radius;
+void main() {
+	vec4 color = texture2D(source, v_texcoord);
+	vec2 d = (v_texcoord - 0.5) * vec2(resolution.x / resolution.y, 1.0);
+	float v = 1.0 - smoothstep(radius - 0.5, radius, length(d));
+	gl_FragColor = vec4(color.rgb * mix(1.0, v, strength), color.a);
+}
+`
+
+// Return a pass darkening the corners. strength is in [0,1], and radius is
+// the distance from the center, relative to the display height, where the
+// darkening is full, eg. 0.9. It fades in over the 0.5 before.
+func Vignette(strength, radius float32) *Pass {
+	return &Pass{Name: "vignette", Fragment: vignetteSource, Uniforms: map[string]interface{}{
+		"strength": strength, "radius": radius,
+	}}
+}
+
+const crtSource = `
+uniform sampler2D source;
+uniform vec2 resolution;
+uniform float time;
+uniform float curvature;
+uniform float scanlines;
+uniform float lines;
+void main() {
+	vec2 uv = v_texcoord * 2.0 - 1.0;
+	uv *= 1.0 + curvature * dot(uv.yx, uv.yx);
+	uv = uv * 0.5 + 0.5;
+	if (uv.x < 0.0 || uv.x > 1.0 || uv.y < 0.0 || uv.y > 1.0) {
+		gl_FragColor = vec4(0.0, 0.0, 0.0, 1.0);
+		return;
+	}
+	vec4 color = texture2D(source, uv);
+	float scan = 0.5 + 0.5 * sin((uv.y * lines + time * 2.0) * 6.2831853);
+	color.rgb *= 1.0 - scanlines * (1.0 - scan);
+	float mask = mod(gl_FragCoord.x, 3.0);
+	color.rgb *= vec3(mask < 1.0 ? 1.0 : 0.85, mask >= 1.0 && mask < 2.0 ? 1.0 : 0.85, mask >= 2.0 ? 1.0 : 0.85);
+	gl_FragColor = color;
+}
+`
+
+// Return a pass imitating a CRT: barrel distortion by curvature, eg. 0.05,
+// scanlines darkening by scanlines in [0,1], lines rows, eg. 240, and an
+// RGB shadow mask
+func CRT(curvature, scanlines, lines float32) *Pass {
+	return &Pass{Name: "crt", Fragment: crtSource, Uniforms: map[string]interface{}{
+		"curvature": curvature, "scanlines": scanlines, "lines": lines,
+	}}
+}
+
+const gammaSource = `
+uniform sampler2D source;
+uniform float gamma;
+void main() {
+	vec4 color = texture2D(source, v_texcoord);
+	gl_FragColor = vec4(pow(color.rgb, vec3(1.0 / gamma)), color.a);
+}
+`
+
+// Return a pass applying gamma, eg. 2.2 to encode linear colors for the display
+func Gamma(gamma float32) *Pass {
+	return &Pass{Name: "gamma", Fragment: gammaSource, Uniforms: map[string]interface{}{
+		"gamma": gamma,
+	}}
+}
@@ -0,0 +1,290 @@
+// Package post runs post-processing effects: the scene renders into an
+// offscreen target, and a chain of full-screen shader passes ping-pongs
+// between targets before the last one draws to the display.
+package post
+
+import (
+	"fmt"
+
+	gl "github.com/FEEDFACE-COM/piglet/gles2"
+	"github.com/FEEDFACE-COM/piglet/glx"
+)
+
+// A Pass is one full-screen shader pass. Its fragment shader samples the
+// output of the previous pass, and may use these uniforms, which the chain
+// sets when the shader declares them:
+//
+//	sampler2D source      output of the previous pass, or the scene
+//	sampler2D scene       the scene, as rendered before any pass
+//	vec2      resolution  size of this pass's output in pixels
+//	vec2      texel       size of one pixel of source, in texture coordinates
+//	float     time        Chain.Time
+//
+// and the varying vec2 v_texcoord.
+type Pass struct {
+	Name     string
+	Fragment string                 // fragment shader, after a header declaring precision and v_texcoord
+	Scale    float32                // output size relative to the chain, 1 if zero, eg. 0.5 for blurs
+	Uniforms map[string]interface{} // set with glx.Program.SetUniform before each draw
+	Disabled bool                   // skip the pass
+
+	program  *glx.Program
+	builtins builtins
+}
+
+// Locations of the uniforms the chain sets, -1 for those the shader lacks
+type builtins struct {
+	source, scene, resolution, texel, time glx.Uniform
+}
+
+// Set a uniform of the pass, from the next frame on
+func (p *Pass) Set(name string, value interface{}) {
+	if p.Uniforms == nil {
+		p.Uniforms = map[string]interface{}{}
+	}
+	p.Uniforms[name] = value
+}
+
+// A Chain renders the scene into a target and runs passes over it
+type Chain struct {
+	Passes []*Pass
+	Time   float32 // for the time uniform, eg. seconds since start
+
+	width, height int
+	options       glx.RenderTargetOptions
+	scene         *glx.RenderTarget
+	targets       map[float32][2]*glx.RenderTarget // ping-pong pairs by scale
+	copy          *Pass
+	quad          *glx.VertexBuffer
+}
+
+const vertexSource = `
+attribute vec2 position;
+varying vec2 v_texcoord;
+void main() {
+	v_texcoord = position * 0.5 + 0.5;
+	gl_Position = vec4(position, 0.0, 1.0);
+}
+`
+
+const header = "precision mediump float;\nvarying vec2 v_texcoord;\n"
+
+var quadLayout = glx.NewLayout(glx.Attribute{Name: "position", Size: 2})
+
+// Create a chain for a display of w*h pixels, rendering the scene into a
+// target with the given options, eg. with Depth set for 3D scenes
+func NewChain(w, h int, scene glx.RenderTargetOptions, passes ...*Pass) (*Chain, error) {
+	c := &Chain{Passes: passes, width: w, height: h, options: scene, targets: map[float32][2]*glx.RenderTarget{}}
+	var err error
+	c.scene, err = glx.NewRenderTarget(w, h, scene)
+	if err != nil {
+		return nil, err
+	}
+	c.copy = &Pass{Name: "copy", Fragment: copySource}
+	for _, p := range append([]*Pass{c.copy}, passes...) {
+		if err := c.compile(p); err != nil {
+			c.Delete()
+			return nil, err
+		}
+	}
+	c.quad = glx.NewVertexBuffer(quadLayout, glx.StaticDraw)
+	c.quad.SetVertices([]float32{-1, -1, 1, -1, -1, 1, 1, 1})
+	return c, nil
+}
+
+func (c *Chain) compile(p *Pass) error {
+	program, err := glx.CompileProgram(vertexSource, header+p.Fragment)
+	if err != nil {
+		return fmt.Errorf("fail to compile %s pass: %s", p.Name, err)
+	}
+	p.program = program
+	location := func(name string) glx.Uniform {
+		if _, ok := program.UniformInfo(name); !ok {
+			return -1
+		}
+		return program.Uniform(name)
+	}
+	p.builtins = builtins{
+		source:     location("source"),
+		scene:      location("scene"),
+		resolution: location("resolution"),
+		texel:      location("texel"),
+		time:       location("time"),
+	}
+	return nil
+}
+
+// Add a pass at the end of the chain
+func (c *Chain) Add(p *Pass) error {
+	if err := c.compile(p); err != nil {
+		return err
+	}
+	c.Passes = append(c.Passes, p)
+	return nil
+}
+
+// Return the pass called name, or nil
+func (c *Chain) Pass(name string) *Pass {
+	for _, p := range c.Passes {
+		if p.Name == name {
+			return p
+		}
+	}
+	return nil
+}
+
+// Return the target the scene renders into, eg. to sample it elsewhere
+func (c *Chain) Scene() *glx.RenderTarget { return c.scene }
+
+// Reallocate the targets for a display of w*h pixels
+func (c *Chain) Resize(w, h int) error {
+	c.width, c.height = w, h
+	for _, pair := range c.targets {
+		pair[0].Delete()
+		pair[1].Delete()
+	}
+	c.targets = map[float32][2]*glx.RenderTarget{}
+	return c.scene.Resize(w, h)
+}
+
+// Start rendering the scene into the offscreen target
+func (c *Chain) Begin() {
+	c.scene.Begin()
+}
+
+// Finish the scene, and run the enabled passes, the last one drawing into
+// the framebuffer and viewport that were bound at Begin
+func (c *Chain) End() error {
+	c.scene.End()
+
+	var enabled []*Pass
+	for _, p := range c.Passes {
+		if !p.Disabled {
+			enabled = append(enabled, p)
+		}
+	}
+	if len(enabled) == 0 {
+		enabled = []*Pass{c.copy}
+	}
+
+	state := glx.StateCache()
+	depth, blend := state.IsEnabled(gl.DEPTH_TEST), state.IsEnabled(gl.BLEND)
+	state.Disable(gl.DEPTH_TEST)
+	state.Disable(gl.BLEND)
+	defer func() {
+		state.SetEnabled(gl.DEPTH_TEST, depth)
+		state.SetEnabled(gl.BLEND, blend)
+	}()
+
+	source := c.scene
+	for i, p := range enabled {
+		if i == len(enabled)-1 {
+			viewport := state.GetViewport()
+			if err := c.draw(p, source, int(viewport[2]), int(viewport[3])); err != nil {
+				return err
+			}
+			break
+		}
+		target, err := c.target(p.Scale, source)
+		if err != nil {
+			return err
+		}
+		target.Begin()
+		target.Discard(gl.COLOR_ATTACHMENT0) // every pixel gets drawn, skip loading the old ones
+		err = c.draw(p, source, target.Width, target.Height)
+		target.End()
+		if err != nil {
+			return err
+		}
+		source = target
+	}
+	return nil
+}
+
+// Return a target of scale that is not source, allocating it on first use
+func (c *Chain) target(scale float32, source *glx.RenderTarget) (*glx.RenderTarget, error) {
+	if scale <= 0 {
+		scale = 1
+	}
+	pair, ok := c.targets[scale]
+	if !ok {
+		w, h := int(float32(c.width)*scale), int(float32(c.height)*scale)
+		if w < 1 {
+			w = 1
+		}
+		if h < 1 {
+			h = 1
+		}
+		options := glx.RenderTargetOptions{Format: c.options.Format, Type: c.options.Type}
+		for i := range pair {
+			target, err := glx.NewRenderTarget(w, h, options)
+			if err != nil {
+				if i > 0 {
+					pair[0].Delete()
+				}
+				return nil, err
+			}
+			pair[i] = target
+		}
+		c.targets[scale] = pair
+	}
+	if pair[0] == source {
+		return pair[1], nil
+	}
+	return pair[0], nil
+}
+
+// Draw p over a w*h viewport, sampling source
+func (c *Chain) draw(p *Pass, source *glx.RenderTarget, w, h int) error {
+	program, u := p.program, p.builtins
+	program.Use()
+	source.Color.BindUnit(0)
+	if u.scene >= 0 {
+		c.scene.Color.BindUnit(1)
+		u.scene.Int(1)
+	}
+	if u.source >= 0 {
+		u.source.Int(0)
+	}
+	if u.resolution >= 0 {
+		u.resolution.Vec2(float32(w), float32(h))
+	}
+	if u.texel >= 0 {
+		u.texel.Vec2(1/float32(source.Width), 1/float32(source.Height))
+	}
+	if u.time >= 0 {
+		u.time.Float(c.Time)
+	}
+	for name, value := range p.Uniforms {
+		if err := program.SetUniform(name, value); err != nil {
+			return fmt.Errorf("fail to run %s pass: %s", p.Name, err)
+		}
+	}
+	c.quad.Draw(program, glx.TriangleStrip)
+	quadLayout.Disable(program)
+	glx.StateCache().ActiveTexture(0)
+	return nil
+}
+
+// Delete the targets and programs of the chain and its passes
+func (c *Chain) Delete() {
+	if c.scene != nil {
+		c.scene.Delete()
+	}
+	for _, pair := range c.targets {
+		for _, target := range pair {
+			if target != nil {
+				target.Delete()
+			}
+		}
+	}
+	for _, p := range append([]*Pass{c.copy}, c.Passes...) {
+		if p != nil && p.program != nil {
+			p.program.Delete()
+			p.program = nil
+		}
+	}
+	if c.quad != nil {
+		c.quad.Delete()
+	}
+}
@@ -0,0 +1,78 @@
+package shader
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"unsafe"
+)
+
+const inotifyMask = syscall.IN_CLOSE_WRITE | syscall.IN_MOVED_TO | syscall.IN_CREATE | syscall.IN_DELETE
+
+// inotify watches directories below root, and reports changed files by
+// their slash separated path relative to root
+type inotify struct {
+	root    string
+	fd      int
+	file    *os.File
+	mu      sync.Mutex
+	watches map[int]string // watch descriptor to directory
+	dirs    map[string]bool
+}
+
+func newInotify(root string) (*inotify, error) {
+	fd, err := syscall.InotifyInit1(syscall.IN_CLOEXEC | syscall.IN_NONBLOCK)
+	if err != nil {
+		return nil, os.NewSyscallError("inotify_init1", err)
+	}
+	// non-blocking, so the runtime poller wakes read up on close. Keep fd
+	// apart, as file.Fd() would switch it back to blocking.
+	file := os.NewFile(uintptr(fd), "inotify")
+	return &inotify{root: root, fd: fd, file: file, watches: map[int]string{}, dirs: map[string]bool{}}, nil
+}
+
+func (n *inotify) add(dir string) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.dirs[dir] {
+		return nil
+	}
+	wd, err := syscall.InotifyAddWatch(n.fd, filepath.Join(n.root, filepath.FromSlash(dir)), inotifyMask)
+	if err != nil {
+		return os.NewSyscallError("inotify_add_watch", err)
+	}
+	n.watches[wd] = dir
+	n.dirs[dir] = true
+	return nil
+}
+
+// Block until events arrive, and return the names of the changed files
+func (n *inotify) read() ([]string, error) {
+	var buf [4096]byte
+	count, err := n.file.Read(buf[:])
+	if err != nil {
+		return nil, err
+	}
+	var ret []string
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	for offset := 0; offset+syscall.SizeofInotifyEvent <= count; {
+		event := (*syscall.InotifyEvent)(unsafe.Pointer(&buf[offset]))
+		start := offset + syscall.SizeofInotifyEvent
+		name := string(buf[start : start+int(event.Len)])
+		offset = start + int(event.Len)
+		for len(name) > 0 && name[len(name)-1] == 0 {
+			name = name[:len(name)-1]
+		}
+		if dir, ok := n.watches[int(event.Wd)]; ok && name != "" {
+			ret = append(ret, path.Join(dir, name))
+		}
+	}
+	return ret, nil
+}
+
+func (n *inotify) close() {
+	n.file.Close()
+}
@@ -0,0 +1,15 @@
+// +build !linux
+
+package shader
+
+import "errors"
+
+type inotify struct{}
+
+func newInotify(root string) (*inotify, error) {
+	return nil, errors.New("inotify not supported")
+}
+
+func (n *inotify) add(dir string) error    { return nil }
+func (n *inotify) read() ([]string, error) { return nil, errors.New("inotify not supported") }
+func (n *inotify) close()                  {}
@@ -0,0 +1,194 @@
+// Package shader loads GLSL ES sources from an fs.FS, resolving
+// #include "file" directives, injecting #defines from Go and prepending a
+// precision header to fragment shaders. It keeps a line map, so that
+// compile errors point to the original file and line.
+package shader
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/FEEDFACE-COM/piglet/glx"
+)
+
+// Precision header prepended to fragment shaders by default. Vertex
+// shaders have a default precision of highp float in GLSL ES.
+const DefaultPrecision = "precision mediump float;"
+
+// A Loader reads shader sources from FS
+type Loader struct {
+	FS        fs.FS
+	Defines   map[string]string // injected as #define name value, sorted by name
+	Precision string            // header of fragment shaders after any #version, empty for none
+}
+
+// Return a loader reading from fsys, with the default precision header
+func NewLoader(fsys fs.FS) *Loader {
+	return &Loader{FS: fsys, Defines: map[string]string{}, Precision: DefaultPrecision}
+}
+
+// A Line is a line of an original source file
+type Line struct {
+	File string
+	Line int
+}
+
+// A Source is a preprocessed shader source, ready to compile
+type Source struct {
+	Text  string
+	Lines []Line // origin of each line of Text; zero for generated lines
+	Files []string
+}
+
+// Return the file and line that line n (1-based) of the source came from,
+// or false for generated lines
+func (s *Source) Origin(n int) (string, int, bool) {
+	if n < 1 || n > len(s.Lines) || s.Lines[n-1].File == "" {
+		return "", 0, false
+	}
+	return s.Lines[n-1].File, s.Lines[n-1].Line, true
+}
+
+type builder struct {
+	loader   *Loader
+	text     strings.Builder
+	lines    []Line
+	included map[string]bool
+	files    []string
+}
+
+func (b *builder) emit(text string, origin Line) {
+	b.text.WriteString(text)
+	b.text.WriteString("\n")
+	b.lines = append(b.lines, origin)
+}
+
+func (b *builder) read(name string) ([]string, error) {
+	buf, err := fs.ReadFile(b.loader.FS, name)
+	if err != nil {
+		return nil, err
+	}
+	b.files = append(b.files, name)
+	return strings.Split(strings.TrimSuffix(string(buf), "\n"), "\n"), nil
+}
+
+// Return the file name of an #include directive, or "" if line is none
+func includeName(line string) (string, error) {
+	line = strings.TrimSpace(line)
+	if !strings.HasPrefix(line, "#") {
+		return "", nil
+	}
+	directive := strings.TrimSpace(line[1:])
+	if !strings.HasPrefix(directive, "include") {
+		return "", nil
+	}
+	arg := strings.TrimSpace(directive[len("include"):])
+	if len(arg) < 2 || arg[0] != '"' || arg[len(arg)-1] != '"' {
+		return "", errors.New(`expect #include "file"`)
+	}
+	return arg[1 : len(arg)-1], nil
+}
+
+// Append the lines of file name, resolving includes relative to it. Each
+// file is included at most once.
+func (b *builder) include(name string, lines []string, first int) error {
+	b.included[name] = true
+	for i := first; i < len(lines); i++ {
+		origin := Line{File: name, Line: i + 1}
+		inc, err := includeName(lines[i])
+		if err != nil {
+			return fmt.Errorf("%s:%d: %s", name, i+1, err)
+		}
+		if inc == "" {
+			b.emit(lines[i], origin)
+			continue
+		}
+		inc = path.Join(path.Dir(name), inc)
+		if b.included[inc] {
+			b.emit("", origin)
+			continue
+		}
+		sub, err := b.read(inc)
+		if err != nil {
+			return fmt.Errorf("%s:%d: fail to include %s: %s", name, i+1, inc, err)
+		}
+		if err := b.include(inc, sub, 0); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Load and preprocess the shader of type xtype called name. A #version
+// line stays first, followed by the precision header of fragment shaders
+// and the defines, then the source with all includes resolved.
+func (l *Loader) Load(xtype glx.ShaderType, name string) (*Source, error) {
+	b := &builder{loader: l, included: map[string]bool{}}
+	lines, err := b.read(name)
+	if err != nil {
+		return nil, err
+	}
+
+	first := 0
+	for first < len(lines) && strings.TrimSpace(lines[first]) == "" {
+		first++
+	}
+	if first < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[first]), "#version") {
+		b.emit(lines[first], Line{File: name, Line: first + 1})
+		first++
+	} else {
+		first = 0
+	}
+
+	if xtype == glx.FragmentShader && l.Precision != "" {
+		for _, line := range strings.Split(strings.TrimSuffix(l.Precision, "\n"), "\n") {
+			b.emit(line, Line{})
+		}
+	}
+	var names []string
+	for define := range l.Defines {
+		names = append(names, define)
+	}
+	sort.Strings(names)
+	for _, define := range names {
+		b.emit(strings.TrimSpace("#define "+define+" "+l.Defines[define]), Line{})
+	}
+
+	if err := b.include(name, lines, first); err != nil {
+		return nil, err
+	}
+	return &Source{Text: b.text.String(), Lines: b.lines, Files: b.files}, nil
+}
+
+// Load, preprocess and compile a vertex and a fragment shader into a
+// program. Compile errors refer to the original files and lines.
+func (l *Loader) CompileProgram(vertexName, fragmentName string) (*glx.Program, error) {
+	program, _, err := l.compile(vertexName, fragmentName)
+	return program, err
+}
+
+// Like CompileProgram, but also return the names of all files read
+func (l *Loader) compile(vertexName, fragmentName string) (*glx.Program, []string, error) {
+	vertex, err := l.Load(glx.VertexShader, vertexName)
+	if err != nil {
+		return nil, nil, err
+	}
+	fragment, err := l.Load(glx.FragmentShader, fragmentName)
+	if err != nil {
+		return nil, vertex.Files, err
+	}
+	files := append(vertex.Files, fragment.Files...)
+	program, err := glx.CompileProgram(vertex.Text, fragment.Text)
+	if cerr, ok := err.(*glx.CompileError); ok {
+		if cerr.Type == glx.VertexShader {
+			cerr.Map = vertex
+		} else {
+			cerr.Map = fragment
+		}
+	}
+	return program, files, err
+}
@@ -0,0 +1,235 @@
+package shader
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/FEEDFACE-COM/piglet/glx"
+)
+
+// Interval at which a Watcher polls without inotify
+const DefaultPollInterval = 500 * time.Millisecond
+
+// A Program is a glx.Program that a Watcher recompiles whenever one of its
+// source files changes. The embedded program is replaced on success, so
+// keep the *Program rather than the *glx.Program.
+type Program struct {
+	*glx.Program
+	Vertex, Fragment string
+	Err              error            // of the last failed reload, nil while up to date
+	OnReload         func(p *Program) // called after a successful reload, eg. to set up attributes again
+	files            []string
+}
+
+type stamp struct {
+	mod  time.Time
+	size int64
+}
+
+// A Watcher monitors the source files of programs, for development. It
+// uses inotify on the directory the loader reads from where available, and
+// polls the loader's FS otherwise. Reloading happens in Update, which must
+// be called on the render thread.
+type Watcher struct {
+	Interval time.Duration // polling interval, DefaultPollInterval if zero
+	Log      io.Writer     // for reload messages, os.Stderr if nil
+
+	loader   *Loader
+	dir      string
+	programs []*Program
+	notify   *inotify
+
+	mu     sync.Mutex // guards the fields below
+	dirty  map[string]bool
+	stamps map[string]stamp
+	done   chan struct{} // closed by Close to stop polling
+}
+
+// Return a watcher recompiling with loader. dir is the OS directory that
+// loader.FS reads from, as in os.DirFS(dir), or "" to always poll.
+func NewWatcher(loader *Loader, dir string) *Watcher {
+	return &Watcher{loader: loader, dir: dir, dirty: map[string]bool{}, stamps: map[string]stamp{}}
+}
+
+func (w *Watcher) logf(format string, args ...interface{}) {
+	out := w.Log
+	if out == nil {
+		out = os.Stderr
+	}
+	fmt.Fprintf(out, format+"\n", args...)
+}
+
+// Compile a program and watch its sources. The initial compile must succeed.
+func (w *Watcher) Load(vertexName, fragmentName string) (*Program, error) {
+	program, files, err := w.loader.compile(vertexName, fragmentName)
+	if err != nil {
+		return nil, err
+	}
+	p := &Program{Program: program, Vertex: vertexName, Fragment: fragmentName}
+	w.programs = append(w.programs, p)
+	w.track(p, files)
+	return p, nil
+}
+
+// Record the files of p, and start watching any new ones
+func (w *Watcher) track(p *Program, files []string) {
+	seen := map[string]bool{}
+	p.files = nil
+	for _, name := range append([]string{p.Vertex, p.Fragment}, files...) {
+		if !seen[name] {
+			seen[name] = true
+			p.files = append(p.files, name)
+		}
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, name := range p.files {
+		if _, ok := w.stamps[name]; ok {
+			continue
+		}
+		w.stamps[name] = w.stat(name)
+		if w.notify != nil {
+			if err := w.notify.add(path.Dir(name)); err != nil {
+				w.logf("shader: fail to watch %s: %s", path.Dir(name), err)
+			}
+		}
+	}
+}
+
+func (w *Watcher) stat(name string) stamp {
+	info, err := fs.Stat(w.loader.FS, name)
+	if err != nil {
+		return stamp{}
+	}
+	return stamp{mod: info.ModTime(), size: info.Size()}
+}
+
+// Start watching in the background, with inotify if possible
+func (w *Watcher) Start() {
+	done := make(chan struct{})
+	w.mu.Lock()
+	w.done = done
+	w.mu.Unlock()
+	if w.dir != "" {
+		notify, err := newInotify(w.dir)
+		if err == nil {
+			w.mu.Lock()
+			w.notify = notify
+			for name := range w.stamps {
+				if err := notify.add(path.Dir(name)); err != nil {
+					w.logf("shader: fail to watch %s: %s", path.Dir(name), err)
+				}
+			}
+			w.mu.Unlock()
+			go w.listen(notify)
+			return
+		}
+		w.logf("shader: fail to start inotify, polling: %s", err)
+	}
+	go w.poll(done)
+}
+
+// Mark changed files as dirty, from inotify events
+func (w *Watcher) listen(notify *inotify) {
+	for {
+		names, err := notify.read()
+		if err != nil {
+			return // closed
+		}
+		w.mu.Lock()
+		for _, name := range names {
+			if _, ok := w.stamps[name]; ok {
+				w.dirty[name] = true
+			}
+		}
+		w.mu.Unlock()
+	}
+}
+
+// Mark changed files as dirty, by comparing modification times and sizes,
+// until done is closed
+func (w *Watcher) poll(done <-chan struct{}) {
+	interval := w.Interval
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+		}
+		w.mu.Lock()
+		for name, old := range w.stamps {
+			if now := w.stat(name); now != old {
+				w.stamps[name] = now
+				w.dirty[name] = true
+			}
+		}
+		w.mu.Unlock()
+	}
+}
+
+// Recompile the programs whose sources changed since the last call, and
+// report whether any was replaced. A program that fails to compile keeps
+// running as it was, and the info log gets printed. Call once per frame,
+// on the render thread.
+func (w *Watcher) Update() bool {
+	w.mu.Lock()
+	dirty := w.dirty
+	if len(dirty) == 0 {
+		w.mu.Unlock()
+		return false
+	}
+	w.dirty = map[string]bool{}
+	w.mu.Unlock()
+
+	reloaded := false
+	for _, p := range w.programs {
+		changed := false
+		for _, name := range p.files {
+			changed = changed || dirty[name]
+		}
+		if !changed {
+			continue
+		}
+		program, files, err := w.loader.compile(p.Vertex, p.Fragment)
+		if err != nil {
+			// files stops at the error, so keep watching the old ones too
+			w.track(p, append(append([]string(nil), p.files...), files...))
+			p.Err = err
+			w.logf("shader: fail to reload %s %s, keep last good program: %s", p.Vertex, p.Fragment, err)
+			continue
+		}
+		w.track(p, files)
+		w.logf("shader: reload %s %s", p.Vertex, p.Fragment)
+		p.Program.Delete()
+		p.Program, p.Err = program, nil
+		reloaded = true
+		if p.OnReload != nil {
+			p.OnReload(p)
+		}
+	}
+	return reloaded
+}
+
+// Stop watching. The programs stay valid.
+func (w *Watcher) Close() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.done != nil {
+		close(w.done)
+		w.done = nil
+	}
+	if w.notify != nil {
+		w.notify.close()
+		w.notify = nil
+	}
+}
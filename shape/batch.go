@@ -0,0 +1,152 @@
+// Package shape draws 2D vector shapes for gauges, charts and panels.
+// Thick lines with joins and caps, polygons, rounded rectangles, circles
+// and arcs are tessellated into triangles with a feathered edge a pixel
+// wide, which antialiases them without multisampling, and collect in one
+// dynamic vertex buffer drawn with a single DrawArrays per frame. Positions
+// are in display pixels with the origin at the top left, like sprites.
+package shape
+
+import (
+	"image/color"
+
+	gl "github.com/FEEDFACE-COM/piglet/gles2"
+	"github.com/FEEDFACE-COM/piglet/glstate"
+	"github.com/FEEDFACE-COM/piglet/glx"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Width of the feathered edge of shapes, in pixels
+const feather = 1
+
+// Pixels a curve may deviate from a true circle
+const tolerance = 0.25
+
+type vertex struct {
+	Position [2]float32
+	Color    [4]uint8 `attrib:"color,normalized"`
+}
+
+const vertexSource = `
+attribute vec2 position;
+attribute vec4 color;
+uniform mat4 projection;
+varying vec4 v_color;
+void main() {
+	v_color = color;
+	gl_Position = projection * vec4(position, 0.0, 1.0);
+}
+`
+
+const fragmentSource = `
+precision mediump float;
+varying vec4 v_color;
+void main() {
+	gl_FragColor = v_color;
+}
+`
+
+// What a Batch drew since Begin
+type Stats struct {
+	Shapes    int
+	Triangles int
+	DrawCalls int
+}
+
+// A Batch collects shapes between Begin and End, and draws them in the
+// order they were added. Colors are not premultiplied; the batch
+// premultiplies them, and blends accordingly.
+type Batch struct {
+	Projection mgl32.Mat4 // display pixels, origin top left, set by NewBatch and Resize
+
+	program  *glx.Program
+	layout   *glx.Layout
+	buffer   *glx.VertexBuffer
+	vertices []vertex
+	stats    Stats
+	saved    glstate.Caps2D // restored by End
+}
+
+// Compile the shape program and create an empty batch, projected onto a
+// display of w*h pixels, eg. from piglet.GetDisplaySize
+func NewBatch(w, h int) (*Batch, error) {
+	layout, err := glx.LayoutOf(vertex{})
+	if err != nil {
+		return nil, err
+	}
+	program, err := glx.CompileProgram(vertexSource, fragmentSource)
+	if err != nil {
+		return nil, err
+	}
+	b := &Batch{
+		program: program,
+		layout:  layout,
+		buffer:  glx.NewVertexBuffer(layout, glx.DynamicDraw),
+	}
+	b.Resize(w, h)
+	return b, nil
+}
+
+// Project shapes onto a display resized to w*h pixels
+func (b *Batch) Resize(w, h int) {
+	b.Projection = mgl32.Ortho2D(0, float32(w), float32(h), 0)
+}
+
+// Start collecting the shapes of a frame. They blend over the scene with
+// premultiplied alpha, regardless of depth and winding.
+func (b *Batch) Begin() {
+	b.stats = Stats{}
+	state := glx.StateCache()
+	b.saved = state.Begin2D()
+	state.BlendFunc(gl.ONE, gl.ONE_MINUS_SRC_ALPHA)
+}
+
+// Draw the shapes collected so far
+func (b *Batch) Flush() {
+	if len(b.vertices) == 0 {
+		return
+	}
+	b.program.Use()
+	b.program.Uniform("projection").Mat4(b.Projection)
+	b.buffer.SetVertices(b.vertices)
+	b.buffer.Draw(b.program, glx.Triangles)
+	b.layout.Disable(b.program)
+	b.stats.Triangles += len(b.vertices) / 3
+	b.stats.DrawCalls++
+	b.vertices = b.vertices[:0]
+}
+
+// Draw the rest of the frame's shapes, and hand depth test, culling and
+// blending back as Begin found them
+func (b *Batch) End() {
+	b.Flush()
+	glx.StateCache().End2D(b.saved)
+}
+
+// Return what the last frame drew, or the current one so far
+func (b *Batch) Stats() Stats {
+	return b.stats
+}
+
+// Delete the buffer and program of the batch
+func (b *Batch) Delete() {
+	b.buffer.Delete()
+	b.program.Delete()
+	b.vertices = nil
+}
+
+// Premultiply c, scaled by alpha for lines thinner than a pixel
+func premultiply(c color.NRGBA, alpha float32) [4]uint8 {
+	a := uint32(float32(c.A)*alpha + 0.5)
+	return [4]uint8{uint8(uint32(c.R) * a / 255), uint8(uint32(c.G) * a / 255), uint8(uint32(c.B) * a / 255), uint8(a)}
+}
+
+// Add a triangle
+func (b *Batch) triangle(p0, p1, p2 mgl32.Vec2, c0, c1, c2 [4]uint8) {
+	b.vertices = append(b.vertices, vertex{p0, c0}, vertex{p1, c1}, vertex{p2, c2})
+}
+
+// Add a quad with corners in order around it
+func (b *Batch) quad(p0, p1, p2, p3 mgl32.Vec2, c0, c1, c2, c3 [4]uint8) {
+	b.triangle(p0, p1, p2, c0, c1, c2)
+	b.triangle(p2, p3, p0, c2, c3, c0)
+}
@@ -0,0 +1,168 @@
+package shape
+
+import (
+	"image/color"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Ratio of miter length to width up to which edges of fills are mitered
+const fillMiterLimit = 4
+
+// Points closer than this are merged
+const epsilon = 1e-3
+
+// Fill the polygon through points with c. Polygons may be concave, but not
+// intersect themselves.
+func (b *Batch) Fill(points []mgl32.Vec2, c color.NRGBA) {
+	pts := clean(points, true)
+	area := signedArea(pts)
+	if len(pts) < 3 || c.A == 0 || area == 0 {
+		return
+	}
+	sign := float32(1)
+	if area < 0 {
+		sign = -1
+	}
+	// inset the polygon by half the feather, and fade out to the same
+	// distance outside, so the edge stays where it was
+	n := len(pts)
+	inner := make([]mgl32.Vec2, n)
+	outer := make([]mgl32.Vec2, n)
+	for i, p := range pts {
+		n0 := normal(p.Sub(pts[(i+n-1)%n]).Normalize()).Mul(-sign)
+		n1 := normal(pts[(i+1)%n].Sub(p).Normalize()).Mul(-sign)
+		m := clampLen(miter(n0, n1), fillMiterLimit)
+		inner[i] = p.Sub(m.Mul(feather / 2))
+		outer[i] = p.Add(m.Mul(feather / 2))
+	}
+	col := premultiply(c, 1)
+	var clear [4]uint8
+	for _, t := range triangulate(pts, sign) {
+		b.triangle(inner[t[0]], inner[t[1]], inner[t[2]], col, col, col)
+	}
+	for i := range pts {
+		j := (i + 1) % n
+		b.quad(inner[i], outer[i], outer[j], inner[j], col, clear, clear, col)
+	}
+	b.stats.Shapes++
+}
+
+// Return points without repeated ones, and without the last if closed and
+// the same as the first
+func clean(points []mgl32.Vec2, closed bool) []mgl32.Vec2 {
+	pts := make([]mgl32.Vec2, 0, len(points))
+	for _, p := range points {
+		if len(pts) == 0 || !near(p, pts[len(pts)-1]) {
+			pts = append(pts, p)
+		}
+	}
+	if closed && len(pts) > 1 && near(pts[0], pts[len(pts)-1]) {
+		pts = pts[:len(pts)-1]
+	}
+	return pts
+}
+
+func near(a, b mgl32.Vec2) bool {
+	return a.Sub(b).Len() < epsilon
+}
+
+// Return twice the area of the polygon, positive if its points go round
+// towards positive angles
+func signedArea(pts []mgl32.Vec2) float32 {
+	var area float32
+	for i, p := range pts {
+		area += cross(p, pts[(i+1)%len(pts)])
+	}
+	return area
+}
+
+func cross(a, b mgl32.Vec2) float32 {
+	return a[0]*b[1] - a[1]*b[0]
+}
+
+// Return the normal of direction d, rotated from it by a quarter turn
+// towards positive angles, which is clockwise on the display
+func normal(d mgl32.Vec2) mgl32.Vec2 {
+	return mgl32.Vec2{-d[1], d[0]}
+}
+
+// Return the offset from a corner that is a unit from both edges with
+// normals n0 and n1
+func miter(n0, n1 mgl32.Vec2) mgl32.Vec2 {
+	d := 1 + n0.Dot(n1)
+	if d < epsilon {
+		// the edges reverse, the miter is infinitely far ahead
+		return n0.Add(n1).Mul(1 / epsilon)
+	}
+	return n0.Add(n1).Mul(1 / d)
+}
+
+// Return v shortened to at most max
+func clampLen(v mgl32.Vec2, max float32) mgl32.Vec2 {
+	if l := v.Len(); l > max {
+		return v.Mul(max / l)
+	}
+	return v
+}
+
+// Triangulate the polygon pts, whose area has sign, into the indices of
+// triangles. Convex polygons are fanned, others clipped ear by ear.
+func triangulate(pts []mgl32.Vec2, sign float32) [][3]int {
+	n := len(pts)
+	tris := make([][3]int, 0, n-2)
+	convex := true
+	for i := range pts {
+		if turn(pts[(i+n-1)%n], pts[i], pts[(i+1)%n])*sign < 0 {
+			convex = false
+			break
+		}
+	}
+	if convex {
+		for i := 1; i < n-1; i++ {
+			tris = append(tris, [3]int{0, i, i + 1})
+		}
+		return tris
+	}
+	idx := make([]int, n)
+	for i := range idx {
+		idx[i] = i
+	}
+	for len(idx) > 3 {
+		ear := -1
+		for i := range idx {
+			a, b, c := idx[(i+len(idx)-1)%len(idx)], idx[i], idx[(i+1)%len(idx)]
+			if turn(pts[a], pts[b], pts[c])*sign <= 0 {
+				continue
+			}
+			inside := false
+			for _, k := range idx {
+				if k != a && k != b && k != c && inTriangle(pts[k], pts[a], pts[b], pts[c], sign) {
+					inside = true
+					break
+				}
+			}
+			if !inside {
+				ear = i
+				break
+			}
+		}
+		if ear < 0 {
+			// only degenerate corners left, clip any
+			ear = 0
+		}
+		tris = append(tris, [3]int{idx[(ear+len(idx)-1)%len(idx)], idx[ear], idx[(ear+1)%len(idx)]})
+		idx = append(idx[:ear], idx[ear+1:]...)
+	}
+	return append(tris, [3]int{idx[0], idx[1], idx[2]})
+}
+
+// Return how far the corner at b turns, positive towards positive angles
+func turn(a, b, c mgl32.Vec2) float32 {
+	return cross(b.Sub(a), c.Sub(b))
+}
+
+// Report whether p is in or on the triangle a,b,c, whose area has sign
+func inTriangle(p, a, b, c mgl32.Vec2, sign float32) bool {
+	return turn(a, b, p)*sign >= 0 && turn(b, c, p)*sign >= 0 && turn(c, a, p)*sign >= 0
+}
@@ -0,0 +1,75 @@
+package shape
+
+import (
+	"math"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Return the number of segments approximating an arc of radius r over
+// angle radians within tolerance
+func segments(r, angle float32) int {
+	r = float32(math.Abs(float64(r)))
+	angle = float32(math.Abs(float64(angle)))
+	if r <= tolerance {
+		return 1
+	}
+	step := 2 * math.Acos(float64(1-tolerance/r))
+	n := int(math.Ceil(float64(angle) / step))
+	if n < 1 {
+		n = 1
+	}
+	return n
+}
+
+// Append the points of an arc about x,y of radius r, from angle start to
+// end, clockwise from the right in radians, including both ends
+func appendArc(points []mgl32.Vec2, x, y, r, start, end float32) []mgl32.Vec2 {
+	n := segments(r, end-start)
+	for i := 0; i <= n; i++ {
+		a := float64(start + (end-start)*float32(i)/float32(n))
+		sin, cos := math.Sincos(a)
+		points = append(points, mgl32.Vec2{x + r*float32(cos), y + r*float32(sin)})
+	}
+	return points
+}
+
+// Return the points of an arc about x,y of radius r, from angle start to
+// end, clockwise from the right in radians, for Stroke or Fill
+func Arc(x, y, r, start, end float32) []mgl32.Vec2 {
+	return appendArc(nil, x, y, r, start, end)
+}
+
+// Return the points of a circle about x,y of radius r
+func Circle(x, y, r float32) []mgl32.Vec2 {
+	points := Arc(x, y, r, 0, 2*math.Pi)
+	return points[:len(points)-1]
+}
+
+// Return the points of a sector of a circle about x,y of radius r, from
+// angle start to end, with the center, eg. for a pie chart
+func Sector(x, y, r, start, end float32) []mgl32.Vec2 {
+	return appendArc([]mgl32.Vec2{{x, y}}, x, y, r, start, end)
+}
+
+// Return the corners of a w*h rectangle at x,y
+func Rect(x, y, w, h float32) []mgl32.Vec2 {
+	return []mgl32.Vec2{{x, y}, {x + w, y}, {x + w, y + h}, {x, y + h}}
+}
+
+// Return the points of a w*h rectangle at x,y with corners rounded to
+// radius r, at most half the shorter side
+func RoundedRect(x, y, w, h, r float32) []mgl32.Vec2 {
+	if m := float32(math.Min(float64(w), float64(h))) / 2; r > m {
+		r = m
+	}
+	if r <= 0 {
+		return Rect(x, y, w, h)
+	}
+	points := make([]mgl32.Vec2, 0, 4*(segments(r, math.Pi/2)+1))
+	points = appendArc(points, x+w-r, y+r, r, -math.Pi/2, 0)
+	points = appendArc(points, x+w-r, y+h-r, r, 0, math.Pi/2)
+	points = appendArc(points, x+r, y+h-r, r, math.Pi/2, math.Pi)
+	points = appendArc(points, x+r, y+r, r, math.Pi, 3*math.Pi/2)
+	return points
+}
@@ -0,0 +1,230 @@
+package shape
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// How lines meet at corners
+type Join int
+
+const (
+	JoinMiter Join = iota // extended to a point, beveled beyond the miter limit
+	JoinBevel             // cut off straight
+	JoinRound             // rounded
+)
+
+// How open lines end
+type Cap int
+
+const (
+	CapButt   Cap = iota // flat at the end point
+	CapSquare            // flat, half the width beyond the end point
+	CapRound             // rounded about the end point
+)
+
+// A Stroke describes how lines are drawn
+type Stroke struct {
+	Width      float32 // in pixels, 1 if zero; thinner lines fade instead
+	Color      color.NRGBA
+	Join       Join
+	Cap        Cap
+	MiterLimit float32 // ratio of miter length to width beyond which miters are beveled, 4 if zero
+	Closed     bool    // join the last point back to the first
+}
+
+// A cross section of a stroke at p, with its sides in directions left and
+// right, scaled to reach the inner and outer edge of the feather
+type section struct {
+	p, left, right mgl32.Vec2
+	fade           bool // clear across, at the ends of butt and square caps
+}
+
+// Tessellates a stroke into quads between its cross sections
+type stroker struct {
+	b            *Batch
+	color        [4]uint8
+	inner, outer float32 // distance of the feather edges from the center
+	first, last  *section
+}
+
+// Draw a line from x0,y0 to x1,y1
+func (b *Batch) Line(x0, y0, x1, y1 float32, s Stroke) {
+	b.Stroke([]mgl32.Vec2{{x0, y0}, {x1, y1}}, s)
+}
+
+// Draw a line through points, eg. of Arc or RoundedRect, closed or not
+func (b *Batch) Stroke(points []mgl32.Vec2, s Stroke) {
+	pts := clean(points, s.Closed)
+	closed := s.Closed && len(pts) > 2
+	if len(pts) < 2 || s.Color.A == 0 {
+		return
+	}
+	width := s.Width
+	if width == 0 {
+		width = 1
+	}
+	alpha := float32(1)
+	if width < feather {
+		alpha = width / feather
+		width = feather
+	}
+	limit := s.MiterLimit
+	if limit == 0 {
+		limit = 4
+	}
+	st := &stroker{b: b, color: premultiply(s.Color, alpha), inner: (width - feather) / 2, outer: (width + feather) / 2}
+	n := len(pts)
+	dir := func(i int) (mgl32.Vec2, float32) {
+		d := pts[(i+1)%n].Sub(pts[i%n])
+		l := d.Len()
+		return d.Mul(1 / l), l
+	}
+	if closed {
+		for i := 0; i < n; i++ {
+			d0, l0 := dir(i + n - 1)
+			d1, l1 := dir(i)
+			st.join(pts[i], d0, d1, l0, l1, s.Join, limit)
+		}
+		st.add(*st.first)
+	} else {
+		d, _ := dir(0)
+		st.startCap(pts[0], d, s.Cap, width/2)
+		for i := 1; i < n-1; i++ {
+			d0, l0 := dir(i - 1)
+			d1, l1 := dir(i)
+			st.join(pts[i], d0, d1, l0, l1, s.Join, limit)
+		}
+		d, _ = dir(n - 2)
+		st.endCap(pts[n-1], d, s.Cap, width/2)
+	}
+	b.stats.Shapes++
+}
+
+// Add a cross section, and the quads from the last one
+func (st *stroker) add(s section) {
+	if st.last != nil {
+		a := st.last
+		// fade along the line from a clear section to the next
+		var clear [4]uint8
+		ca, cb := st.color, st.color
+		if a.fade {
+			ca = clear
+		}
+		if s.fade {
+			cb = clear
+		}
+		st.b.quad(a.p.Add(a.left.Mul(st.outer)), a.p.Add(a.left.Mul(st.inner)), s.p.Add(s.left.Mul(st.inner)), s.p.Add(s.left.Mul(st.outer)), clear, ca, cb, clear)
+		st.b.quad(a.p.Add(a.left.Mul(st.inner)), a.p.Add(a.right.Mul(st.inner)), s.p.Add(s.right.Mul(st.inner)), s.p.Add(s.left.Mul(st.inner)), ca, ca, cb, cb)
+		st.b.quad(a.p.Add(a.right.Mul(st.inner)), a.p.Add(a.right.Mul(st.outer)), s.p.Add(s.right.Mul(st.outer)), s.p.Add(s.right.Mul(st.inner)), ca, clear, clear, cb)
+	} else {
+		st.first = &s
+	}
+	st.last = &s
+}
+
+// Add the cross sections of a corner at p, from direction d0 with length
+// l0 to d1 with length l1
+func (st *stroker) join(p, d0, d1 mgl32.Vec2, l0, l1 float32, join Join, limit float32) {
+	n0, n1 := normal(d0), normal(d1)
+	t := cross(d0, d1)
+	if math.Abs(float64(t)) < epsilon && d0.Dot(d1) > 0 {
+		st.add(section{p: p, left: n0, right: n0.Mul(-1)})
+		return
+	}
+	m := miter(n0, n1)
+	// the inner side reaches no further back than the shorter segment
+	in := m
+	if l := m.Len() * st.outer; l > l0 || l > l1 {
+		in = m.Mul(float32(math.Min(float64(l0), float64(l1))) / l)
+	}
+	// the corner turns towards the left normals if t > 0, and the right
+	// side goes round the outside
+	side := float32(1)
+	if t > 0 {
+		side = -1
+	}
+	out0, out1 := n0.Mul(side), n1.Mul(side)
+	in = in.Mul(-side)
+	if join == JoinMiter && m.Len() <= limit {
+		st.corner(p, m.Mul(side), in, side)
+		return
+	}
+	if join == JoinRound {
+		angle := math.Acos(math.Max(-1, math.Min(1, float64(n0.Dot(n1)))))
+		k := segments(st.outer, float32(angle))
+		for i := 0; i <= k; i++ {
+			a := angle * float64(i) / float64(k)
+			if t < 0 {
+				a = -a
+			}
+			sin, cos := math.Sincos(a)
+			u := mgl32.Vec2{out0[0]*float32(cos) - out0[1]*float32(sin), out0[0]*float32(sin) + out0[1]*float32(cos)}
+			st.corner(p, u, in, side)
+		}
+		return
+	}
+	st.corner(p, out0, in, side)
+	st.corner(p, out1, in, side)
+}
+
+// Add a cross section of a corner with the outer side in direction out,
+// and the inner side in direction in
+func (st *stroker) corner(p, out, in mgl32.Vec2, side float32) {
+	if side > 0 {
+		st.add(section{p: p, left: out, right: in})
+	} else {
+		st.add(section{p: p, left: in, right: out})
+	}
+}
+
+// Add the cap at the start point p of a line in direction d, with half
+// the width of the line hw
+func (st *stroker) startCap(p, d mgl32.Vec2, c Cap, hw float32) {
+	n := normal(d)
+	switch c {
+	case CapRound:
+		st.round(p, n, d.Mul(-1))
+		st.add(section{p: p, left: n, right: n.Mul(-1)})
+	case CapSquare:
+		p = p.Sub(d.Mul(hw))
+		fallthrough
+	default:
+		st.add(section{p: p.Sub(d.Mul(feather / 2)), left: n, right: n.Mul(-1), fade: true})
+		st.add(section{p: p.Add(d.Mul(feather / 2)), left: n, right: n.Mul(-1)})
+	}
+}
+
+// Add the cap at the end point p of a line in direction d
+func (st *stroker) endCap(p, d mgl32.Vec2, c Cap, hw float32) {
+	n := normal(d)
+	switch c {
+	case CapRound:
+		st.add(section{p: p, left: n, right: n.Mul(-1)})
+		st.round(p, n, d)
+	case CapSquare:
+		p = p.Add(d.Mul(hw))
+		fallthrough
+	default:
+		st.add(section{p: p.Sub(d.Mul(feather / 2)), left: n, right: n.Mul(-1)})
+		st.add(section{p: p.Add(d.Mul(feather / 2)), left: n, right: n.Mul(-1), fade: true})
+	}
+}
+
+// Add a half disc about p, from direction n through d to -n
+func (st *stroker) round(p, n, d mgl32.Vec2) {
+	var clear [4]uint8
+	k := segments(st.outer, math.Pi)
+	var prev mgl32.Vec2
+	for i := 0; i <= k; i++ {
+		sin, cos := math.Sincos(math.Pi * float64(i) / float64(k))
+		u := n.Mul(float32(cos)).Add(d.Mul(float32(sin)))
+		if i > 0 {
+			st.b.triangle(p, p.Add(prev.Mul(st.inner)), p.Add(u.Mul(st.inner)), st.color, st.color, st.color)
+			st.b.quad(p.Add(prev.Mul(st.inner)), p.Add(prev.Mul(st.outer)), p.Add(u.Mul(st.outer)), p.Add(u.Mul(st.inner)), st.color, clear, clear, st.color)
+		}
+		prev = u
+	}
+}
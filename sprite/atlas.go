@@ -0,0 +1,198 @@
+package sprite
+
+import (
+	"fmt"
+	"image"
+	"image/draw"
+	"sort"
+
+	"github.com/FEEDFACE-COM/piglet/glx"
+	"github.com/FEEDFACE-COM/piglet/texture"
+)
+
+// Largest atlas Pack creates, the texture size limit of VideoCore IV
+const MaxAtlasSize = 2048
+
+// A Region is a rectangle of a texture, drawn as a sprite
+type Region struct {
+	Texture        glx.Texture
+	U0, V0, U1, V1 float32 // texture coordinates of the top left and bottom right corners
+	Width, Height  float32 // in pixels
+}
+
+// Return a region covering a whole texture of w*h pixels, as loaded by
+// texture.Load with rows flipped to GL orientation
+func Whole(tex glx.Texture, w, h int) Region {
+	return Region{Texture: tex, U0: 0, V0: 1, U1: 1, V1: 0, Width: float32(w), Height: float32(h)}
+}
+
+// Return the part of the region within rect, in pixels from its top left
+func (r Region) Sub(rect image.Rectangle) Region {
+	du, dv := (r.U1-r.U0)/r.Width, (r.V1-r.V0)/r.Height
+	return Region{
+		Texture: r.Texture,
+		U0:      r.U0 + du*float32(rect.Min.X),
+		V0:      r.V0 + dv*float32(rect.Min.Y),
+		U1:      r.U0 + du*float32(rect.Max.X),
+		V1:      r.V0 + dv*float32(rect.Max.Y),
+		Width:   float32(rect.Dx()),
+		Height:  float32(rect.Dy()),
+	}
+}
+
+// An Atlas is a texture holding many images, drawn as named regions
+type Atlas struct {
+	Texture       glx.Texture
+	Width, Height int
+
+	whole   Region
+	regions map[string]Region
+}
+
+// Return an atlas of a w*h texture. Set noFlip if the texture was uploaded
+// with texture.Options.NoFlip, its first row at the top.
+func NewAtlas(tex glx.Texture, w, h int, noFlip bool) *Atlas {
+	a := &Atlas{Texture: tex, Width: w, Height: h, whole: Whole(tex, w, h), regions: map[string]Region{}}
+	if noFlip {
+		a.whole.V0, a.whole.V1 = 0, 1
+	}
+	return a
+}
+
+// Return the region of the atlas within rect, in pixels from its top left
+func (a *Atlas) Sub(rect image.Rectangle) Region {
+	return a.whole.Sub(rect)
+}
+
+// Name the region of the atlas within rect, and return it
+func (a *Atlas) Add(name string, rect image.Rectangle) Region {
+	r := a.Sub(rect)
+	a.regions[name] = r
+	return r
+}
+
+// Return the region called name
+func (a *Atlas) Region(name string) (Region, bool) {
+	r, ok := a.regions[name]
+	return r, ok
+}
+
+// Return the names of the regions, sorted
+func (a *Atlas) Names() []string {
+	ret := make([]string, 0, len(a.regions))
+	for name := range a.regions {
+		ret = append(ret, name)
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+// Delete the texture of the atlas
+func (a *Atlas) Delete() {
+	a.Texture.Delete()
+}
+
+// A Packer places rectangles in rows of a w*h area, for building atlases
+type Packer struct {
+	Width, Height int
+	Padding       int // pixels between rectangles, against bleeding with linear filtering
+
+	shelves []image.Rectangle // rows, Max.X is where the next rectangle goes
+}
+
+// Return a packer for an area of w*h pixels
+func NewPacker(w, h, padding int) *Packer {
+	return &Packer{Width: w, Height: h, Padding: padding}
+}
+
+// Return a free w*h rectangle, or false if there is no room
+func (p *Packer) Place(w, h int) (image.Rectangle, bool) {
+	best := -1
+	for i, shelf := range p.shelves {
+		if h <= shelf.Dy() && shelf.Max.X+w <= p.Width && (best < 0 || shelf.Dy() < p.shelves[best].Dy()) {
+			best = i
+		}
+	}
+	if best < 0 {
+		y := 0
+		if n := len(p.shelves); n > 0 {
+			y = p.shelves[n-1].Max.Y + p.Padding
+		}
+		if y+h > p.Height || w > p.Width {
+			return image.Rectangle{}, false
+		}
+		p.shelves = append(p.shelves, image.Rect(0, y, 0, y+h))
+		best = len(p.shelves) - 1
+	}
+	shelf := &p.shelves[best]
+	rect := image.Rect(shelf.Max.X, shelf.Min.Y, shelf.Max.X+w, shelf.Min.Y+h)
+	shelf.Max.X += w + p.Padding
+	return rect, true
+}
+
+// Forget all rectangles placed
+func (p *Packer) Reset() {
+	p.shelves = p.shelves[:0]
+}
+
+// Pack images into the smallest power of two atlas they fit, with padding
+// pixels between them, and upload it with opts
+func Pack(images map[string]image.Image, padding int, opts texture.Options) (*Atlas, error) {
+	names := make([]string, 0, len(images))
+	area := 0
+	for name, img := range images {
+		names = append(names, name)
+		size := img.Bounds().Size()
+		area += (size.X + padding) * (size.Y + padding)
+	}
+	// tallest first, so rows fill evenly
+	sort.Slice(names, func(i, j int) bool {
+		hi, hj := images[names[i]].Bounds().Dy(), images[names[j]].Bounds().Dy()
+		return hi > hj || hi == hj && names[i] < names[j]
+	})
+	w, h := 1, 1
+	for w*h < area {
+		if w <= h {
+			w *= 2
+		} else {
+			h *= 2
+		}
+	}
+	for {
+		if w > MaxAtlasSize || h > MaxAtlasSize {
+			return nil, fmt.Errorf("fail to pack %d images: they do not fit %dx%d", len(images), MaxAtlasSize, MaxAtlasSize)
+		}
+		if rects, ok := place(images, names, w, h, padding); ok {
+			canvas := image.NewNRGBA(image.Rect(0, 0, w, h))
+			for _, name := range names {
+				img := images[name]
+				draw.Draw(canvas, rects[name], img, img.Bounds().Min, draw.Src)
+			}
+			a := NewAtlas(texture.Load(canvas, opts), w, h, opts.NoFlip)
+			for _, name := range names {
+				a.Add(name, rects[name])
+			}
+			return a, nil
+		}
+		if w <= h {
+			w *= 2
+		} else {
+			h *= 2
+		}
+	}
+}
+
+// Place images in a w*h area, in the order of names
+func place(images map[string]image.Image, names []string, w, h, padding int) (map[string]image.Rectangle, bool) {
+	packer := NewPacker(w, h, padding)
+	rects := map[string]image.Rectangle{}
+	for _, name := range names {
+		size := images[name].Bounds().Size()
+		rect, ok := packer.Place(size.X, size.Y)
+		if !ok {
+			return nil, false
+		}
+		rects[name] = rect
+	}
+	return rects, true
+}
@@ -0,0 +1,301 @@
+// Package sprite draws 2D images in batches. Textured, tinted, scaled and
+// rotated quads accumulate in one dynamic vertex buffer per texture, and
+// each texture draws with a single DrawElements, in display pixels with
+// the origin at the top left.
+package sprite
+
+import (
+	"image/color"
+	"math"
+
+	gl "github.com/FEEDFACE-COM/piglet/gles2"
+	"github.com/FEEDFACE-COM/piglet/glstate"
+	"github.com/FEEDFACE-COM/piglet/glx"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// Quads drawn with one DrawElements, as many as 16 bit indices reach
+const MaxQuads = 65536 / 4
+
+// Frames a texture's vertex buffer is kept while the texture is not drawn
+const idleFrames = 60
+
+type vertex struct {
+	Position [2]float32
+	TexCoord [2]float32
+	Color    [4]uint8 `attrib:"color,normalized"`
+}
+
+const vertexSource = `
+attribute vec2 position;
+attribute vec2 texCoord;
+attribute vec4 color;
+uniform mat4 projection;
+varying vec2 v_texcoord;
+varying vec4 v_color;
+void main() {
+	v_texcoord = texCoord;
+	v_color = color;
+	gl_Position = projection * vec4(position, 0.0, 1.0);
+}
+`
+
+const fragmentSource = `
+void main() {
+	gl_FragColor = texture2D(image, v_texcoord) * v_color;
+}
+`
+
+// A Sprite is a region drawn with a transform and a tint
+type Sprite struct {
+	Region           Region
+	X, Y             float32     // position of the origin on the display
+	OriginX, OriginY float32     // point placed at X,Y and rotated about, 0 at the left or top to 1 at the right or bottom
+	Width, Height    float32     // size on the display, of the region if zero
+	Rotation         float32     // clockwise, in radians
+	Color            color.NRGBA // multiplies the texture, white if zero
+}
+
+// Statistics of a Batch, from Begin to End
+type Stats struct {
+	Sprites   int
+	Textures  int // switched to, once per texture unless flushed in between
+	DrawCalls int
+}
+
+// Textures and shaders collect quads separately
+type batchKey struct {
+	texture uint32
+	shader  *Shader
+}
+
+// The quads of one texture and shader
+type texBatch struct {
+	texture  glx.Texture
+	shader   *Shader
+	vertices []vertex
+	buffer   *glx.VertexBuffer
+	idle     int
+}
+
+// A Batch collects sprites between Begin and End. Sprites of the same
+// texture and shader draw in order, and textures and shaders in the order
+// they are first used since the last flush, so call Flush where sprites of
+// different textures must overlap in a certain order.
+type Batch struct {
+	Projection    mgl32.Mat4 // display pixels, origin top left, set by NewBatch and Resize
+	Premultiplied bool       // textures have premultiplied alpha, as with texture.Options.Premultiply
+	Shader        *Shader    // draws the sprites added from now on, the default one if nil
+
+	shader  *Shader
+	layout  *glx.Layout
+	indices *glx.IndexBuffer
+	quads   int // in indices
+	batches map[batchKey]*texBatch
+	order   []*texBatch
+	stats   Stats
+	saved   glstate.Caps2D // restored by End
+}
+
+// Create a batch for a display of w*h pixels, eg. from piglet.GetDisplaySize
+func NewBatch(w, h int) (*Batch, error) {
+	layout, err := glx.LayoutOf(vertex{})
+	if err != nil {
+		return nil, err
+	}
+	shader, err := NewShader(fragmentSource)
+	if err != nil {
+		return nil, err
+	}
+	b := &Batch{
+		shader:  shader,
+		layout:  layout,
+		indices: glx.NewIndexBuffer(glx.StaticDraw),
+		batches: map[batchKey]*texBatch{},
+	}
+	b.Resize(w, h)
+	return b, nil
+}
+
+// Set the projection for a display of w*h pixels
+func (b *Batch) Resize(w, h int) {
+	b.Projection = mgl32.Ortho2D(0, float32(w), float32(h), 0)
+}
+
+// Start a frame of sprites. Blending is enabled, and depth test and face
+// culling disabled, until End.
+func (b *Batch) Begin() {
+	b.stats = Stats{}
+	state := glx.StateCache()
+	b.saved = state.Begin2D()
+	if b.Premultiplied {
+		state.BlendFunc(gl.ONE, gl.ONE_MINUS_SRC_ALPHA)
+	} else {
+		state.BlendFunc(gl.SRC_ALPHA, gl.ONE_MINUS_SRC_ALPHA)
+	}
+}
+
+// Draw a region with its top left corner at x,y, at its size
+func (b *Batch) Draw(r Region, x, y float32) {
+	b.DrawRect(r, x, y, r.Width, r.Height, color.NRGBA{})
+}
+
+// Draw a region stretched over a w*h rectangle at x,y, tinted by c unless
+// zero
+func (b *Batch) DrawRect(r Region, x, y, w, h float32, c color.NRGBA) {
+	b.add(r, [4][2]float32{{x, y}, {x + w, y}, {x + w, y + h}, {x, y + h}}, c)
+}
+
+// Draw a sprite
+func (b *Batch) DrawSprite(s *Sprite) {
+	w, h := s.Width, s.Height
+	if w == 0 {
+		w = s.Region.Width
+	}
+	if h == 0 {
+		h = s.Region.Height
+	}
+	x0, y0 := -s.OriginX*w, -s.OriginY*h
+	corners := [4][2]float32{{x0, y0}, {x0 + w, y0}, {x0 + w, y0 + h}, {x0, y0 + h}}
+	sin, cos := float32(0), float32(1)
+	if s.Rotation != 0 {
+		sn, cs := math.Sincos(float64(s.Rotation))
+		sin, cos = float32(sn), float32(cs)
+	}
+	for i, p := range corners {
+		corners[i] = [2]float32{s.X + p[0]*cos - p[1]*sin, s.Y + p[0]*sin + p[1]*cos}
+	}
+	b.add(s.Region, corners, s.Color)
+}
+
+// Add a quad with corners clockwise from the top left of the region
+func (b *Batch) add(r Region, corners [4][2]float32, c color.NRGBA) {
+	shader := b.Shader
+	if shader == nil {
+		shader = b.shader
+	}
+	key := batchKey{r.Texture.Name(), shader}
+	tb := b.batches[key]
+	if tb == nil {
+		tb = &texBatch{texture: r.Texture, shader: shader, buffer: glx.NewVertexBuffer(b.layout, glx.DynamicDraw)}
+		b.batches[key] = tb
+	}
+	if len(tb.vertices) == 0 {
+		b.order = append(b.order, tb)
+		b.stats.Textures++
+	}
+	tb.idle = 0
+	if c == (color.NRGBA{}) {
+		c = color.NRGBA{255, 255, 255, 255}
+	}
+	rgba := [4]uint8{c.R, c.G, c.B, c.A}
+	if b.Premultiplied {
+		for i := 0; i < 3; i++ {
+			rgba[i] = uint8(uint32(rgba[i]) * uint32(c.A) / 255)
+		}
+	}
+	uv := [4][2]float32{{r.U0, r.V0}, {r.U1, r.V0}, {r.U1, r.V1}, {r.U0, r.V1}}
+	for i := range corners {
+		tb.vertices = append(tb.vertices, vertex{Position: corners[i], TexCoord: uv[i], Color: rgba})
+	}
+	b.stats.Sprites++
+}
+
+// Make the index buffer hold n quads
+func (b *Batch) reserve(n int) {
+	if n <= b.quads {
+		return
+	}
+	quads := 64
+	for quads < n {
+		quads *= 2
+	}
+	if quads > MaxQuads {
+		quads = MaxQuads
+	}
+	indices := make([]uint16, 0, quads*6)
+	for i := 0; i < quads; i++ {
+		v := uint16(i * 4)
+		indices = append(indices, v, v+1, v+2, v+2, v+3, v)
+	}
+	b.indices.SetIndices(indices)
+	b.quads = quads
+}
+
+// Draw the sprites collected so far. Fails if a uniform of a shader can
+// not be set, after drawing all sprites.
+func (b *Batch) Flush() error {
+	var err error
+	var shader *Shader
+	var program *glx.Program
+	for _, tb := range b.order {
+		if tb.shader != shader {
+			shader = tb.shader
+			if program != shader.program {
+				if program != nil {
+					b.layout.Disable(program)
+				}
+				program = shader.program
+				program.Use()
+				program.Uniform("projection").Mat4(b.Projection)
+				program.Uniform("image").Int(0)
+			}
+			for name, value := range shader.Uniforms {
+				if e := program.SetUniform(name, value); e != nil && err == nil {
+					err = e
+				}
+			}
+		}
+		quads := len(tb.vertices) / 4
+		b.reserve(quads)
+		tb.buffer.SetVertices(tb.vertices)
+		tb.texture.BindUnit(0)
+		for first := 0; first < quads; first += MaxQuads {
+			n := quads - first
+			if n > MaxQuads {
+				n = MaxQuads
+			}
+			tb.buffer.Bind()
+			b.layout.Enable(program, first*4)
+			b.indices.DrawRange(glx.Triangles, 0, n*6)
+			b.stats.DrawCalls++
+		}
+		tb.vertices = tb.vertices[:0]
+	}
+	if program != nil {
+		b.layout.Disable(program)
+	}
+	b.order = b.order[:0]
+	return err
+}
+
+// Draw the sprites collected, and restore the state changed by Begin.
+// Vertex buffers of textures not drawn for a while are deleted.
+func (b *Batch) End() error {
+	err := b.Flush()
+	glx.StateCache().End2D(b.saved)
+	for key, tb := range b.batches {
+		if tb.idle++; tb.idle > idleFrames {
+			tb.buffer.Delete()
+			delete(b.batches, key)
+		}
+	}
+	return err
+}
+
+// Return the statistics of the last frame, or of the current one so far
+func (b *Batch) Stats() Stats {
+	return b.stats
+}
+
+// Delete the buffers and default shader of the batch. Textures and other
+// shaders belong to the caller.
+func (b *Batch) Delete() {
+	for _, tb := range b.batches {
+		tb.buffer.Delete()
+	}
+	b.batches = map[batchKey]*texBatch{}
+	b.order = nil
+	b.indices.Delete()
+	b.shader.Delete()
+}
@@ -0,0 +1,62 @@
+package sprite
+
+import (
+	"fmt"
+
+	"github.com/FEEDFACE-COM/piglet/glx"
+)
+
+// Header of the fragment shaders of a Shader
+const fragmentHeader = `precision mediump float;
+uniform sampler2D image;
+varying vec2 v_texcoord;
+varying vec4 v_color;
+`
+
+// A Shader draws sprites with another fragment shader, eg. for distance
+// field text. The shader samples image at v_texcoord, and gets the tint in
+// v_color, premultiplied if the batch is.
+type Shader struct {
+	Uniforms map[string]interface{} // set with glx.Program.SetUniform before each draw
+
+	program *glx.Program
+}
+
+// Compile a shader from the body of a fragment shader, after a header
+// declaring precision, image, v_texcoord and v_color
+func NewShader(fragment string) (*Shader, error) {
+	program, err := glx.CompileProgram(vertexSource, fragmentHeader+fragment)
+	if err != nil {
+		return nil, fmt.Errorf("fail to compile sprite shader: %s", err)
+	}
+	return &Shader{program: program}, nil
+}
+
+// Set a uniform of the shader, for the sprites not yet flushed and on
+func (s *Shader) Set(name string, value interface{}) {
+	if s.Uniforms == nil {
+		s.Uniforms = map[string]interface{}{}
+	}
+	s.Uniforms[name] = value
+}
+
+// Return a shader sharing the program, with a copy of the uniforms, to draw
+// with other uniforms in the same batch. Delete only the original.
+func (s *Shader) Copy() *Shader {
+	c := &Shader{program: s.program, Uniforms: map[string]interface{}{}}
+	for name, value := range s.Uniforms {
+		c.Uniforms[name] = value
+	}
+	return c
+}
+
+// Return the program of the shader
+func (s *Shader) Program() *glx.Program { return s.program }
+
+// Delete the program of the shader
+func (s *Shader) Delete() {
+	if s.program != nil {
+		s.program.Delete()
+		s.program = nil
+	}
+}
@@ -0,0 +1,159 @@
+package text
+
+import (
+	"fmt"
+	"image"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/sfnt"
+	"golang.org/x/image/math/fixed"
+	"golang.org/x/image/vector"
+)
+
+// A face rasterizes the glyphs of a TrueType or OpenType font at one size
+type face struct {
+	font    *sfnt.Font
+	buf     sfnt.Buffer
+	ppem    fixed.Int26_6
+	metrics font.Metrics
+	raster  vector.Rasterizer
+}
+
+// Return a face of a TrueType or OpenType font, eg. goregular.TTF from
+// golang.org/x/image/font/gofont, at size pixels per em
+func NewFace(data []byte, size float64) (font.Face, error) {
+	f, err := sfnt.Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("fail to parse font: %v", err)
+	}
+	ret := &face{font: f, ppem: fixed.Int26_6(size * 64)}
+	ret.metrics, err = f.Metrics(&ret.buf, ret.ppem, font.HintingNone)
+	if err != nil {
+		return nil, fmt.Errorf("fail to get font metrics: %v", err)
+	}
+	return ret, nil
+}
+
+func (f *face) Close() error { return nil }
+
+func (f *face) Metrics() font.Metrics { return f.metrics }
+
+func (f *face) index(r rune) (sfnt.GlyphIndex, bool) {
+	x, err := f.font.GlyphIndex(&f.buf, r)
+	return x, err == nil && x != 0
+}
+
+func (f *face) Kern(r0, r1 rune) fixed.Int26_6 {
+	x0, ok0 := f.index(r0)
+	x1, ok1 := f.index(r1)
+	if !ok0 || !ok1 {
+		return 0
+	}
+	k, err := f.font.Kern(&f.buf, x0, x1, f.ppem, font.HintingNone)
+	if err != nil {
+		return 0
+	}
+	return k
+}
+
+func (f *face) GlyphAdvance(r rune) (fixed.Int26_6, bool) {
+	x, ok := f.index(r)
+	if !ok {
+		return 0, false
+	}
+	advance, err := f.font.GlyphAdvance(&f.buf, x, f.ppem, font.HintingNone)
+	return advance, err == nil
+}
+
+func (f *face) GlyphBounds(r rune) (fixed.Rectangle26_6, fixed.Int26_6, bool) {
+	x, ok := f.index(r)
+	if !ok {
+		return fixed.Rectangle26_6{}, 0, false
+	}
+	segments, err := f.font.LoadGlyph(&f.buf, x, f.ppem, nil)
+	if err != nil {
+		return fixed.Rectangle26_6{}, 0, false
+	}
+	advance, err := f.font.GlyphAdvance(&f.buf, x, f.ppem, font.HintingNone)
+	return segmentBounds(segments), advance, err == nil
+}
+
+// Return the bounds of the points of segments, y down
+func segmentBounds(segments []sfnt.Segment) fixed.Rectangle26_6 {
+	var b fixed.Rectangle26_6
+	for i, s := range segments {
+		n := 1
+		switch s.Op {
+		case sfnt.SegmentOpQuadTo:
+			n = 2
+		case sfnt.SegmentOpCubeTo:
+			n = 3
+		}
+		for j, p := range s.Args[:n] {
+			if i == 0 && j == 0 {
+				b.Min, b.Max = p, p
+				continue
+			}
+			if p.X < b.Min.X {
+				b.Min.X = p.X
+			} else if p.X > b.Max.X {
+				b.Max.X = p.X
+			}
+			if p.Y < b.Min.Y {
+				b.Min.Y = p.Y
+			} else if p.Y > b.Max.Y {
+				b.Max.Y = p.Y
+			}
+		}
+	}
+	return b
+}
+
+func (f *face) Glyph(dot fixed.Point26_6, r rune) (image.Rectangle, image.Image, image.Point, fixed.Int26_6, bool) {
+	x, ok := f.index(r)
+	if !ok {
+		return image.Rectangle{}, nil, image.Point{}, 0, false
+	}
+	segments, err := f.font.LoadGlyph(&f.buf, x, f.ppem, nil)
+	if err != nil {
+		return image.Rectangle{}, nil, image.Point{}, 0, false
+	}
+	advance, err := f.font.GlyphAdvance(&f.buf, x, f.ppem, font.HintingNone)
+	if err != nil {
+		return image.Rectangle{}, nil, image.Point{}, 0, false
+	}
+	b := segmentBounds(segments)
+	dr := image.Rect((dot.X + b.Min.X).Floor(), (dot.Y + b.Min.Y).Floor(), (dot.X + b.Max.X).Ceil(), (dot.Y + b.Max.Y).Ceil())
+	mask := image.NewAlpha(image.Rect(0, 0, dr.Dx(), dr.Dy()))
+	if dr.Empty() {
+		return dr, mask, image.Point{}, advance, true
+	}
+	// points relative to the top left of the mask
+	ox := float32(dot.X)/64 - float32(dr.Min.X)
+	oy := float32(dot.Y)/64 - float32(dr.Min.Y)
+	pt := func(p fixed.Point26_6) (float32, float32) { return float32(p.X)/64 + ox, float32(p.Y)/64 + oy }
+	f.raster.Reset(dr.Dx(), dr.Dy())
+	for i, s := range segments {
+		switch s.Op {
+		case sfnt.SegmentOpMoveTo:
+			if i > 0 {
+				f.raster.ClosePath()
+			}
+			f.raster.MoveTo(pt(s.Args[0]))
+		case sfnt.SegmentOpLineTo:
+			f.raster.LineTo(pt(s.Args[0]))
+		case sfnt.SegmentOpQuadTo:
+			bx, by := pt(s.Args[0])
+			cx, cy := pt(s.Args[1])
+			f.raster.QuadTo(bx, by, cx, cy)
+		case sfnt.SegmentOpCubeTo:
+			bx, by := pt(s.Args[0])
+			cx, cy := pt(s.Args[1])
+			dx, dy := pt(s.Args[2])
+			f.raster.CubeTo(bx, by, cx, cy, dx, dy)
+		}
+	}
+	f.raster.ClosePath()
+	f.raster.Draw(mask, mask.Bounds(), image.Opaque, image.Point{})
+	return dr, mask, image.Point{}, advance, true
+}
@@ -0,0 +1,345 @@
+package text
+
+import (
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/png"
+	"math"
+	"os"
+	"sort"
+
+	"github.com/FEEDFACE-COM/piglet/sprite"
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+)
+
+// Oversampling of the outlines distance fields are computed from
+const sdfOversample = 4
+
+// Options of generating distance field glyphs
+type SDFOptions struct {
+	Size   float64 // pixels per em glyphs are generated at, 48 if zero
+	Spread float64 // pixels at Size the field reaches out from edges, which limits outlines, glows and shadows, Size/8 if zero
+}
+
+func (o SDFOptions) defaults() SDFOptions {
+	if o.Size == 0 {
+		o.Size = 48
+	}
+	if o.Spread == 0 {
+		o.Spread = o.Size / 8
+	}
+	return o
+}
+
+// Return the distance field of glyph r of hires, a face at sdfOversample
+// times opts.Size, and the offset of its top left from the dot at opts.Size.
+// Fields are 0.5 at the edge of the glyph and rise inwards.
+func sdfField(hires font.Face, r rune, opts SDFOptions) (*image.Gray, image.Point, bool) {
+	const k = sdfOversample
+	dr, mask, maskp, _, ok := hires.Glyph(fixed.Point26_6{}, r)
+	if !ok {
+		return nil, image.Point{}, false
+	}
+	if dr.Empty() {
+		return image.NewGray(image.Rectangle{}), image.Point{}, true
+	}
+	// the field, in pixels at Size, aligned so its pixels cover whole k*k blocks
+	pad := int(math.Ceil(opts.Spread))
+	bounds := image.Rect(floorDiv(dr.Min.X, k)-pad, floorDiv(dr.Min.Y, k)-pad, -floorDiv(-dr.Max.X, k)+pad, -floorDiv(-dr.Max.Y, k)+pad)
+	w, h := bounds.Dx()*k, bounds.Dy()*k
+	hi := image.NewAlpha(image.Rect(0, 0, w, h))
+	draw.Draw(hi, dr.Sub(bounds.Min.Mul(k)), mask, maskp, draw.Src)
+
+	inside := make([]bool, w*h)
+	outside := make([]bool, w*h)
+	for i, a := range hi.Pix {
+		inside[i] = a >= 0x80
+		outside[i] = !inside[i]
+	}
+	toInside, toOutside := distances(inside, w, h), distances(outside, w, h)
+
+	field := image.NewGray(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	for y := 0; y < bounds.Dy(); y++ {
+		for x := 0; x < bounds.Dx(); x++ {
+			sum := 0.0
+			for yy := y * k; yy < (y+1)*k; yy++ {
+				for xx := x * k; xx < (x+1)*k; xx++ {
+					i := yy*w + xx
+					if inside[i] {
+						sum -= math.Sqrt(toOutside[i]) - 0.5
+					} else {
+						sum += math.Sqrt(toInside[i]) - 0.5
+					}
+				}
+			}
+			d := sum / (k * k) / k // in pixels at Size, positive outside
+			v := 0.5 - d/(2*opts.Spread)
+			field.Pix[y*field.Stride+x] = uint8(math.Round(255 * math.Max(0, math.Min(1, v))))
+		}
+	}
+	return field, bounds.Min, true
+}
+
+func floorDiv(a, b int) int {
+	if a < 0 {
+		return -((-a + b - 1) / b)
+	}
+	return a / b
+}
+
+// Return the squared distance of each pixel of a w*h grid to the nearest
+// pixel that is set, with the transform of Felzenszwalb and Huttenlocher
+func distances(set []bool, w, h int) []float64 {
+	const inf = 1e20
+	d := make([]float64, w*h)
+	for i, s := range set {
+		if !s {
+			d[i] = inf
+		}
+	}
+	n := w
+	if h > n {
+		n = h
+	}
+	f, out, z := make([]float64, n), make([]float64, n), make([]float64, n+1)
+	v := make([]int, n)
+	for x := 0; x < w; x++ {
+		for y := 0; y < h; y++ {
+			f[y] = d[y*w+x]
+		}
+		distances1D(f[:h], out, v, z)
+		for y := 0; y < h; y++ {
+			d[y*w+x] = out[y]
+		}
+	}
+	for y := 0; y < h; y++ {
+		copy(f, d[y*w:(y+1)*w])
+		distances1D(f[:w], out, v, z)
+		copy(d[y*w:(y+1)*w], out[:w])
+	}
+	return d
+}
+
+// Squared distances along one row, from the lower envelope of parabolas
+func distances1D(f, d []float64, v []int, z []float64) {
+	k := 0
+	v[0] = 0
+	z[0], z[1] = math.Inf(-1), math.Inf(1)
+	for q := 1; q < len(f); q++ {
+		s := ((f[q] + float64(q*q)) - (f[v[k]] + float64(v[k]*v[k]))) / float64(2*q-2*v[k])
+		for s <= z[k] {
+			k--
+			s = ((f[q] + float64(q*q)) - (f[v[k]] + float64(v[k]*v[k]))) / float64(2*q-2*v[k])
+		}
+		k++
+		v[k] = q
+		z[k], z[k+1] = s, math.Inf(1)
+	}
+	k = 0
+	for q := range f {
+		for z[k+1] < float64(q) {
+			k++
+		}
+		d[q] = float64((q-v[k])*(q-v[k])) + f[v[k]]
+	}
+}
+
+// An SDF is a set of distance field glyphs packed into one image, as made by
+// GenerateSDF, eg. offline with cmd/sdffont
+type SDF struct {
+	Image   *image.Gray `json:"-"` // 0.5 at the edges of glyphs, rising inwards
+	Size    float64
+	Spread  float64
+	Height  float32 // distance between baselines, in pixels at Size
+	Ascent  float32
+	Descent float32
+	Glyphs  map[rune]SDFGlyph
+	Kerns   []SDFKern // pairs with kerning
+}
+
+// A glyph of an SDF
+type SDFGlyph struct {
+	Rect    image.Rectangle // in the image
+	Offset  image.Point     // of the top left of Rect from the dot, in pixels at Size
+	Advance float32
+}
+
+// Kerning between two runes of an SDF
+type SDFKern struct {
+	Left, Right rune
+	Kern        float32
+}
+
+// Generate distance field glyphs of the runes of a TrueType or OpenType font
+func GenerateSDF(data []byte, runes string, opts SDFOptions) (*SDF, error) {
+	opts = opts.defaults()
+	face, err := NewFace(data, opts.Size)
+	if err != nil {
+		return nil, err
+	}
+	hires, err := NewFace(data, opts.Size*sdfOversample)
+	if err != nil {
+		return nil, err
+	}
+	metrics := face.Metrics()
+	s := &SDF{
+		Size:    opts.Size,
+		Spread:  opts.Spread,
+		Height:  float32(metrics.Height) / 64,
+		Ascent:  float32(metrics.Ascent) / 64,
+		Descent: float32(metrics.Descent) / 64,
+		Glyphs:  map[rune]SDFGlyph{},
+	}
+	fields := map[rune]*image.Gray{}
+	var set []rune
+	for _, r := range runes {
+		if _, ok := fields[r]; ok {
+			continue
+		}
+		advance, ok := face.GlyphAdvance(r)
+		if !ok {
+			continue
+		}
+		field, offset, ok := sdfField(hires, r, opts)
+		if !ok {
+			continue
+		}
+		fields[r] = field
+		s.Glyphs[r] = SDFGlyph{Offset: offset, Advance: float32(advance) / 64}
+		set = append(set, r)
+	}
+	for _, r0 := range set {
+		for _, r1 := range set {
+			if k := face.Kern(r0, r1); k != 0 {
+				s.Kerns = append(s.Kerns, SDFKern{Left: r0, Right: r1, Kern: float32(k) / 64})
+			}
+		}
+	}
+
+	// tallest first, into the smallest power of two square that fits
+	sort.Slice(set, func(i, j int) bool {
+		hi, hj := fields[set[i]].Bounds().Dy(), fields[set[j]].Bounds().Dy()
+		return hi > hj || hi == hj && set[i] < set[j]
+	})
+	for size := 64; ; size *= 2 {
+		if size > sprite.MaxAtlasSize {
+			return nil, fmt.Errorf("fail to generate distance field glyphs: %d do not fit %dx%d", len(set), sprite.MaxAtlasSize, sprite.MaxAtlasSize)
+		}
+		packer := sprite.NewPacker(size, size, 1)
+		rects := map[rune]image.Rectangle{}
+		for _, r := range set {
+			rect, ok := packer.Place(fields[r].Bounds().Dx(), fields[r].Bounds().Dy())
+			if !ok {
+				break
+			}
+			rects[r] = rect
+		}
+		if len(rects) < len(set) {
+			continue
+		}
+		s.Image = image.NewGray(image.Rect(0, 0, size, size))
+		for _, r := range set {
+			draw.Draw(s.Image, rects[r], fields[r], image.Point{}, draw.Src)
+			g := s.Glyphs[r]
+			g.Rect = rects[r]
+			s.Glyphs[r] = g
+		}
+		return s, nil
+	}
+}
+
+// Write the image of the glyphs to path as PNG, and the rest to path.json
+func (s *SDF) Write(path string) error {
+	desc, err := json.MarshalIndent(s, "", "\t")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path+".json", desc, 0644); err != nil {
+		return fmt.Errorf("fail to write distance field glyphs: %v", err)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("fail to write distance field glyphs: %v", err)
+	}
+	defer f.Close()
+	if err := png.Encode(f, s.Image); err != nil {
+		return fmt.Errorf("fail to write distance field glyphs: %v", err)
+	}
+	return f.Close()
+}
+
+// Read glyphs written by SDF.Write
+func OpenSDF(path string) (*SDF, error) {
+	desc, err := os.ReadFile(path + ".json")
+	if err != nil {
+		return nil, fmt.Errorf("fail to read distance field glyphs: %v", err)
+	}
+	s := &SDF{}
+	if err := json.Unmarshal(desc, s); err != nil {
+		return nil, fmt.Errorf("fail to read distance field glyphs %s.json: %v", path, err)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("fail to read distance field glyphs: %v", err)
+	}
+	defer f.Close()
+	img, err := png.Decode(f)
+	if err != nil {
+		return nil, fmt.Errorf("fail to read distance field glyphs %s: %v", path, err)
+	}
+	if gray, ok := img.(*image.Gray); ok {
+		s.Image = gray
+	} else {
+		s.Image = image.NewGray(img.Bounds())
+		draw.Draw(s.Image, img.Bounds(), img, img.Bounds().Min, draw.Src)
+	}
+	return s, nil
+}
+
+// A face of the metrics of an SDF, for laying out its glyphs
+type sdfFace struct {
+	sdf   *SDF
+	kerns map[[2]rune]fixed.Int26_6
+}
+
+func newSDFFace(s *SDF) *sdfFace {
+	f := &sdfFace{sdf: s, kerns: map[[2]rune]fixed.Int26_6{}}
+	for _, k := range s.Kerns {
+		f.kerns[[2]rune{k.Left, k.Right}] = fixed.Int26_6(k.Kern * 64)
+	}
+	return f
+}
+
+func (f *sdfFace) Close() error { return nil }
+
+func (f *sdfFace) Metrics() font.Metrics {
+	return font.Metrics{
+		Height:  fixed.Int26_6(f.sdf.Height * 64),
+		Ascent:  fixed.Int26_6(f.sdf.Ascent * 64),
+		Descent: fixed.Int26_6(f.sdf.Descent * 64),
+	}
+}
+
+func (f *sdfFace) Kern(r0, r1 rune) fixed.Int26_6 { return f.kerns[[2]rune{r0, r1}] }
+
+func (f *sdfFace) GlyphAdvance(r rune) (fixed.Int26_6, bool) {
+	g, ok := f.sdf.Glyphs[r]
+	return fixed.Int26_6(g.Advance * 64), ok
+}
+
+func (f *sdfFace) GlyphBounds(r rune) (fixed.Rectangle26_6, fixed.Int26_6, bool) {
+	g, ok := f.sdf.Glyphs[r]
+	if !ok {
+		return fixed.Rectangle26_6{}, 0, false
+	}
+	rect := image.Rectangle{Min: g.Offset, Max: g.Offset.Add(g.Rect.Size())}
+	return fixed.R(rect.Min.X, rect.Min.Y, rect.Max.X, rect.Max.Y), fixed.Int26_6(g.Advance * 64), true
+}
+
+// Glyphs of an SDF are only drawn as distance fields
+func (f *sdfFace) Glyph(dot fixed.Point26_6, r rune) (image.Rectangle, image.Image, image.Point, fixed.Int26_6, bool) {
+	return image.Rectangle{}, nil, image.Point{}, 0, false
+}
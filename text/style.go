@@ -0,0 +1,105 @@
+package text
+
+import (
+	"image/color"
+
+	"github.com/FEEDFACE-COM/piglet/sprite"
+	"github.com/go-gl/mathgl/mgl32"
+)
+
+// A Style decorates text of distance field fonts. Widths and offsets are in
+// pixels at the size drawn, and reach at most the spread of the font.
+type Style struct {
+	Outline          float32 // width of the outline
+	OutlineColor     color.NRGBA
+	Glow             float32 // width of the glow, beyond the outline
+	GlowColor        color.NRGBA
+	ShadowX, ShadowY float32 // offset of the shadow
+	ShadowBlur       float32 // width of the soft edge of the shadow
+	ShadowColor      color.NRGBA
+}
+
+// Composites the shadow, glow, outline and fill from the distance field,
+// with an edge of about a pixel at any scale. Works with premultiplied
+// colors throughout, as layers blend correctly that way.
+const sdfFragment = `
+uniform float smoothing;
+uniform float outline;
+uniform vec4 outlineColor;
+uniform float glow;
+uniform vec4 glowColor;
+uniform vec2 shadowOffset;
+uniform float shadowBlur;
+uniform vec4 shadowColor;
+uniform float premultiplied;
+
+vec4 over(vec4 top, vec4 bottom) {
+	return top + bottom * (1.0 - top.a);
+}
+
+void main() {
+	float d = texture2D(image, v_texcoord).a;
+	vec4 fill = v_color;
+	if (premultiplied == 0.0) {
+		fill.rgb *= fill.a;
+	}
+	float edge = 0.5 - outline;
+	vec4 color = mix(outlineColor, fill, smoothstep(0.5 - smoothing, 0.5 + smoothing, d));
+	color *= smoothstep(edge - smoothing, edge + smoothing, d);
+	color = over(color, glowColor * smoothstep(edge - glow - smoothing, edge + smoothing, d));
+	float s = texture2D(image, v_texcoord - shadowOffset).a;
+	color = over(color, shadowColor * smoothstep(edge - shadowBlur - smoothing, edge + shadowBlur + smoothing, s));
+	if (premultiplied == 0.0 && color.a > 0.0) {
+		color.rgb /= color.a;
+	}
+	gl_FragColor = color;
+}
+`
+
+// Styled shaders kept per font. Text drawn at a scale changing every frame
+// makes a new one each frame, so the cache starts over when full; the
+// copies share the program, and hold no GL objects of their own.
+const maxStyles = 64
+
+// A style at a scale, which the uniforms depend on
+type styleKey struct {
+	style Style
+	scale float32
+}
+
+// Return the shader drawing the distance field glyphs of the font in style,
+// scaled by scale
+func (f *Font) styled(style Style, scale float32) *sprite.Shader {
+	key := styleKey{style, scale}
+	if s, ok := f.styles[key]; ok {
+		return s
+	}
+	if len(f.styles) >= maxStyles {
+		f.styles = map[styleKey]*sprite.Shader{}
+	}
+	s := f.shader.Copy()
+	// distances in the field run from 1 inside to 0 outside over twice the
+	// spread, in pixels at the generated size
+	unit := 1 / (2 * float32(f.sdf.Spread) * scale)
+	texel := 1 / (float32(f.pageSize) * scale)
+	premultiplied := float32(0)
+	if f.Premultiplied {
+		premultiplied = 1
+	}
+	s.Set("smoothing", 0.5*unit)
+	s.Set("outline", style.Outline*unit)
+	s.Set("outlineColor", premultiply(style.OutlineColor))
+	s.Set("glow", style.Glow*unit)
+	s.Set("glowColor", premultiply(style.GlowColor))
+	s.Set("shadowOffset", mgl32.Vec2{style.ShadowX * texel, style.ShadowY * texel})
+	s.Set("shadowBlur", style.ShadowBlur*unit)
+	s.Set("shadowColor", premultiply(style.ShadowColor))
+	s.Set("premultiplied", premultiplied)
+	f.styles[key] = s
+	return s
+}
+
+func premultiply(c color.NRGBA) mgl32.Vec4 {
+	a := float32(c.A) / 255
+	return mgl32.Vec4{float32(c.R) / 255 * a, float32(c.G) / 255 * a, float32(c.B) / 255 * a, a}
+}
@@ -0,0 +1,467 @@
+// Package text draws strings in TrueType and OpenType fonts. Glyphs are
+// rasterized when first used and packed into textures, and strings are laid
+// out with kerning, line breaks, alignment and wrapping, then drawn as
+// sprites through a sprite.Batch:
+//
+//	face, err := text.NewFace(goregular.TTF, 32)
+//	font := text.NewFont(face)
+//	...
+//	batch.Begin()
+//	font.Draw(batch, "Platform 3\nDelayed", 20, 20, text.Options{Color: color.NRGBA{255, 200, 0, 255}})
+//	batch.End()
+//
+// Any font.Face works, eg. basicfont.Face7x13 for a bitmap font.
+//
+// Glyphs blur when scaled up. Distance field fonts, from LoadSDFFont or
+// glyphs generated offline with cmd/sdffont, stay sharp at any size, and
+// draw with outlines, glows and shadows, see Style.
+package text
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	gl "github.com/FEEDFACE-COM/piglet/gles2"
+	"github.com/FEEDFACE-COM/piglet/glx"
+	"github.com/FEEDFACE-COM/piglet/sprite"
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+)
+
+// Smallest size of the textures glyphs are packed into. Fonts with larger
+// glyphs get larger ones, up to sprite.MaxAtlasSize.
+const minPageSize = 256
+
+// A glyph rasterized into a page
+type glyph struct {
+	region  sprite.Region
+	offset  image.Point // of the top left of the bitmap from the dot
+	size    image.Point
+	advance float32
+}
+
+// A texture glyphs are packed into
+type page struct {
+	atlas  *sprite.Atlas
+	packer *sprite.Packer
+}
+
+// A Font draws text in a face. Its methods must be called from the thread
+// that holds the context, as they upload glyphs.
+type Font struct {
+	Face          font.Face
+	Premultiplied bool // store glyphs with premultiplied alpha, for a sprite.Batch with Premultiplied set
+
+	metrics  font.Metrics
+	pageSize int
+	pages    []*page
+	glyphs   map[rune]*glyph
+	kerns    map[[2]rune]float32
+
+	sdf    *SDFOptions // of distance field fonts
+	hires  font.Face   // rasterizes outlines for distance fields, if generated at runtime
+	shader *sprite.Shader
+	styles map[styleKey]*sprite.Shader
+}
+
+// Return a font drawing in face
+func NewFont(face font.Face) *Font {
+	f := &Font{Face: face, metrics: face.Metrics(), glyphs: map[rune]*glyph{}, kerns: map[[2]rune]float32{}}
+	f.pageSize = minPageSize
+	for f.pageSize < 8*f.metrics.Height.Ceil() && f.pageSize < sprite.MaxAtlasSize {
+		f.pageSize *= 2
+	}
+	return f
+}
+
+// Return a font of TrueType or OpenType data at size pixels per em
+func LoadFont(data []byte, size float64) (*Font, error) {
+	face, err := NewFace(data, size)
+	if err != nil {
+		return nil, err
+	}
+	return NewFont(face), nil
+}
+
+// Return a distance field font of TrueType or OpenType data, generating
+// glyphs as they are first used. That takes a few milliseconds each on a
+// Pi, so Preload them, or generate them offline.
+func LoadSDFFont(data []byte, opts SDFOptions) (*Font, error) {
+	opts = opts.defaults()
+	face, err := NewFace(data, opts.Size)
+	if err != nil {
+		return nil, err
+	}
+	hires, err := NewFace(data, opts.Size*sdfOversample)
+	if err != nil {
+		return nil, err
+	}
+	f := NewFont(face)
+	f.hires = hires
+	return f, f.initSDF(opts)
+}
+
+// Return a distance field font of generated glyphs, eg. from OpenSDF
+func NewSDFFont(s *SDF) (*Font, error) {
+	f := NewFont(newSDFFace(s))
+	f.pageSize = s.Image.Bounds().Dx()
+	if err := f.initSDF(SDFOptions{Size: s.Size, Spread: s.Spread}); err != nil {
+		return nil, err
+	}
+	tex := glx.NewTexture(glx.Texture2D)
+	gl.PixelStorei(gl.UNPACK_ALIGNMENT, 1)
+	tex.SetImage(0, glx.Alpha, s.Image.Bounds().Dx(), s.Image.Bounds().Dy(), glx.UnsignedByte, s.Image.Pix)
+	gl.PixelStorei(gl.UNPACK_ALIGNMENT, 4)
+	tex.SetFilter(glx.Linear, glx.Linear)
+	tex.SetWrap(glx.ClampToEdge, glx.ClampToEdge)
+	p := &page{atlas: sprite.NewAtlas(tex, s.Image.Bounds().Dx(), s.Image.Bounds().Dy(), true)}
+	f.pages = append(f.pages, p)
+	for r, g := range s.Glyphs {
+		f.glyphs[r] = &glyph{region: p.atlas.Sub(g.Rect), offset: g.Offset, size: g.Rect.Size(), advance: g.Advance}
+	}
+	return f, nil
+}
+
+func (f *Font) initSDF(opts SDFOptions) error {
+	shader, err := sprite.NewShader(sdfFragment)
+	if err != nil {
+		return err
+	}
+	f.sdf, f.shader, f.styles = &opts, shader, map[styleKey]*sprite.Shader{}
+	return nil
+}
+
+// Return the distance between baselines, in pixels
+func (f *Font) LineHeight() float32 { return float32(f.metrics.Height) / 64 }
+
+// Return the distance from the top of a line to its baseline, in pixels
+func (f *Font) Ascent() float32 { return float32(f.metrics.Ascent) / 64 }
+
+// Rasterize the glyphs of s, eg. the digits of a clock, so drawing them
+// later does not upload
+func (f *Font) Preload(s string) {
+	for _, r := range s {
+		f.glyph(r)
+	}
+}
+
+// Return the glyph of r, rasterizing it on first use. Runes the face lacks
+// get its replacement character, or nothing.
+func (f *Font) glyph(r rune) *glyph {
+	if g, ok := f.glyphs[r]; ok {
+		return g
+	}
+	g := &glyph{}
+	f.glyphs[r] = g
+	var dr image.Rectangle
+	var mask image.Image
+	var maskp image.Point
+	var advance fixed.Int26_6
+	var ok bool
+	if f.sdf != nil {
+		advance, ok = f.Face.GlyphAdvance(r)
+		if ok && f.hires != nil {
+			var field *image.Gray
+			field, dr.Min, ok = sdfField(f.hires, r, *f.sdf)
+			if ok {
+				mask, dr.Max = field, dr.Min.Add(field.Bounds().Size())
+			}
+		} else {
+			ok = false
+		}
+	} else {
+		dr, mask, maskp, advance, ok = f.Face.Glyph(fixed.Point26_6{}, r)
+	}
+	if !ok {
+		if r != unicode.ReplacementChar && !unicode.IsSpace(r) {
+			*g = *f.glyph(unicode.ReplacementChar)
+		} else if advance, ok := f.Face.GlyphAdvance(r); ok {
+			g.advance = float32(advance) / 64
+		}
+		return g
+	}
+	g.advance = float32(advance) / 64
+	g.offset, g.size = dr.Min, dr.Size()
+	if dr.Empty() {
+		return g
+	}
+	region, ok := f.pack(mask, maskp, dr.Size())
+	if !ok {
+		g.size = image.Point{}
+		return g
+	}
+	g.region = region
+	return g
+}
+
+// Upload the w*h mask at maskp into a page with room, and return its region
+func (f *Font) pack(mask image.Image, maskp image.Point, size image.Point) (sprite.Region, bool) {
+	if size.X > f.pageSize || size.Y > f.pageSize {
+		return sprite.Region{}, false
+	}
+	// distance fields take one channel, bitmaps luminance for the tint and alpha
+	format, channels := glx.LuminanceAlpha, 2
+	if f.sdf != nil {
+		format, channels = glx.Alpha, 1
+	}
+	var p *page
+	var rect image.Rectangle
+	if n := len(f.pages); n > 0 && f.pages[n-1].packer != nil {
+		p = f.pages[n-1]
+		rect, _ = p.packer.Place(size.X, size.Y)
+	}
+	if rect.Empty() {
+		tex := glx.NewTexture(glx.Texture2D)
+		tex.SetImage(0, format, f.pageSize, f.pageSize, glx.UnsignedByte, make([]byte, f.pageSize*f.pageSize*channels))
+		tex.SetFilter(glx.Linear, glx.Linear)
+		tex.SetWrap(glx.ClampToEdge, glx.ClampToEdge)
+		p = &page{atlas: sprite.NewAtlas(tex, f.pageSize, f.pageSize, true), packer: sprite.NewPacker(f.pageSize, f.pageSize, 1)}
+		f.pages = append(f.pages, p)
+		rect, _ = p.packer.Place(size.X, size.Y)
+	}
+	var pixels []byte
+	if field, ok := mask.(*image.Gray); ok {
+		pixels = field.Pix // a distance field
+	} else {
+		alpha := image.NewAlpha(image.Rect(0, 0, size.X, size.Y))
+		draw.Draw(alpha, alpha.Bounds(), mask, maskp, draw.Src)
+		pixels = make([]byte, 0, size.X*size.Y*2)
+		for _, a := range alpha.Pix {
+			if f.Premultiplied {
+				pixels = append(pixels, a, a)
+			} else {
+				pixels = append(pixels, 255, a)
+			}
+		}
+	}
+	gl.PixelStorei(gl.UNPACK_ALIGNMENT, 1)
+	p.atlas.Texture.SetSubImage(0, rect.Min.X, rect.Min.Y, size.X, size.Y, format, glx.UnsignedByte, pixels)
+	gl.PixelStorei(gl.UNPACK_ALIGNMENT, 4)
+	return p.atlas.Sub(rect), true
+}
+
+// Return the kerning between r0 and r1, in pixels
+func (f *Font) kern(r0, r1 rune) float32 {
+	pair := [2]rune{r0, r1}
+	k, ok := f.kerns[pair]
+	if !ok {
+		k = float32(f.Face.Kern(r0, r1)) / 64
+		f.kerns[pair] = k
+	}
+	return k
+}
+
+// Return the textures glyphs are packed into, eg. to show them when debugging
+func (f *Font) Textures() []glx.Texture {
+	ret := make([]glx.Texture, len(f.pages))
+	for i, p := range f.pages {
+		ret[i] = p.atlas.Texture
+	}
+	return ret
+}
+
+// Delete the textures and shader of the font and forget its glyphs
+func (f *Font) Delete() {
+	for _, p := range f.pages {
+		p.atlas.Delete()
+	}
+	f.pages = nil
+	f.glyphs = map[rune]*glyph{}
+	if f.shader != nil {
+		f.shader.Delete()
+		f.styles = map[styleKey]*sprite.Shader{}
+	}
+}
+
+// Horizontal alignment of lines
+type Align int
+
+const (
+	AlignLeft Align = iota
+	AlignCenter
+	AlignRight
+)
+
+// Options of laying out text
+type Options struct {
+	Width       float32     // wrap lines at spaces to fit this, if not zero
+	Align       Align       // lines within Width, or within the longest line if Width is zero
+	LineSpacing float32     // multiple of the line height, 1 if zero
+	Color       color.NRGBA // white if zero
+	Size        float32     // pixels per em of distance field fonts, their generated size if zero
+	Style       Style       // of distance field fonts
+}
+
+// A glyph placed in a Text, at the top left of its bitmap
+type placed struct {
+	glyph *glyph
+	x, y  float32
+	w, h  float32
+}
+
+// A Text is a string laid out in a font, to draw it many times without
+// laying it out again
+type Text struct {
+	Font          *Font
+	Color         color.NRGBA // white if zero
+	Width, Height float32     // of the lines, in pixels
+	Lines         int
+
+	glyphs []placed
+	shader *sprite.Shader // of distance field fonts
+}
+
+// A line being laid out
+type line struct {
+	glyphs []placed
+	width  float32 // up to the last glyph that is not a space
+}
+
+// Lay out s, breaking lines at newlines and, with opts.Width set, at the
+// last space that fits
+func (f *Font) Layout(s string, opts Options) *Text {
+	t := &Text{Font: f, Color: opts.Color}
+	// lay out at the size of the font, then scale
+	scale := float32(1)
+	if f.sdf != nil {
+		if opts.Size > 0 {
+			scale = opts.Size / float32(f.sdf.Size)
+		}
+		t.shader = f.styled(opts.Style, scale)
+	}
+	limit := opts.Width / scale
+	var lines []line
+	for _, para := range strings.Split(s, "\n") {
+		var cur line
+		x := float32(0)
+		prev := rune(-1)
+		wrap := func() {
+			lines = append(lines, cur)
+			cur, x, prev = line{}, 0, -1
+		}
+		for _, word := range words(para) {
+			r, _ := utf8.DecodeRuneInString(word)
+			space := unicode.IsSpace(r)
+			if !space && limit > 0 && cur.width > 0 && x+f.measure(prev, word) > limit {
+				wrap()
+			}
+			for _, r := range word {
+				g := f.glyph(r)
+				advance := g.advance
+				if r == '\t' {
+					advance = 4 * f.glyph(' ').advance
+				}
+				if prev >= 0 {
+					x += f.kern(prev, r)
+				}
+				if !space && limit > 0 && cur.width > 0 && x+advance > limit {
+					wrap() // a word longer than a line
+				}
+				if g.size.X > 0 {
+					cur.glyphs = append(cur.glyphs, placed{glyph: g, x: x + float32(g.offset.X), y: float32(g.offset.Y), w: float32(g.size.X), h: float32(g.size.Y)})
+				}
+				x += advance
+				if !space {
+					cur.width = x
+				}
+				prev = r
+			}
+		}
+		lines = append(lines, cur)
+	}
+
+	spacing := opts.LineSpacing
+	if spacing == 0 {
+		spacing = 1
+	}
+	for _, l := range lines {
+		if l.width > t.Width {
+			t.Width = l.width
+		}
+	}
+	block := t.Width
+	if limit > 0 {
+		block = limit
+	}
+	for i, l := range lines {
+		dx := float32(0)
+		switch opts.Align {
+		case AlignCenter:
+			dx = (block - l.width) / 2
+		case AlignRight:
+			dx = block - l.width
+		}
+		baseline := f.Ascent() + float32(i)*f.LineHeight()*spacing
+		for _, p := range l.glyphs {
+			t.glyphs = append(t.glyphs, placed{glyph: p.glyph, x: (p.x + dx) * scale, y: (p.y + baseline) * scale, w: p.w * scale, h: p.h * scale})
+		}
+	}
+	t.Lines = len(lines)
+	t.Width *= scale
+	t.Height = (float32(len(lines)-1)*f.LineHeight()*spacing + f.LineHeight()) * scale
+	return t
+}
+
+// Split s into runs of spaces and of other runes
+func words(s string) []string {
+	var ret []string
+	start, space := 0, false
+	for i, r := range s {
+		if i > start && unicode.IsSpace(r) != space {
+			ret = append(ret, s[start:i])
+			start = i
+		}
+		if i == start {
+			space = unicode.IsSpace(r)
+		}
+	}
+	if start < len(s) {
+		ret = append(ret, s[start:])
+	}
+	return ret
+}
+
+// Return the width of word following prev, with kerning
+func (f *Font) measure(prev rune, word string) float32 {
+	w := float32(0)
+	for _, r := range word {
+		if prev >= 0 {
+			w += f.kern(prev, r)
+		}
+		w += f.glyph(r).advance
+		prev = r
+	}
+	return w
+}
+
+// Return the size of s laid out with opts
+func (f *Font) Measure(s string, opts Options) (float32, float32) {
+	t := f.Layout(s, opts)
+	return t.Width, t.Height
+}
+
+// Lay out s with opts and draw it with its top left at x,y
+func (f *Font) Draw(b *sprite.Batch, s string, x, y float32, opts Options) {
+	f.Layout(s, opts).Draw(b, x, y)
+}
+
+// Draw the text with its top left at x,y. Glyphs of bitmap fonts are placed
+// on whole pixels, so they stay sharp.
+func (t *Text) Draw(b *sprite.Batch, x, y float32) {
+	if t.shader != nil {
+		defer func(shader *sprite.Shader) { b.Shader = shader }(b.Shader)
+		b.Shader = t.shader
+	}
+	for _, p := range t.glyphs {
+		gx, gy := x+p.x, y+p.y
+		if t.shader == nil {
+			gx, gy = float32(math.Round(float64(gx))), float32(math.Round(float64(gy)))
+		}
+		b.DrawRect(p.glyph.region, gx, gy, p.w, p.h, t.Color)
+	}
+}
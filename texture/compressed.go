@@ -0,0 +1,71 @@
+package texture
+
+import (
+	"errors"
+	"io"
+
+	gl "github.com/FEEDFACE-COM/piglet/gles2"
+	"github.com/FEEDFACE-COM/piglet/glx"
+	"github.com/FEEDFACE-COM/piglet/texture/etc1"
+)
+
+// Extension required for ETC1 textures. VideoCore IV has it.
+const ETC1Extension = "GL_OES_compressed_ETC1_RGB8_texture"
+
+// Create a new 2D texture from a KTX or PKM file holding ETC1 data, with
+// all the mip levels the file has. Of opts, only Filter and Wrap apply, and
+// Mipmap is implied by a file with more than one level. ETC1 cannot be
+// flipped, so encode images in GL orientation, see cmd/etc1ktx.
+func LoadCompressed(r io.Reader, opts Options) (glx.Texture, error) {
+	f, err := etc1.Read(r)
+	if err != nil {
+		return glx.Texture{}, err
+	}
+	return UploadCompressed(f, opts)
+}
+
+// Create a new 2D texture from the mip levels of f
+func UploadCompressed(f *etc1.File, opts Options) (glx.Texture, error) {
+	if !f.IsETC1() {
+		return glx.Texture{}, &etc1.UnsupportedError{Container: "KTX", Format: f.Format}
+	}
+	if !gl.HasExtension(ETC1Extension) {
+		return glx.Texture{}, errors.New("fail to upload ETC1 texture: no " + ETC1Extension)
+	}
+	if len(f.Levels) == 0 {
+		return glx.Texture{}, errors.New("fail to upload ETC1 texture: no image data")
+	}
+	mipmap := len(f.Levels) > 1
+	if mipmap && len(f.Levels) != etc1.MipLevels(f.Width, f.Height) {
+		// an incomplete chain would leave the texture unusable
+		return glx.Texture{}, errors.New("fail to upload ETC1 texture: incomplete mipmap chain")
+	}
+
+	tex := glx.NewTexture(glx.Texture2D)
+	for n, data := range f.Levels {
+		w, h := f.LevelSize(n)
+		tex.SetCompressedImage(n, gl.ETC1_RGB8_OES, w, h, data)
+	}
+	if err := gl.GetError(); err != gl.NO_ERROR {
+		tex.Delete()
+		return glx.Texture{}, errors.New("fail to upload ETC1 texture: " + gl.ErrorString(err))
+	}
+
+	mag, min := opts.Filter, opts.Filter
+	if mag == 0 {
+		mag, min = glx.Linear, glx.Linear
+	}
+	if mipmap {
+		min = glx.LinearMipmapLinear
+		if mag == glx.Nearest {
+			min = glx.NearestMipmapNearest
+		}
+	}
+	tex.SetFilter(min, mag)
+	wrap := opts.Wrap
+	if wrap == 0 {
+		wrap = glx.ClampToEdge
+	}
+	tex.SetWrap(wrap, wrap)
+	return tex, nil
+}
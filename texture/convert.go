@@ -0,0 +1,254 @@
+package texture
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/FEEDFACE-COM/piglet/glx"
+)
+
+// Upload formats of texture data
+type Format int
+
+const (
+	Auto           Format = iota // pick from the image, see Convert
+	RGBA                         // 4 bytes per pixel
+	RGB                          // 3 bytes per pixel, no alpha
+	Luminance                    // 1 byte per pixel, gray
+	LuminanceAlpha               // 2 bytes per pixel, gray and alpha
+	RGB565                       // 2 bytes per pixel once uploaded, no alpha
+)
+
+var formatNames = map[Format]string{
+	Auto: "auto", RGBA: "RGBA", RGB: "RGB", Luminance: "LUMINANCE", LuminanceAlpha: "LUMINANCE_ALPHA", RGB565: "RGB565",
+}
+
+func (f Format) String() string { return formatNames[f] }
+
+// bytes per pixel before packing
+func (f Format) channels() int {
+	switch f {
+	case Luminance:
+		return 1
+	case LuminanceAlpha:
+		return 2
+	case RGB, RGB565:
+		return 3
+	}
+	return 4
+}
+
+// GL format and type of f
+func (f Format) gl() (glx.Format, glx.PixelType) {
+	switch f {
+	case RGB:
+		return glx.RGB, glx.UnsignedByte
+	case Luminance:
+		return glx.Luminance, glx.UnsignedByte
+	case LuminanceAlpha:
+		return glx.LuminanceAlpha, glx.UnsignedByte
+	case RGB565:
+		return glx.RGB, glx.UnsignedShort565
+	}
+	return glx.RGBA, glx.UnsignedByte
+}
+
+// Pixels are texture data, ready for TexImage2D
+type Pixels struct {
+	Format Format
+	Width  int
+	Height int
+	Data   []byte // rows of Width pixels, tightly packed, RGB565 as 3 bytes per pixel
+}
+
+// Return the GL format and type to upload p with
+func (p *Pixels) GL() (glx.Format, glx.PixelType) { return p.Format.gl() }
+
+// Choose the smallest format that keeps the content of img: Luminance for
+// gray images, RGB for opaque ones and RGBA otherwise
+func choose(img image.Image) Format {
+	switch m := img.(type) {
+	case *image.Gray, *image.Gray16:
+		return Luminance
+	case *image.YCbCr:
+		return RGB
+	case *image.Paletted:
+		for _, c := range m.Palette {
+			if _, _, _, a := c.RGBA(); a != 0xffff {
+				return RGBA
+			}
+		}
+		return RGB
+	case *image.RGBA:
+		if m.Opaque() {
+			return RGB
+		}
+		return RGBA
+	case *image.NRGBA:
+		if m.Opaque() {
+			return RGB
+		}
+		return RGBA
+	}
+	if o, ok := img.(interface{ Opaque() bool }); ok && o.Opaque() {
+		return RGB
+	}
+	return RGBA
+}
+
+// Convert img to pixels of the given format, or of the one chosen by choose
+// for Auto. Colors are premultiplied by alpha if premultiply is set, and
+// straight otherwise.
+func Convert(img image.Image, format Format, premultiply bool) *Pixels {
+	if format == Auto {
+		format = choose(img)
+	}
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	n := format.channels()
+	ret := &Pixels{Format: format, Width: w, Height: h, Data: make([]byte, w*h*n)}
+
+	// copy rows of images already in the right layout
+	var pix []byte
+	var stride int
+	switch m := img.(type) {
+	case *image.NRGBA:
+		if n == 4 && (!premultiply || m.Opaque()) {
+			pix, stride = m.Pix[m.PixOffset(b.Min.X, b.Min.Y):], m.Stride
+		}
+	case *image.RGBA:
+		if n == 4 && (premultiply || m.Opaque()) {
+			pix, stride = m.Pix[m.PixOffset(b.Min.X, b.Min.Y):], m.Stride
+		}
+	case *image.Gray:
+		if n == 1 {
+			pix, stride = m.Pix[m.PixOffset(b.Min.X, b.Min.Y):], m.Stride
+		}
+	}
+	if pix != nil {
+		for y := 0; y < h; y++ {
+			copy(ret.Data[y*w*n:(y+1)*w*n], pix[y*stride:])
+		}
+		return ret
+	}
+
+	model := color.NRGBAModel
+	if premultiply {
+		model = color.RGBAModel
+	}
+	i := 0
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			var r, g, bb, a uint8
+			switch c := model.Convert(img.At(x, y)).(type) {
+			case color.NRGBA:
+				r, g, bb, a = c.R, c.G, c.B, c.A
+			case color.RGBA:
+				r, g, bb, a = c.R, c.G, c.B, c.A
+			}
+			switch n {
+			case 1:
+				ret.Data[i] = luma(r, g, bb)
+			case 2:
+				ret.Data[i], ret.Data[i+1] = luma(r, g, bb), a
+			case 3:
+				ret.Data[i], ret.Data[i+1], ret.Data[i+2] = r, g, bb
+			case 4:
+				ret.Data[i], ret.Data[i+1], ret.Data[i+2], ret.Data[i+3] = r, g, bb, a
+			}
+			i += n
+		}
+	}
+	return ret
+}
+
+// same weights as color.GrayModel
+func luma(r, g, b uint8) uint8 {
+	return uint8((19595*uint32(r) + 38470*uint32(g) + 7471*uint32(b) + 1<<15) >> 16)
+}
+
+// Flip the rows of p, as GL expects the first row at the bottom
+func (p *Pixels) FlipY() {
+	if p.Height == 0 {
+		return
+	}
+	stride := len(p.Data) / p.Height
+	tmp := make([]byte, stride)
+	for top, bottom := 0, p.Height-1; top < bottom; top, bottom = top+1, bottom-1 {
+		a, b := p.Data[top*stride:(top+1)*stride], p.Data[bottom*stride:(bottom+1)*stride]
+		copy(tmp, a)
+		copy(a, b)
+		copy(b, tmp)
+	}
+}
+
+// Return the data to upload p with, with RGB565 pixels packed from 3
+// bytes into 2. p itself stays unpacked, so it can still be resized.
+func (p *Pixels) packed() []byte {
+	if p.Format != RGB565 {
+		return p.Data
+	}
+	out := make([]byte, p.Width*p.Height*2)
+	for i, j := 0, 0; i < len(p.Data); i, j = i+3, j+2 {
+		v := uint16(p.Data[i]>>3)<<11 | uint16(p.Data[i+1]>>2)<<5 | uint16(p.Data[i+2]>>3)
+		out[j], out[j+1] = byte(v), byte(v>>8) // little endian, as on the Pi
+	}
+	return out
+}
+
+func isPOT(n int) bool { return n > 0 && n&(n-1) == 0 }
+
+func nextPOT(n int) int {
+	ret := 1
+	for ret < n {
+		ret <<= 1
+	}
+	return ret
+}
+
+// Return p scaled to w*h with bilinear filtering
+func (p *Pixels) Resize(w, h int) *Pixels {
+	n := p.Format.channels()
+	ret := &Pixels{Format: p.Format, Width: w, Height: h, Data: make([]byte, w*h*n)}
+	sx := float32(p.Width) / float32(w)
+	sy := float32(p.Height) / float32(h)
+	for y := 0; y < h; y++ {
+		fy := (float32(y)+.5)*sy - .5
+		y0, ty := split(fy, p.Height)
+		y1 := clamp(y0+1, p.Height)
+		for x := 0; x < w; x++ {
+			fx := (float32(x)+.5)*sx - .5
+			x0, tx := split(fx, p.Width)
+			x1 := clamp(x0+1, p.Width)
+			for c := 0; c < n; c++ {
+				a := float32(p.Data[(y0*p.Width+x0)*n+c])
+				b := float32(p.Data[(y0*p.Width+x1)*n+c])
+				d := float32(p.Data[(y1*p.Width+x0)*n+c])
+				e := float32(p.Data[(y1*p.Width+x1)*n+c])
+				top := a + (b-a)*tx
+				bottom := d + (e-d)*tx
+				ret.Data[(y*w+x)*n+c] = uint8(top + (bottom-top)*ty + .5)
+			}
+		}
+	}
+	return ret
+}
+
+// Split a sample position into a clamped index and a fraction
+func split(f float32, n int) (int, float32) {
+	if f <= 0 {
+		return 0, 0
+	}
+	i := int(f)
+	if i >= n-1 {
+		return n - 1, 0
+	}
+	return i, f - float32(i)
+}
+
+func clamp(i, n int) int {
+	if i >= n {
+		return n - 1
+	}
+	return i
+}
@@ -0,0 +1,320 @@
+// Package etc1 encodes and decodes ETC1 compressed images, and reads and
+// writes them in KTX and PKM files. It does not use GL, so offline tools
+// can use it on any platform.
+package etc1
+
+import (
+	"image"
+	"image/color"
+)
+
+// GL internal format of ETC1 data, as of GL_OES_compressed_ETC1_RGB8_texture
+const GL_ETC1_RGB8_OES = 0x8D64
+
+// Bytes per block of 4x4 pixels
+const BlockSize = 8
+
+// intensity modifier tables, as {small, large}
+var modifiers = [8][2]int{
+	{2, 8}, {5, 17}, {9, 29}, {13, 42}, {18, 60}, {24, 80}, {33, 106}, {47, 183},
+}
+
+// Return the size in bytes of an ETC1 image of w*h pixels
+func EncodedSize(w, h int) int {
+	return ((w + 3) / 4) * ((h + 3) / 4) * BlockSize
+}
+
+// Return the modifier of a pixel index, ie. of its msb and lsb
+func modifier(table, index int) int {
+	m := modifiers[table][index&1]
+	if index&2 != 0 {
+		return -m
+	}
+	return m
+}
+
+func clamp8(v int) int {
+	if v < 0 {
+		return 0
+	} else if v > 255 {
+		return 255
+	}
+	return v
+}
+
+// Decode ETC1 data of a w*h image
+func Decode(data []byte, w, h int) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	bw := (w + 3) / 4
+	for by := 0; by < (h+3)/4; by++ {
+		for bx := 0; bx < bw; bx++ {
+			offset := (by*bw + bx) * BlockSize
+			if offset+BlockSize > len(data) {
+				return img
+			}
+			var block [16]color.NRGBA
+			decodeBlock(data[offset:offset+BlockSize], &block)
+			for i, c := range block {
+				x, y := bx*4+i%4, by*4+i/4
+				if x < w && y < h {
+					img.SetNRGBA(x, y, c)
+				}
+			}
+		}
+	}
+	return img
+}
+
+// Decode one block into pixels, in rows of 4
+func decodeBlock(b []byte, out *[16]color.NRGBA) {
+	hi := uint32(b[0])<<24 | uint32(b[1])<<16 | uint32(b[2])<<8 | uint32(b[3])
+	lo := uint32(b[4])<<24 | uint32(b[5])<<16 | uint32(b[6])<<8 | uint32(b[7])
+	diff, flip := hi&2 != 0, hi&1 != 0
+	var base [2][3]int
+	if diff {
+		for c := 0; c < 3; c++ {
+			v := int(hi >> uint(27-8*c) & 0x1f)
+			d := int(int32(hi>>uint(24-8*c)&7<<29) >> 29) // sign extend 3 bits
+			v2 := v + d
+			base[0][c] = v<<3 | v>>2
+			base[1][c] = v2<<3 | v2>>2
+		}
+	} else {
+		for c := 0; c < 3; c++ {
+			v1 := int(hi >> uint(28-8*c) & 0xf)
+			v2 := int(hi >> uint(24-8*c) & 0xf)
+			base[0][c] = v1<<4 | v1
+			base[1][c] = v2<<4 | v2
+		}
+	}
+	tables := [2]int{int(hi >> 5 & 7), int(hi >> 2 & 7)}
+	for x := 0; x < 4; x++ {
+		for y := 0; y < 4; y++ {
+			i := uint(x*4 + y)
+			index := int(lo>>(i+16)&1)<<1 | int(lo>>i&1)
+			sub := subBlock(x, y, flip)
+			m := modifier(tables[sub], index)
+			out[y*4+x] = color.NRGBA{
+				R: uint8(clamp8(base[sub][0] + m)),
+				G: uint8(clamp8(base[sub][1] + m)),
+				B: uint8(clamp8(base[sub][2] + m)),
+				A: 0xff,
+			}
+		}
+	}
+}
+
+// Return which half of a block pixel x,y belongs to
+func subBlock(x, y int, flip bool) int {
+	if flip {
+		return y / 2
+	}
+	return x / 2
+}
+
+// Encode img as ETC1. Alpha is dropped, and partial blocks at the right and
+// bottom edges are padded by repeating the edge pixels.
+func Encode(img image.Image) []byte {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	ret := make([]byte, 0, EncodedSize(w, h))
+	var block [16][3]int
+	for by := 0; by < h; by += 4 {
+		for bx := 0; bx < w; bx += 4 {
+			for i := range block {
+				x, y := bx+i%4, by+i/4
+				if x >= w {
+					x = w - 1
+				}
+				if y >= h {
+					y = h - 1
+				}
+				c := color.NRGBAModel.Convert(img.At(b.Min.X+x, b.Min.Y+y)).(color.NRGBA)
+				block[i] = [3]int{int(c.R), int(c.G), int(c.B)}
+			}
+			ret = append(ret, encodeBlock(&block)...)
+		}
+	}
+	return ret
+}
+
+// An encoding of one half of a block
+type half struct {
+	base    [3]int // expanded to 8 bits
+	table   int
+	indices [8]int
+	err     int
+}
+
+// Pick the best table and pixel indices for pixels around base
+func fit(base [3]int, pixels [][3]int) half {
+	best := half{base: base, err: -1}
+	for t := range modifiers {
+		h := half{base: base, table: t}
+		for p, px := range pixels {
+			bestErr := -1
+			for index := 0; index < 4; index++ {
+				m := modifier(t, index)
+				e := 0
+				for c := 0; c < 3; c++ {
+					d := clamp8(base[c]+m) - px[c]
+					e += d * d
+				}
+				if bestErr < 0 || e < bestErr {
+					bestErr, h.indices[p] = e, index
+				}
+			}
+			h.err += bestErr
+		}
+		if best.err < 0 || h.err < best.err {
+			best = h
+		}
+	}
+	return best
+}
+
+func average(pixels [][3]int) [3]int {
+	var sum [3]int
+	for _, px := range pixels {
+		for c := 0; c < 3; c++ {
+			sum[c] += px[c]
+		}
+	}
+	for c := 0; c < 3; c++ {
+		sum[c] /= len(pixels)
+	}
+	return sum
+}
+
+// Encode one block, given as pixels in rows of 4, trying both sub-block
+// orientations in both individual and differential mode
+func encodeBlock(block *[16][3]int) []byte {
+	var bestBits [2]uint32
+	bestErr := -1
+	for _, flip := range []bool{false, true} {
+		var halves [2][][3]int
+		var where [2][][2]int
+		for y := 0; y < 4; y++ {
+			for x := 0; x < 4; x++ {
+				s := subBlock(x, y, flip)
+				halves[s] = append(halves[s], block[y*4+x])
+				where[s] = append(where[s], [2]int{x, y})
+			}
+		}
+		avg := [2][3]int{average(halves[0]), average(halves[1])}
+
+		// individual mode, 4 bits per channel per half
+		var q4 [2][3]int
+		var base4 [2][3]int
+		for s := 0; s < 2; s++ {
+			for c := 0; c < 3; c++ {
+				q4[s][c] = (avg[s][c]*15 + 127) / 255
+				base4[s][c] = q4[s][c]<<4 | q4[s][c]
+			}
+		}
+		try := func(diff bool, q [2][3]int, base [2][3]int) {
+			h0, h1 := fit(base[0], halves[0]), fit(base[1], halves[1])
+			if bestErr >= 0 && h0.err+h1.err >= bestErr {
+				return
+			}
+			bestErr = h0.err + h1.err
+			var hi, lo uint32
+			for c := 0; c < 3; c++ {
+				if diff {
+					hi |= uint32(q[0][c]) << uint(27-8*c)
+					hi |= uint32(q[1][c]-q[0][c]) & 7 << uint(24-8*c)
+				} else {
+					hi |= uint32(q[0][c]) << uint(28-8*c)
+					hi |= uint32(q[1][c]) << uint(24-8*c)
+				}
+			}
+			hi |= uint32(h0.table)<<5 | uint32(h1.table)<<2
+			if diff {
+				hi |= 2
+			}
+			if flip {
+				hi |= 1
+			}
+			for s, h := range []half{h0, h1} {
+				for p, xy := range where[s] {
+					i := uint(xy[0]*4 + xy[1])
+					lo |= uint32(h.indices[p]>>1) << (i + 16)
+					lo |= uint32(h.indices[p]&1) << i
+				}
+			}
+			bestBits = [2]uint32{hi, lo}
+		}
+		try(false, q4, base4)
+
+		// differential mode, 5 bits per channel and a 3 bit delta
+		var q5 [2][3]int
+		var base5 [2][3]int
+		ok := true
+		for s := 0; s < 2; s++ {
+			for c := 0; c < 3; c++ {
+				q5[s][c] = (avg[s][c]*31 + 127) / 255
+			}
+		}
+		for c := 0; c < 3; c++ {
+			if d := q5[1][c] - q5[0][c]; d < -4 || d > 3 {
+				ok = false
+			}
+			for s := 0; s < 2; s++ {
+				base5[s][c] = q5[s][c]<<3 | q5[s][c]>>2
+			}
+		}
+		if ok {
+			try(true, q5, base5)
+		}
+	}
+	hi, lo := bestBits[0], bestBits[1]
+	return []byte{
+		byte(hi >> 24), byte(hi >> 16), byte(hi >> 8), byte(hi),
+		byte(lo >> 24), byte(lo >> 16), byte(lo >> 8), byte(lo),
+	}
+}
+
+// Encode img as ETC1, with all mip levels down to 1x1 if mipmap is set
+func EncodeFile(img image.Image, mipmap bool) *File {
+	b := img.Bounds()
+	ret := &File{Format: GL_ETC1_RGB8_OES, Width: b.Dx(), Height: b.Dy(), KeyValues: map[string]string{}}
+	ret.Levels = append(ret.Levels, Encode(img))
+	for mipmap && (img.Bounds().Dx() > 1 || img.Bounds().Dy() > 1) {
+		img = Downsample(img)
+		ret.Levels = append(ret.Levels, Encode(img))
+	}
+	return ret
+}
+
+// Return img scaled to the next mip level, half its size rounded down but
+// at least 1x1, by averaging 2x2 pixels
+func Downsample(img image.Image) *image.NRGBA {
+	b := img.Bounds()
+	w, h := b.Dx()/2, b.Dy()/2
+	if w < 1 {
+		w = 1
+	}
+	if h < 1 {
+		h = 1
+	}
+	ret := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var sum [4]int
+			n := 0
+			for dy := 0; dy < 2; dy++ {
+				for dx := 0; dx < 2; dx++ {
+					sx, sy := 2*x+dx, 2*y+dy
+					if sx >= b.Dx() || sy >= b.Dy() {
+						continue
+					}
+					c := color.NRGBAModel.Convert(img.At(b.Min.X+sx, b.Min.Y+sy)).(color.NRGBA)
+					sum[0], sum[1], sum[2], sum[3] = sum[0]+int(c.R), sum[1]+int(c.G), sum[2]+int(c.B), sum[3]+int(c.A)
+					n++
+				}
+			}
+			ret.SetNRGBA(x, y, color.NRGBA{uint8(sum[0] / n), uint8(sum[1] / n), uint8(sum[2] / n), uint8(sum[3] / n)})
+		}
+	}
+	return ret
+}
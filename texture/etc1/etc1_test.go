@@ -0,0 +1,49 @@
+package etc1
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+// Blocks of two flat halves must survive encoding within the precision of
+// ETC1, in both differential and individual mode
+func TestRoundTrip(t *testing.T) {
+	tests := []struct {
+		left, right color.NRGBA
+	}{
+		{color.NRGBA{180, 180, 180, 255}, color.NRGBA{180, 180, 180, 255}},
+		{color.NRGBA{200, 200, 200, 255}, color.NRGBA{180, 180, 180, 255}}, // negative delta
+		{color.NRGBA{180, 180, 180, 255}, color.NRGBA{200, 200, 200, 255}}, // positive delta
+		{color.NRGBA{255, 0, 0, 255}, color.NRGBA{0, 0, 255, 255}},         // individual mode
+		{color.NRGBA{30, 120, 220, 255}, color.NRGBA{10, 100, 200, 255}},
+	}
+	for _, test := range tests {
+		img := image.NewNRGBA(image.Rect(0, 0, 4, 4))
+		for y := 0; y < 4; y++ {
+			for x := 0; x < 4; x++ {
+				c := test.left
+				if x >= 2 {
+					c = test.right
+				}
+				img.SetNRGBA(x, y, c)
+			}
+		}
+		out := Decode(Encode(img), 4, 4)
+		for y := 0; y < 4; y++ {
+			for x := 0; x < 4; x++ {
+				want, got := img.NRGBAAt(x, y), out.NRGBAAt(x, y)
+				if diff(want.R, got.R) > 12 || diff(want.G, got.G) > 12 || diff(want.B, got.B) > 12 {
+					t.Errorf("%v|%v: pixel %d,%d decodes as %v", test.left, test.right, x, y, got)
+				}
+			}
+		}
+	}
+}
+
+func diff(a, b uint8) int {
+	if a > b {
+		return int(a - b)
+	}
+	return int(b - a)
+}
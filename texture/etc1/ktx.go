@@ -0,0 +1,256 @@
+package etc1
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// A File is a compressed image with its mip levels, as read from a KTX or
+// PKM file. Format is the GL internal format, which need not be ETC1.
+type File struct {
+	Format    uint32
+	Width     int
+	Height    int
+	Levels    [][]byte          // image data of each mip level, largest first
+	KeyValues map[string]string // KTX metadata, eg. KTXorientation
+}
+
+// Report whether f holds ETC1 data
+func (f *File) IsETC1() bool { return f.Format == GL_ETC1_RGB8_OES }
+
+// Return the size in pixels of mip level n
+func (f *File) LevelSize(n int) (w, h int) {
+	w, h = f.Width>>uint(n), f.Height>>uint(n)
+	if w < 1 {
+		w = 1
+	}
+	if h < 1 {
+		h = 1
+	}
+	return w, h
+}
+
+// Error for files with a format other than ETC1
+type UnsupportedError struct {
+	Container string // "KTX" or "PKM"
+	Format    uint32 // GL internal format, or PKM data type
+}
+
+func (e *UnsupportedError) Error() string {
+	if e.Container == "PKM" {
+		return fmt.Sprintf("unsupported PKM data type %d, only ETC1 is supported", e.Format)
+	}
+	return fmt.Sprintf("unsupported KTX format 0x%04X (%s), only ETC1_RGB8_OES is supported", e.Format, formatName(e.Format))
+}
+
+// names of common compressed formats, for error messages
+var formatNames = map[uint32]string{
+	0x8D64: "ETC1_RGB8_OES",
+	0x9274: "COMPRESSED_RGB8_ETC2",
+	0x9278: "COMPRESSED_RGBA8_ETC2_EAC",
+	0x83F0: "COMPRESSED_RGB_S3TC_DXT1",
+	0x83F3: "COMPRESSED_RGBA_S3TC_DXT5",
+	0x8C00: "COMPRESSED_RGB_PVRTC_4BPPV1_IMG",
+	0x8C02: "COMPRESSED_RGBA_PVRTC_4BPPV1_IMG",
+	0x93B0: "COMPRESSED_RGBA_ASTC_4x4",
+	0x1907: "RGB, uncompressed",
+	0x1908: "RGBA, uncompressed",
+	0x8058: "RGBA8, uncompressed",
+}
+
+func formatName(format uint32) string {
+	if name, ok := formatNames[format]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+// Limits of what ReadKTX and ReadPKM accept, so corrupt headers fail
+// before allocating
+const (
+	maxSize         = 16384   // pixels across
+	maxKeyValueData = 1 << 16 // bytes
+)
+
+var ktxIdentifier = [12]byte{0xAB, 'K', 'T', 'X', ' ', '1', '1', 0xBB, '\r', '\n', 0x1A, '\n'}
+
+const ktxEndianness = 0x04030201
+
+type ktxHeader struct {
+	Identifier            [12]byte
+	Endianness            uint32
+	GLType                uint32
+	GLTypeSize            uint32
+	GLFormat              uint32
+	GLInternalFormat      uint32
+	GLBaseInternalFormat  uint32
+	PixelWidth            uint32
+	PixelHeight           uint32
+	PixelDepth            uint32
+	NumberOfArrayElements uint32
+	NumberOfFaces         uint32
+	NumberOfMipmapLevels  uint32
+	BytesOfKeyValueData   uint32
+}
+
+// Read a KTX file holding a single 2D ETC1 image. Files of other formats
+// fail with an *UnsupportedError.
+func ReadKTX(r io.Reader) (*File, error) {
+	var ident [12]byte
+	if _, err := io.ReadFull(r, ident[:]); err != nil {
+		return nil, fmt.Errorf("fail to read KTX header: %s", err)
+	}
+	if ident != ktxIdentifier {
+		return nil, errors.New("fail to read KTX: not a KTX 1.1 file")
+	}
+	var order binary.ByteOrder = binary.LittleEndian
+	var endianness [4]byte
+	if _, err := io.ReadFull(r, endianness[:]); err != nil {
+		return nil, fmt.Errorf("fail to read KTX header: %s", err)
+	}
+	switch binary.LittleEndian.Uint32(endianness[:]) {
+	case ktxEndianness:
+	case 0x01020304:
+		order = binary.BigEndian
+	default:
+		return nil, errors.New("fail to read KTX: bad endianness")
+	}
+
+	var header ktxHeader
+	fields := []*uint32{
+		&header.GLType, &header.GLTypeSize, &header.GLFormat, &header.GLInternalFormat,
+		&header.GLBaseInternalFormat, &header.PixelWidth, &header.PixelHeight, &header.PixelDepth,
+		&header.NumberOfArrayElements, &header.NumberOfFaces, &header.NumberOfMipmapLevels,
+		&header.BytesOfKeyValueData,
+	}
+	for _, field := range fields {
+		if err := binary.Read(r, order, field); err != nil {
+			return nil, fmt.Errorf("fail to read KTX header: %s", err)
+		}
+	}
+	if header.PixelDepth > 1 || header.NumberOfArrayElements > 0 || header.NumberOfFaces > 1 {
+		return nil, errors.New("fail to read KTX: only single 2D images are supported, not 3D, arrays or cube maps")
+	}
+	if header.PixelWidth == 0 || header.PixelHeight == 0 {
+		return nil, errors.New("fail to read KTX: empty image")
+	}
+	if header.PixelWidth > maxSize || header.PixelHeight > maxSize {
+		return nil, fmt.Errorf("fail to read KTX: %dx%d is larger than %d pixels", header.PixelWidth, header.PixelHeight, maxSize)
+	}
+	if header.GLInternalFormat != GL_ETC1_RGB8_OES {
+		return nil, &UnsupportedError{Container: "KTX", Format: header.GLInternalFormat}
+	}
+	if header.BytesOfKeyValueData > maxKeyValueData {
+		return nil, fmt.Errorf("fail to read KTX: %d bytes of key/value data, at most %d", header.BytesOfKeyValueData, maxKeyValueData)
+	}
+
+	ret := &File{
+		Format:    header.GLInternalFormat,
+		Width:     int(header.PixelWidth),
+		Height:    int(header.PixelHeight),
+		KeyValues: map[string]string{},
+	}
+	kv := make([]byte, header.BytesOfKeyValueData)
+	if _, err := io.ReadFull(r, kv); err != nil {
+		return nil, fmt.Errorf("fail to read KTX key/value data: %s", err)
+	}
+	for len(kv) >= 4 {
+		size := int(order.Uint32(kv))
+		kv = kv[4:]
+		if size > len(kv) {
+			break
+		}
+		pair := kv[:size]
+		if i := bytes.IndexByte(pair, 0); i >= 0 {
+			ret.KeyValues[string(pair[:i])] = string(bytes.TrimRight(pair[i+1:], "\x00"))
+		}
+		kv = kv[(size+3)&^3:]
+	}
+
+	levels := int(header.NumberOfMipmapLevels)
+	if levels == 0 {
+		levels = 1 // the application should generate mipmaps
+	}
+	if max := MipLevels(ret.Width, ret.Height); levels > max {
+		return nil, fmt.Errorf("fail to read KTX: %d mip levels for %dx%d, at most %d", levels, ret.Width, ret.Height, max)
+	}
+	for n := 0; n < levels; n++ {
+		var size uint32
+		if err := binary.Read(r, order, &size); err != nil {
+			return nil, fmt.Errorf("fail to read KTX mip level %d: %s", n, err)
+		}
+		if w, h := ret.LevelSize(n); int(size) != EncodedSize(w, h) {
+			return nil, fmt.Errorf("fail to read KTX mip level %d: %d bytes for %dx%d ETC1", n, size, w, h)
+		}
+		data := make([]byte, size)
+		if _, err := io.ReadFull(r, data); err != nil {
+			return nil, fmt.Errorf("fail to read KTX mip level %d: %s", n, err)
+		}
+		if pad := int(3 - (size+3)%4); pad > 0 {
+			if _, err := io.CopyN(io.Discard, r, int64(pad)); err != nil && n < levels-1 {
+				return nil, fmt.Errorf("fail to read KTX mip level %d: %s", n, err)
+			}
+		}
+		ret.Levels = append(ret.Levels, data)
+	}
+	return ret, nil
+}
+
+// Return the number of mip levels of a w*h image, down to 1x1
+func MipLevels(w, h int) int {
+	n := 1
+	for w > 1 || h > 1 {
+		w, h = w/2, h/2
+		n++
+	}
+	return n
+}
+
+// Write f as a little endian KTX file
+func WriteKTX(w io.Writer, f *File) error {
+	out := bufio.NewWriter(w)
+	var kv bytes.Buffer
+	keys := make([]string, 0, len(f.KeyValues))
+	for key := range f.KeyValues {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		pair := key + "\x00" + f.KeyValues[key] + "\x00"
+		binary.Write(&kv, binary.LittleEndian, uint32(len(pair)))
+		kv.WriteString(pair)
+		for kv.Len()%4 != 0 {
+			kv.WriteByte(0)
+		}
+	}
+
+	header := ktxHeader{
+		Identifier:           ktxIdentifier,
+		Endianness:           ktxEndianness,
+		GLTypeSize:           1,
+		GLInternalFormat:     f.Format,
+		GLBaseInternalFormat: 0x1907, // GL_RGB
+		PixelWidth:           uint32(f.Width),
+		PixelHeight:          uint32(f.Height),
+		NumberOfFaces:        1,
+		NumberOfMipmapLevels: uint32(len(f.Levels)),
+		BytesOfKeyValueData:  uint32(kv.Len()),
+	}
+	if err := binary.Write(out, binary.LittleEndian, &header); err != nil {
+		return err
+	}
+	out.Write(kv.Bytes())
+	for _, data := range f.Levels {
+		binary.Write(out, binary.LittleEndian, uint32(len(data)))
+		out.Write(data)
+		for pad := 3 - (len(data)+3)%4; pad > 0; pad-- {
+			out.WriteByte(0)
+		}
+	}
+	return out.Flush()
+}
@@ -0,0 +1,56 @@
+package etc1
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"testing"
+)
+
+// Offsets of header fields in a KTX file
+const (
+	offsetFormat   = 28
+	offsetKeyValue = 60
+	offsetLevel0   = 64
+)
+
+func TestReadKTX(t *testing.T) {
+	var buf bytes.Buffer
+	f := EncodeFile(image.NewNRGBA(image.Rect(0, 0, 16, 8)), true)
+	if err := WriteKTX(&buf, f); err != nil {
+		t.Fatal(err)
+	}
+	valid := buf.Bytes()
+	if g, err := ReadKTX(bytes.NewReader(valid)); err != nil {
+		t.Fatal(err)
+	} else if g.Width != 16 || g.Height != 8 || len(g.Levels) != len(f.Levels) {
+		t.Fatalf("read %dx%d with %d levels, wrote 16x8 with %d", g.Width, g.Height, len(g.Levels), len(f.Levels))
+	}
+
+	tests := []struct {
+		name   string
+		offset int
+		value  uint32
+	}{
+		{"huge key/value data", offsetKeyValue, 1 << 31},
+		{"huge level", offsetLevel0 + int(binary.LittleEndian.Uint32(valid[offsetKeyValue:])), 1 << 31},
+		{"short level", offsetLevel0 + int(binary.LittleEndian.Uint32(valid[offsetKeyValue:])), 8},
+		{"too many levels", offsetKeyValue - 4, 32},
+		{"huge width", offsetFormat + 8, 1 << 30},
+	}
+	for _, test := range tests {
+		data := append([]byte(nil), valid...)
+		binary.LittleEndian.PutUint32(data[test.offset:], test.value)
+		if _, err := ReadKTX(bytes.NewReader(data)); err == nil {
+			t.Errorf("%s: read without error", test.name)
+		}
+	}
+
+	data := append([]byte(nil), valid...)
+	binary.LittleEndian.PutUint32(data[offsetFormat:], 0x9274)
+	if _, err := ReadKTX(bytes.NewReader(data)); err == nil {
+		t.Error("ETC2: read without error")
+	} else if _, ok := err.(*UnsupportedError); !ok {
+		t.Errorf("ETC2: %s, want an *UnsupportedError", err)
+	}
+}
@@ -0,0 +1,93 @@
+package etc1
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+var pkmMagic = []byte("PKM 10")
+
+// PKM data type of ETC1 without mipmaps, the only one of version 1.0
+const pkmETC1 = 0
+
+type pkmHeader struct {
+	Magic          [6]byte
+	DataType       uint16
+	ExtendedWidth  uint16 // rounded up to multiples of 4
+	ExtendedHeight uint16
+	Width          uint16
+	Height         uint16
+}
+
+// Read a PKM file, as written by etc1tool. PKM files have no mip levels.
+func ReadPKM(r io.Reader) (*File, error) {
+	var header pkmHeader
+	if err := binary.Read(r, binary.BigEndian, &header); err != nil {
+		return nil, fmt.Errorf("fail to read PKM header: %s", err)
+	}
+	if !bytes.Equal(header.Magic[:4], pkmMagic[:4]) {
+		return nil, errors.New("fail to read PKM: not a PKM file")
+	}
+	if header.Magic[4] != '1' {
+		return nil, fmt.Errorf("fail to read PKM: unsupported version %s, only ETC1 PKM 10 is supported", header.Magic[4:])
+	}
+	if header.DataType != pkmETC1 {
+		return nil, &UnsupportedError{Container: "PKM", Format: uint32(header.DataType)}
+	}
+	w, h := int(header.Width), int(header.Height)
+	if w == 0 || h == 0 {
+		return nil, errors.New("fail to read PKM: empty image")
+	}
+	if w > maxSize || h > maxSize {
+		return nil, fmt.Errorf("fail to read PKM: %dx%d is larger than %d pixels", w, h, maxSize)
+	}
+	if int(header.ExtendedWidth) != (w+3)&^3 || int(header.ExtendedHeight) != (h+3)&^3 {
+		return nil, fmt.Errorf("fail to read PKM: extended size %dx%d does not match %dx%d", header.ExtendedWidth, header.ExtendedHeight, w, h)
+	}
+	data := make([]byte, EncodedSize(w, h))
+	if _, err := io.ReadFull(r, data); err != nil {
+		return nil, fmt.Errorf("fail to read PKM data: %s", err)
+	}
+	return &File{Format: GL_ETC1_RGB8_OES, Width: w, Height: h, Levels: [][]byte{data}}, nil
+}
+
+// Write the first level of f as a PKM file
+func WritePKM(w io.Writer, f *File) error {
+	if !f.IsETC1() || len(f.Levels) == 0 {
+		return errors.New("fail to write PKM: need ETC1 data")
+	}
+	header := pkmHeader{
+		DataType:       pkmETC1,
+		ExtendedWidth:  uint16((f.Width + 3) &^ 3),
+		ExtendedHeight: uint16((f.Height + 3) &^ 3),
+		Width:          uint16(f.Width),
+		Height:         uint16(f.Height),
+	}
+	copy(header.Magic[:], pkmMagic)
+	out := bufio.NewWriter(w)
+	if err := binary.Write(out, binary.BigEndian, &header); err != nil {
+		return err
+	}
+	out.Write(f.Levels[0])
+	return out.Flush()
+}
+
+// Read a KTX or PKM file, telling them apart by their magic bytes
+func Read(r io.Reader) (*File, error) {
+	in := bufio.NewReader(r)
+	magic, err := in.Peek(4)
+	if err != nil {
+		return nil, fmt.Errorf("fail to read compressed texture: %s", err)
+	}
+	switch {
+	case bytes.Equal(magic, ktxIdentifier[:4]):
+		return ReadKTX(in)
+	case bytes.Equal(magic, pkmMagic[:4]):
+		return ReadPKM(in)
+	}
+	return nil, errors.New("fail to read compressed texture: neither KTX nor PKM")
+}
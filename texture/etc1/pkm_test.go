@@ -0,0 +1,67 @@
+package etc1
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"testing"
+)
+
+// Offsets of header fields in a PKM file
+const (
+	offsetDataType       = 6
+	offsetExtendedWidth  = 8
+	offsetExtendedHeight = 10
+	offsetWidth          = 12
+	offsetHeight         = 14
+)
+
+func TestReadPKM(t *testing.T) {
+	var buf bytes.Buffer
+	f := EncodeFile(image.NewNRGBA(image.Rect(0, 0, 10, 6)), false)
+	if err := WritePKM(&buf, f); err != nil {
+		t.Fatal(err)
+	}
+	valid := buf.Bytes()
+	if g, err := Read(bytes.NewReader(valid)); err != nil {
+		t.Fatal(err)
+	} else if g.Width != 10 || g.Height != 6 || len(g.Levels) != 1 || !bytes.Equal(g.Levels[0], f.Levels[0]) {
+		t.Fatalf("read %dx%d with %d levels, wrote 10x6 with 1", g.Width, g.Height, len(g.Levels))
+	}
+
+	tests := []struct {
+		name   string
+		offset int
+		value  uint16
+	}{
+		{"huge extended width", offsetExtendedWidth, 0xfffc},
+		{"huge extended height", offsetExtendedHeight, 0xfffc},
+		{"small extended width", offsetExtendedWidth, 8},
+		{"unaligned extended height", offsetExtendedHeight, 6},
+		{"huge size", offsetWidth, 0xfffc},
+		{"empty", offsetHeight, 0},
+	}
+	for _, test := range tests {
+		data := append([]byte(nil), valid...)
+		binary.BigEndian.PutUint16(data[test.offset:], test.value)
+		if _, err := ReadPKM(bytes.NewReader(data)); err == nil {
+			t.Errorf("%s: read without error", test.name)
+		}
+	}
+
+	// a consistent header that is larger than the data
+	data := append([]byte(nil), valid...)
+	binary.BigEndian.PutUint16(data[offsetExtendedWidth:], 16)
+	binary.BigEndian.PutUint16(data[offsetWidth:], 16)
+	if _, err := ReadPKM(bytes.NewReader(data)); err == nil {
+		t.Error("short data: read without error")
+	}
+
+	data = append([]byte(nil), valid...)
+	binary.BigEndian.PutUint16(data[offsetDataType:], 1)
+	if _, err := ReadPKM(bytes.NewReader(data)); err == nil {
+		t.Error("ETC2: read without error")
+	} else if _, ok := err.(*UnsupportedError); !ok {
+		t.Errorf("ETC2: %s, want an *UnsupportedError", err)
+	}
+}
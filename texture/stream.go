@@ -0,0 +1,305 @@
+package texture
+
+import (
+	"io"
+	"sync"
+	"time"
+
+	gl "github.com/FEEDFACE-COM/piglet/gles2"
+	"github.com/FEEDFACE-COM/piglet/glx"
+)
+
+// Formats of streamed frames
+type StreamFormat int
+
+const (
+	StreamRGBA   StreamFormat = iota // one plane of 4 bytes per pixel
+	StreamYUV420                     // planar Y, U and V, with U and V at half width and height
+)
+
+func (f StreamFormat) String() string {
+	if f == StreamYUV420 {
+		return "YUV420"
+	}
+	return "RGBA"
+}
+
+// Return the size of plane i of a w*h frame in format f
+func (f StreamFormat) planeSize(i, w, h int) (int, int) {
+	if f == StreamYUV420 && i > 0 {
+		return (w + 1) / 2, (h + 1) / 2
+	}
+	return w, h
+}
+
+func (f StreamFormat) planes() int {
+	if f == StreamYUV420 {
+		return 3
+	}
+	return 1
+}
+
+// A Frame is one buffer of a Stream's ring. Planes are preallocated at the
+// stream's size, with tightly packed rows, first row at the top.
+type Frame struct {
+	Planes [][]byte      // RGBA, or Y, U and V
+	Time   time.Duration // presentation time, if the source has one
+}
+
+// A FrameSource produces frames, eg. a video decoder or a network stream
+type FrameSource interface {
+	// Fill f with the next frame, blocking until it is available. Return
+	// io.EOF at the end of the stream.
+	NextFrame(f *Frame) error
+}
+
+// Upload statistics of a Stream
+type StreamStats struct {
+	Frames  int           // uploaded
+	Dropped int           // replaced by newer frames before upload
+	Last    time.Duration // time spent in TexSubImage2D for the last frame
+	Total   time.Duration // over all frames
+}
+
+// Average upload time per frame
+func (s StreamStats) Average() time.Duration {
+	if s.Frames == 0 {
+		return 0
+	}
+	return s.Total / time.Duration(s.Frames)
+}
+
+// A Stream is a texture for content that changes every frame, like video.
+// Its textures are allocated once and updated with TexSubImage2D. Producers
+// fill frames from a ring of preallocated buffers on any goroutine, and
+// Update uploads the newest one on the render thread, dropping older ones.
+type Stream struct {
+	Format   StreamFormat
+	Width    int
+	Height   int
+	Textures []glx.Texture // RGBA, or Y, U and V as LUMINANCE
+
+	free  chan *Frame
+	ready chan *Frame
+	done  chan struct{}
+	once  sync.Once
+
+	mu    sync.Mutex
+	stats StreamStats
+}
+
+// Create a stream of w*h frames with a ring of buffers frames, at least 2
+func NewStream(format StreamFormat, w, h, buffers int) *Stream {
+	if buffers < 2 {
+		buffers = 2
+	}
+	s := &Stream{
+		Format: format,
+		Width:  w,
+		Height: h,
+		free:   make(chan *Frame, buffers),
+		ready:  make(chan *Frame, buffers),
+		done:   make(chan struct{}),
+	}
+	for n := 0; n < buffers; n++ {
+		f := &Frame{}
+		for i := 0; i < format.planes(); i++ {
+			pw, ph := format.planeSize(i, w, h)
+			bpp := 1
+			if format == StreamRGBA {
+				bpp = 4
+			}
+			f.Planes = append(f.Planes, make([]byte, pw*ph*bpp))
+		}
+		s.free <- f
+	}
+	for i := 0; i < format.planes(); i++ {
+		pw, ph := format.planeSize(i, w, h)
+		glformat := glx.Luminance
+		if format == StreamRGBA {
+			glformat = glx.RGBA
+		}
+		tex := glx.NewTexture(glx.Texture2D)
+		tex.SetImage(0, glformat, pw, ph, glx.UnsignedByte, nil)
+		tex.SetFilter(glx.Linear, glx.Linear)
+		tex.SetWrap(glx.ClampToEdge, glx.ClampToEdge)
+		s.Textures = append(s.Textures, tex)
+	}
+	return s
+}
+
+// Take a free frame to fill, blocking until one is available. Return nil
+// once the stream is closed.
+func (s *Stream) Frame() *Frame {
+	select {
+	case f := <-s.free:
+		return f
+	case <-s.done:
+		return nil
+	}
+}
+
+// Queue a filled frame for upload
+func (s *Stream) Submit(f *Frame) {
+	s.ready <- f
+}
+
+// Return a frame to the ring without uploading it
+func (s *Stream) Release(f *Frame) {
+	s.free <- f
+}
+
+// Fill frames from src until it ends, fails or the stream is closed.
+// Returns nil at the end of the stream. Run it on its own goroutine.
+func (s *Stream) Run(src FrameSource) error {
+	for {
+		f := s.Frame()
+		if f == nil {
+			return nil
+		}
+		if err := src.NextFrame(f); err != nil {
+			s.Release(f)
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		s.Submit(f)
+	}
+}
+
+// Upload the newest submitted frame, and report whether there was one. Call
+// once per frame, on the render thread.
+func (s *Stream) Update() bool {
+	var latest *Frame
+	dropped := 0
+drain:
+	for {
+		select {
+		case f := <-s.ready:
+			if latest != nil {
+				s.free <- latest
+				dropped++
+			}
+			latest = f
+		default:
+			break drain
+		}
+	}
+	if latest == nil {
+		return false
+	}
+	elapsed := s.Upload(latest)
+	s.free <- latest
+
+	s.mu.Lock()
+	s.stats.Dropped += dropped
+	s.stats.Frames++
+	s.stats.Last = elapsed
+	s.stats.Total += elapsed
+	s.mu.Unlock()
+	return true
+}
+
+// Upload f to the textures right away, and return the time it took. GL
+// copies the data before returning, so f can be reused afterwards. The
+// time is that of the driver call, which may return before the GPU is done.
+func (s *Stream) Upload(f *Frame) time.Duration {
+	start := time.Now()
+	gl.PixelStorei(gl.UNPACK_ALIGNMENT, 1)
+	for i, tex := range s.Textures {
+		pw, ph := s.Format.planeSize(i, s.Width, s.Height)
+		if s.Format == StreamRGBA {
+			tex.SetSubImage(0, 0, 0, pw, ph, glx.RGBA, glx.UnsignedByte, f.Planes[i])
+		} else {
+			tex.SetSubImage(0, 0, 0, pw, ph, glx.Luminance, glx.UnsignedByte, f.Planes[i])
+		}
+	}
+	gl.PixelStorei(gl.UNPACK_ALIGNMENT, 4)
+	return time.Since(start)
+}
+
+// Return the upload statistics so far
+func (s *Stream) Stats() StreamStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.stats
+}
+
+// Bind the textures to units first, first+1, .. and leave the last active
+func (s *Stream) Bind(first int) {
+	for i, tex := range s.Textures {
+		tex.BindUnit(first + i)
+	}
+}
+
+// Point the samplers of program at units first, first+1, .., using the
+// names of YUVFragmentShader for YUV420 and "tex" for RGBA. The program
+// must be in use.
+func (s *Stream) SetSamplers(program *glx.Program, first int) error {
+	names := []string{"tex"}
+	if s.Format == StreamYUV420 {
+		names = []string{"texY", "texU", "texV"}
+	}
+	for i, name := range names {
+		if err := program.SetUniform(name, int32(first+i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Stop the stream, unblocking Frame and Run. The textures stay valid.
+func (s *Stream) Close() {
+	s.once.Do(func() { close(s.done) })
+}
+
+// Close the stream and delete its textures, on the render thread
+func (s *Stream) Delete() {
+	s.Close()
+	for i := range s.Textures {
+		s.Textures[i].Delete()
+	}
+}
+
+// Vertex shader for drawing a stream to a quad, with attributes position
+// and texcoord in [0,1], where texcoord 0,0 is the top left of the frame
+const StreamVertexShader = `
+attribute vec2 position;
+attribute vec2 texcoord;
+varying vec2 v_texcoord;
+void main() {
+	gl_Position = vec4(position, 0.0, 1.0);
+	v_texcoord = texcoord;
+}
+`
+
+// Fragment shader for RGBA streams, with sampler tex
+const RGBAFragmentShader = `
+precision mediump float;
+varying vec2 v_texcoord;
+uniform sampler2D tex;
+void main() {
+	gl_FragColor = texture2D(tex, v_texcoord);
+}
+`
+
+// Fragment shader for YUV420 streams, converting limited range BT.601, as
+// most video decoders produce, to RGB. Samplers are texY, texU and texV.
+const YUVFragmentShader = `
+precision mediump float;
+varying vec2 v_texcoord;
+uniform sampler2D texY;
+uniform sampler2D texU;
+uniform sampler2D texV;
+void main() {
+	float y = 1.1643 * (texture2D(texY, v_texcoord).r - 0.0625);
+	float u = texture2D(texU, v_texcoord).r - 0.5;
+	float v = texture2D(texV, v_texcoord).r - 0.5;
+	gl_FragColor = vec4(
+		y + 1.5958 * v,
+		y - 0.39173 * u - 0.81290 * v,
+		y + 2.017 * u,
+		1.0);
+}
+`
@@ -0,0 +1,89 @@
+// Package texture creates glx.Textures from images, converting them to
+// the formats GLES2 can upload.
+package texture
+
+import (
+	"image"
+
+	gl "github.com/FEEDFACE-COM/piglet/gles2"
+	"github.com/FEEDFACE-COM/piglet/glx"
+)
+
+// Options control how an image becomes a texture
+type Options struct {
+	Format      Format     // upload format, Auto to pick from the image
+	Premultiply bool       // premultiply colors by alpha
+	NoFlip      bool       // keep the first row at the top, instead of flipping it to GL orientation
+	Mipmap      bool       // generate mipmaps, and filter with them
+	Filter      glx.Filter // glx.Linear if zero; a mipmap filter minifies with Mipmap, and magnifies as Nearest or Linear
+	Wrap        glx.Wrap   // glx.ClampToEdge if zero
+}
+
+// Report whether textures of w*h pixels need resizing to power of two
+// sizes under opts. GLES2 only allows mipmaps and wrap modes other than
+// ClampToEdge for NPOT textures with GL_OES_texture_npot.
+func needsPOT(w, h int, opts Options) bool {
+	if isPOT(w) && isPOT(h) {
+		return false
+	}
+	if !opts.Mipmap && (opts.Wrap == 0 || opts.Wrap == glx.ClampToEdge) {
+		return false
+	}
+	return !gl.HasExtension("GL_OES_texture_npot")
+}
+
+// Convert img according to opts, ready to upload. Needs the current
+// context to check for GL_OES_texture_npot.
+func Prepare(img image.Image, opts Options) *Pixels {
+	pixels := Convert(img, opts.Format, opts.Premultiply)
+	if needsPOT(pixels.Width, pixels.Height, opts) {
+		pixels = pixels.Resize(nextPOT(pixels.Width), nextPOT(pixels.Height))
+	}
+	if !opts.NoFlip {
+		pixels.FlipY()
+	}
+	return pixels
+}
+
+// Create a new 2D texture from img
+func Load(img image.Image, opts Options) glx.Texture {
+	return Upload(Prepare(img, opts), opts)
+}
+
+// Create a new 2D texture from prepared pixels
+func Upload(pixels *Pixels, opts Options) glx.Texture {
+	format, xtype := pixels.GL()
+	tex := glx.NewTexture(glx.Texture2D)
+	gl.PixelStorei(gl.UNPACK_ALIGNMENT, 1)
+	tex.SetImage(0, format, pixels.Width, pixels.Height, xtype, pixels.packed())
+	gl.PixelStorei(gl.UNPACK_ALIGNMENT, 4)
+
+	min := opts.Filter
+	if min == 0 {
+		min = glx.Linear
+	}
+	// magnification never uses mipmaps
+	mag := glx.Linear
+	switch min {
+	case glx.Nearest, glx.NearestMipmapNearest, glx.NearestMipmapLinear:
+		mag = glx.Nearest
+	}
+	if opts.Mipmap {
+		tex.GenerateMipmap()
+		switch min {
+		case glx.Linear:
+			min = glx.LinearMipmapLinear
+		case glx.Nearest:
+			min = glx.NearestMipmapNearest
+		}
+	} else {
+		min = mag // a mipmap filter without mipmaps leaves the texture incomplete
+	}
+	tex.SetFilter(min, mag)
+	wrap := opts.Wrap
+	if wrap == 0 {
+		wrap = glx.ClampToEdge
+	}
+	tex.SetWrap(wrap, wrap)
+	return tex
+}